@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/anIcedAntFA/gohome/internal/commitcache"
+	"github.com/anIcedAntFA/gohome/internal/entity"
+	"github.com/anIcedAntFA/gohome/internal/git"
+)
+
+// repoCommits returns the parsed commits of the author in a repository
+// since the start of the period, newest first. The commit cache serves
+// repositories whose HEAD did not move; when HEAD moved forward only the
+// new commits are read from git.
+func repoCommits(ctx context.Context, deps *dependencies, repo repository) ([]entity.Commit, error) {
+	if deps.cache == nil {
+		logs, err := deps.gitClient.GetLogs(ctx, repo.path, deps.author, gitTime(deps.from))
+		if err != nil {
+			return nil, err
+		}
+		return plainCommits(parseLogs(repo, logs)), nil
+	}
+
+	head, err := deps.gitClient.Head(ctx, repo.path)
+	if err != nil {
+		return nil, err // No commits yet
+	}
+
+	rules := repo.parser.Fingerprint()
+	entry := deps.cache.Load(repo.path, deps.author)
+	switch {
+	case entry != nil && entry.Covers(rules, deps.from) && entry.Head == head:
+		return entry.Since(deps.from), nil
+
+	case entry != nil && entry.Covers(rules, deps.from) && deps.gitClient.IsAncestor(ctx, repo.path, entry.Head, head):
+		logs, err := deps.gitClient.GetLogsRange(ctx, repo.path, deps.author, gitTime(deps.from), entry.Head+".."+head)
+		if err != nil {
+			return nil, err
+		}
+		entry.Commits = append(parseLogs(repo, logs), entry.Commits...)
+		entry.Head = head
+		entry.Trim(deps.from) // Keep the entry to the period, not every commit since the first run
+
+	default:
+		// Rebased, new period or rules: read the whole period again
+		logs, err := deps.gitClient.GetLogsRange(ctx, repo.path, deps.author, gitTime(deps.from), head)
+		if err != nil {
+			return nil, err
+		}
+		entry = &commitcache.Entry{
+			Repo:    repo.path,
+			Author:  deps.author,
+			Rules:   rules,
+			Head:    head,
+			From:    deps.from,
+			Commits: parseLogs(repo, logs),
+		}
+	}
+
+	if err := deps.cache.Store(entry); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️ Warning: cannot update the commit cache: %v\n", err)
+	}
+	return entry.Since(deps.from), nil
+}
+
+// parseLogs parses the log entries of a repository, leaving out the
+// ignored ones.
+func parseLogs(repo repository, logs []git.LogEntry) []commitcache.Commit {
+	commits := make([]commitcache.Commit, 0, len(logs))
+	for _, entry := range logs {
+		if repo.parser.Ignored(entry.Subject) {
+			continue
+		}
+		commit := repo.parser.Parse(entry.Subject)
+		commit.Hash = entry.Hash
+		commit.Date = entry.Date
+		commits = append(commits, commitcache.Commit{Commit: commit, Committed: entry.Committed})
+	}
+	return commits
+}
+
+// plainCommits drops the cache details of parsed commits.
+func plainCommits(commits []commitcache.Commit) []entity.Commit {
+	plain := make([]entity.Commit, 0, len(commits))
+	for _, c := range commits {
+		plain = append(plain, c.Commit)
+	}
+	return plain
+}
+
+// gitTime formats a time for git log --since.
+func gitTime(t time.Time) string {
+	return fmt.Sprintf("@%d", t.Unix())
+}
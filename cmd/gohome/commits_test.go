@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anIcedAntFA/gohome/internal/commitcache"
+	"github.com/anIcedAntFA/gohome/internal/entity"
+	"github.com/anIcedAntFA/gohome/internal/git/gittest"
+	"github.com/anIcedAntFA/gohome/internal/parser"
+)
+
+// messages returns the messages of commits.
+func messages(commits []entity.Commit) []string {
+	out := make([]string, 0, len(commits))
+	for _, c := range commits {
+		out = append(out, c.Message)
+	}
+	return out
+}
+
+// fetches returns the log reads among the calls made to the fake since the
+// given number of calls.
+func fetches(fake *gittest.Fake, since int) []string {
+	var out []string
+	for _, call := range fake.Calls()[since:] {
+		if strings.HasPrefix(call, "GetLogsRange ") {
+			out = append(out, call)
+		}
+	}
+	return out
+}
+
+func TestRepoCommitsCache(t *testing.T) {
+	base := gittest.Start
+	fake := gittest.NewFake()
+	api := fake.Repo("/work/api")
+	first := api.Commit("tester", "feat: one", base)
+	api.Commit("someone", "docs: not mine", base.Add(time.Hour))
+	second := api.Commit("tester", "fix: two", base.Add(2*time.Hour))
+
+	deps := &dependencies{
+		gitClient: fake,
+		cache:     commitcache.New(t.TempDir()),
+		author:    "tester",
+		from:      base.Add(-time.Hour),
+	}
+	repo := repository{path: "/work/api", name: "api", parser: parser.NewService()}
+	read := func(wantFetches ...string) {
+		t.Helper()
+		calls := len(fake.Calls())
+		commits, err := repoCommits(context.Background(), deps, repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := fetches(fake, calls); !reflect.DeepEqual(got, wantFetches) {
+			t.Errorf("fetches = %q, want %q", got, wantFetches)
+		}
+		if got, want := messages(commits), readMessages(t, fake, deps); !reflect.DeepEqual(got, want) {
+			t.Errorf("commits = %q, want %q", got, want)
+		}
+	}
+
+	// First run reads the period, the second one nothing
+	read("GetLogsRange /work/api " + second)
+	read()
+
+	// New commits: only those are read
+	third := api.Commit("tester", "feat: three", base.Add(3*time.Hour))
+	read("GetLogsRange /work/api " + second + ".." + third)
+
+	// As the period moves on, the entry drops the commits before it
+	deps.from = base.Add(90 * time.Minute)
+	fourth := api.Commit("tester", "feat: four", base.Add(3*time.Hour+30*time.Minute))
+	read("GetLogsRange /work/api " + third + ".." + fourth)
+	if entry := deps.cache.Load("/work/api", "tester"); !entry.From.Equal(deps.from) || len(entry.Commits) != 3 {
+		t.Errorf("entry from %v with %d commits, want from %v with 3", entry.From, len(entry.Commits), deps.from)
+	}
+
+	// Rewritten history: the period is read again
+	api.Head = first
+	rewritten := api.Commit("tester", "fix: two, amended", base.Add(4*time.Hour))
+	read("GetLogsRange /work/api " + rewritten)
+
+	// A longer period is read again too
+	deps.from = base.Add(-24 * time.Hour)
+	read("GetLogsRange /work/api " + rewritten)
+
+	// Without the cache every run reads the period
+	deps.cache = nil
+	read("GetLogsRange /work/api")
+	read("GetLogsRange /work/api")
+}
+
+// readMessages returns the messages of the commits git would list for the
+// author in the period, to check the cache against.
+func readMessages(t *testing.T, fake *gittest.Fake, deps *dependencies) []string {
+	t.Helper()
+	logs, err := fake.GetLogs(context.Background(), "/work/api", deps.author, gitTime(deps.from))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := make([]string, 0, len(logs))
+	for _, entry := range logs {
+		out = append(out, parser.NewService().Parse(entry.Subject).Message)
+	}
+	return out
+}
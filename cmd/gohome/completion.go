@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/anIcedAntFA/gohome/internal/config"
+)
+
+// completionCommand describes the completion command.
+func completionCommand() *config.Command {
+	return &config.Command{
+		Name:    "completion",
+		Usage:   "gohome completion <bash|zsh|fish>",
+		Summary: "Generate a shell completion script.",
+		Examples: []string{
+			"source <(gohome completion bash)",
+			"gohome completion zsh > \"${fpath[1]}/_gohome\"",
+			"gohome completion fish > ~/.config/fish/completions/gohome.fish",
+		},
+	}
+}
+
+// runCompletion writes the completion script of the requested shell.
+func runCompletion(args []string) error {
+	cmd := completionCommand()
+	rest, err := config.ParseArgs(cmd, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 1 {
+		config.PrintUsage(os.Stderr, cmd)
+		return fmt.Errorf("expected exactly one shell: bash, zsh or fish")
+	}
+
+	switch rest[0] {
+	case "bash":
+		writeBashCompletion(os.Stdout)
+	case "zsh":
+		writeZshCompletion(os.Stdout)
+	case "fish":
+		writeFishCompletion(os.Stdout)
+	default:
+		return fmt.Errorf("unsupported shell %q (use bash, zsh or fish)", rest[0])
+	}
+	return nil
+}
+
+// commandNames returns the names of all commands.
+func commandNames() []string {
+	cmds := commands()
+	names := make([]string, 0, len(cmds))
+	for _, cmd := range cmds {
+		names = append(names, cmd.name)
+	}
+	return names
+}
+
+// writeBashCompletion writes a bash completion script.
+func writeBashCompletion(w io.Writer) {
+	fmt.Fprintln(w, "# bash completion for gohome")
+	fmt.Fprintln(w, "_gohome() {")
+	fmt.Fprintln(w, `    local cur="${COMP_WORDS[COMP_CWORD]}" cmd="report" words=""`)
+	fmt.Fprintln(w, `    case "${COMP_WORDS[1]}" in`)
+	for _, cmd := range commands() {
+		fmt.Fprintf(w, "        %s) cmd=%s ;;\n", cmd.name, cmd.name)
+	}
+	fmt.Fprintln(w, "    esac")
+	fmt.Fprintln(w, `    case "$cmd" in`)
+	for _, cmd := range commands() {
+		fmt.Fprintf(w, "        %s) words=%q ;;\n", cmd.name, strings.Join(config.FlagNames(cmd.spec()), " "))
+	}
+	fmt.Fprintln(w, "    esac")
+	fmt.Fprintln(w, `    if [[ $COMP_CWORD -eq 1 && "$cur" != -* ]]; then`)
+	fmt.Fprintf(w, "        words=%q\n", strings.Join(commandNames(), " "))
+	fmt.Fprintln(w, "    fi")
+	fmt.Fprintln(w, `    COMPREPLY=($(compgen -W "$words" -- "$cur"))`)
+	fmt.Fprintln(w, "}")
+	fmt.Fprintln(w, "complete -F _gohome gohome")
+}
+
+// writeZshCompletion writes a zsh completion script.
+func writeZshCompletion(w io.Writer) {
+	fmt.Fprintln(w, "#compdef gohome")
+	fmt.Fprintln(w, "_gohome() {")
+	fmt.Fprintln(w, `    local cmd="report"`)
+	fmt.Fprintln(w, "    local -a commands")
+	fmt.Fprintln(w, "    commands=(")
+	for _, cmd := range commands() {
+		fmt.Fprintf(w, "        '%s:%s'\n", cmd.name, strings.ReplaceAll(cmd.summary, "'", ""))
+	}
+	fmt.Fprintln(w, "    )")
+	fmt.Fprintln(w, `    if (( CURRENT == 2 )) && [[ "$words[2]" != -* ]]; then`)
+	fmt.Fprintln(w, "        _describe 'command' commands")
+	fmt.Fprintln(w, "        return")
+	fmt.Fprintln(w, "    fi")
+	fmt.Fprintln(w, `    (( ${commands[(I)$words[2]:*]} )) && cmd="$words[2]"`)
+	fmt.Fprintln(w, `    case "$cmd" in`)
+	for _, cmd := range commands() {
+		fmt.Fprintf(w, "        %s) compadd -- %s ;;\n", cmd.name, strings.Join(config.FlagNames(cmd.spec()), " "))
+	}
+	fmt.Fprintln(w, "    esac")
+	fmt.Fprintln(w, "}")
+	fmt.Fprintln(w, `_gohome "$@"`)
+}
+
+// writeFishCompletion writes a fish completion script.
+func writeFishCompletion(w io.Writer) {
+	fmt.Fprintln(w, "# fish completion for gohome")
+	fmt.Fprintln(w, "complete -c gohome -f")
+	names := strings.Join(commandNames(), " ")
+	for _, cmd := range commands() {
+		fmt.Fprintf(w, "complete -c gohome -n 'not __fish_seen_subcommand_from %s' -a %s -d '%s'\n",
+			names, cmd.name, strings.ReplaceAll(cmd.summary, "'", ""))
+	}
+	for _, cmd := range commands() {
+		condition := "__fish_seen_subcommand_from " + cmd.name
+		if cmd.name == "report" {
+			condition = "not __fish_seen_subcommand_from " + names + "; or __fish_seen_subcommand_from report"
+		}
+		for _, name := range config.FlagNames(cmd.spec()) {
+			fmt.Fprintf(w, "complete -c gohome -n '%s' -l %s\n", condition, strings.TrimPrefix(name, "--"))
+		}
+	}
+}
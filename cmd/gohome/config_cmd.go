@@ -0,0 +1,417 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/anIcedAntFA/gohome/internal/config"
+)
+
+// configCommand describes the config command.
+func configCommand() *config.Command {
+	return &config.Command{
+		Name:    "config",
+		Usage:   "gohome config <command> [args]",
+		Summary: "Inspect and edit the configuration file.",
+		Config:  true,
+		Examples: []string{
+			"gohome config get format",
+			"gohome config set days 3",
+			"gohome config set email.to alice@example.com,bob@example.com",
+			"gohome config unset webhooks.team",
+			"gohome config list --show-origin -w 1",
+		},
+		Help: func(w io.Writer) {
+			fmt.Fprintln(w, "   path\tPrint the location of the config file")
+			fmt.Fprintln(w, "   show [--show-secrets]\tPrint the effective configuration as JSON")
+			fmt.Fprintln(w, "   get <key>\tPrint the effective value of a setting")
+			fmt.Fprintln(w, "   set <key> <value>\tChange a setting in the config file")
+			fmt.Fprintln(w, "   unset <key>\tRemove a setting from the config file")
+			fmt.Fprintln(w, "   list [--show-origin] [--show-secrets]\tList the effective settings, accepts the report flags")
+			fmt.Fprintln(w, "   edit\tOpen the config file in $VISUAL or $EDITOR")
+			fmt.Fprintln(w, "   validate\tReport unknown keys and invalid values")
+			fmt.Fprintln(w, "   schema\tPrint the JSON Schema of the config file")
+			fmt.Fprintln(w, "\t")
+		},
+	}
+}
+
+// configListCommand describes the config list command.
+func configListCommand(showOrigin, showSecrets *bool) *config.Command {
+	return &config.Command{
+		Name:     "config list",
+		Usage:    "gohome config list [--show-origin] [--show-secrets] [flags]",
+		Summary:  "List the effective settings.",
+		Examples: []string{"gohome config list --show-origin -d 3"},
+		Groups:   config.PeriodFlags | config.OutputFlags,
+		Flags: func(fs *flag.FlagSet) {
+			fs.BoolVar(showOrigin, "show-origin", false, "")
+			fs.BoolVar(showSecrets, "show-secrets", false, "")
+		},
+		Help: func(w io.Writer) {
+			fmt.Fprintln(w, "       --show-origin\tShow where each value comes from: flag, env, profile, file or default")
+			fmt.Fprintln(w, "       --show-secrets\tShow the email password and webhook URLs and headers instead of masking them")
+		},
+	}
+}
+
+// configShowCommand describes the config show command.
+func configShowCommand(showSecrets *bool) *config.Command {
+	return &config.Command{
+		Name:     "config show",
+		Usage:    "gohome config show [--show-secrets]",
+		Summary:  "Print the effective configuration as JSON.",
+		Examples: []string{"gohome config show --show-secrets"},
+		Config:   true,
+		Flags: func(fs *flag.FlagSet) {
+			fs.BoolVar(showSecrets, "show-secrets", false, "")
+		},
+		Help: func(w io.Writer) {
+			fmt.Fprintln(w, "       --show-secrets\tShow the email password and webhook URLs and headers instead of masking them")
+		},
+	}
+}
+
+// runConfig dispatches the config subcommands.
+func runConfig(args []string) error {
+	cmd := configCommand()
+	if len(args) == 0 {
+		config.PrintUsage(os.Stderr, cmd)
+		return nil
+	}
+
+	rest, err := config.ParseArgs(cmd, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) == 0 {
+		config.PrintUsage(os.Stderr, cmd)
+		return nil
+	}
+	switch rest[0] {
+	case "list":
+		return runConfigList(rest[1:])
+	case "show":
+		return runConfigShow(rest[1:])
+	}
+
+	sub, params := rest[0], rest[1:]
+	wantArgs := map[string]int{"path": 0, "get": 1, "set": 2, "unset": 1, "edit": 0, "validate": 0, "schema": 0}
+	n, ok := wantArgs[sub]
+	if !ok {
+		return fmt.Errorf("unknown config command %q (see 'gohome config --help')", sub)
+	}
+	if len(params) != n {
+		return fmt.Errorf("config %s expects %d argument(s), got %d (see 'gohome config --help')", sub, n, len(params))
+	}
+
+	switch sub {
+	case "path":
+		fmt.Println(config.GetConfigPath())
+		return nil
+	case "get":
+		return configGet(params[0])
+	case "set":
+		return configSet(params[0], params[1])
+	case "unset":
+		return configUnset(params[0])
+	case "edit":
+		return configEdit()
+	case "schema":
+		return configSchema()
+	default:
+		return configValidate()
+	}
+}
+
+// runConfigShow prints the effective configuration as JSON, secrets masked.
+func runConfigShow(args []string) error {
+	var showSecrets bool
+	rest, err := config.ParseArgs(configShowCommand(&showSecrets), args)
+	if err != nil {
+		return err
+	}
+	if len(rest) > 0 {
+		return fmt.Errorf("config show expects 0 argument(s), got %d (see 'gohome config --help')", len(rest))
+	}
+
+	cfg, _, err := config.Load(configCommand(), nil)
+	if err != nil {
+		return err
+	}
+	settings, err := effectiveSettings(cfg, showSecrets)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(settings.Root())
+}
+
+// configGet prints the effective value of a key.
+func configGet(key string) error {
+	if _, err := config.KeyType(key); err != nil {
+		return err
+	}
+
+	cfg, _, err := config.Load(configCommand(), nil)
+	if err != nil {
+		return err
+	}
+
+	settings, err := effectiveSettings(cfg, true)
+	if err != nil {
+		return err
+	}
+	value, ok := settings.Get(key)
+	if !ok || value == nil {
+		return fmt.Errorf("%s is not set", key)
+	}
+
+	fmt.Println(formatSetting(value, true))
+	return nil
+}
+
+// configSet changes a key in the config file, keeping the other settings as they are.
+func configSet(key, value string) error {
+	file, err := config.OpenFile()
+	if err != nil {
+		return err
+	}
+
+	if err := file.Set(key, value); err != nil {
+		return err
+	}
+
+	// Refuse values that are invalid, but not problems elsewhere in the file
+	for _, p := range file.Validate() {
+		if p.Key == key || strings.HasPrefix(p.Key, key+".") || strings.HasPrefix(key, p.Key+".") {
+			return fmt.Errorf("invalid value for %s: %s", key, p.Message)
+		}
+	}
+
+	if err := file.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	warnRewritten(file)
+	current, _ := file.Get(key)
+	fmt.Fprintf(os.Stderr, "✅ %s = %s\n", key, formatSetting(current, false))
+	if name := config.EnvName(key); os.Getenv(name) != "" {
+		fmt.Fprintf(os.Stderr, "⚠️ Warning: %s is set and overrides this setting\n", name)
+	}
+	return nil
+}
+
+// warnRewritten tells the user when comments could not be kept.
+func warnRewritten(file *config.File) {
+	if file.Rewritten() {
+		fmt.Fprintf(os.Stderr, "⚠️ Warning: %s could not be edited in place, the file was rewritten without its comments\n", file.Path)
+	}
+}
+
+// configUnset removes a key from the config file. Unknown keys can be
+// removed too, which is how misspelled settings get cleaned up.
+func configUnset(key string) error {
+	file, err := config.OpenFile()
+	if err != nil {
+		return err
+	}
+
+	removed, err := file.Unset(key)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		if _, err := config.KeyType(key); err != nil {
+			return err
+		}
+		return fmt.Errorf("%s is not set in %s", key, file.Path)
+	}
+	warnRewritten(file)
+
+	if err := file.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "✅ Removed %s\n", key)
+	return nil
+}
+
+// runConfigList prints the effective settings, optionally with their origin.
+func runConfigList(args []string) error {
+	var showOrigin, showSecrets bool
+	cfg, _, err := config.Load(configListCommand(&showOrigin, &showSecrets), args)
+	if err != nil {
+		return err
+	}
+
+	settings, err := effectiveSettings(cfg, showSecrets)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	for _, key := range settingKeys(settings, "") {
+		value, _ := settings.Get(key)
+		if showOrigin {
+			fmt.Fprintf(w, "%s\t%s=%s\n", cfg.Origin(key), key, formatSetting(value, false))
+		} else {
+			fmt.Fprintf(w, "%s=%s\n", key, formatSetting(value, false))
+		}
+	}
+	return w.Flush()
+}
+
+// configEdit opens the config file in the user's editor and validates it afterwards.
+func configEdit() error {
+	path := config.GetConfigPath()
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		// An empty JSON object, other formats start as an empty file
+		var content []byte
+		if strings.EqualFold(filepath.Ext(path), ".json") {
+			content = []byte("{\n}\n")
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			return fmt.Errorf("failed to create config: %w", err)
+		}
+		if err := os.WriteFile(path, content, 0o600); err != nil {
+			return fmt.Errorf("failed to create config: %w", err)
+		}
+	}
+
+	editor := strings.Fields(editorCommand())
+	// #nosec G204 -- the editor is chosen by the user running the tool
+	cmd := exec.Command(editor[0], append(editor[1:], path)...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("editor failed: %w", err)
+	}
+
+	return configValidate()
+}
+
+// editorCommand returns the user's editor.
+func editorCommand() string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if editor := strings.TrimSpace(os.Getenv(name)); editor != "" {
+			return editor
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+	return "vi"
+}
+
+// configValidate reports the problems of the config file.
+func configValidate() error {
+	file, err := config.OpenFile()
+	if err != nil {
+		return err
+	}
+
+	problems := file.Validate()
+	errs := file.Errors(problems)
+	for _, p := range problems {
+		note := ""
+		if p.Unknown && len(errs) < len(problems) {
+			note = " (ignored, strict is off)"
+		}
+		if p.Line > 0 {
+			fmt.Fprintf(os.Stderr, "%s:%d:%d: %s: %s%s\n", file.Path, p.Line, p.Column, p.Key, p.Message, note)
+		} else {
+			fmt.Fprintf(os.Stderr, "%s: %s: %s%s\n", file.Path, p.Key, p.Message, note)
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%d problem(s) found in %s", len(errs), file.Path)
+	}
+	fmt.Fprintf(os.Stderr, "✅ %s is valid\n", file.Path)
+	return nil
+}
+
+// configSchema prints the JSON Schema of the config file.
+func configSchema() error {
+	schema, err := config.Schema()
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(schema)
+	return err
+}
+
+// effectiveSettings converts the merged configuration into a File
+// so its values can be looked up by key. Secrets are masked unless shown.
+func effectiveSettings(cfg *config.AppConfig, showSecrets bool) (*config.File, error) {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := config.ParseSettings(data)
+	if err != nil || showSecrets {
+		return settings, err
+	}
+	return settings, settings.MaskSecrets()
+}
+
+// settingKeys returns the keys of all scalar settings and lists of scalars, depth first.
+func settingKeys(settings *config.File, prefix string) []string {
+	value, _ := settings.Get(prefix)
+	if prefix == "" {
+		value = settings.Root()
+	}
+
+	children := config.Children(value)
+	if list, ok := value.([]any); ok && !slices.ContainsFunc(list, func(item any) bool { return config.Children(item) != nil }) {
+		children = nil
+	}
+	if children == nil {
+		if value == nil {
+			return nil
+		}
+		return []string{prefix}
+	}
+
+	var keys []string
+	for _, child := range children {
+		key := child
+		if prefix != "" {
+			key = prefix + "." + child
+		}
+		keys = append(keys, settingKeys(settings, key)...)
+	}
+	return keys
+}
+
+// formatSetting prints strings bare and everything else as JSON.
+func formatSetting(value any, indent bool) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+
+	var data []byte
+	var err error
+	if indent {
+		data, err = json.MarshalIndent(value, "", "  ")
+	} else {
+		data, err = json.Marshal(value)
+	}
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeConfig writes the config file of an isolated run and returns its path.
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(os.Getenv("XDG_CONFIG_HOME"), "gohome", "config.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestConfigMasksSecrets(t *testing.T) {
+	isolate(t)
+	writeConfig(t, `{
+  "email": {"host": "smtp.example.com", "from": "me@example.com", "to": ["team@example.com"], "password": "hunter2"},
+  "webhooks": {"team": {"type": "slack", "url": "https://hooks.slack.com/services/T0/B0/token1", "headers": {"Authorization": "Bearer token2"}}},
+  "profiles": {"work": {"webhooks": {"ci": {"type": "generic", "url": "https://ci.example.com/hook?token=token3"}}}}
+}`)
+	secrets := []string{"hunter2", "token1", "token2", "token3"}
+
+	for _, args := range [][]string{{"config", "list"}, {"config", "show"}} {
+		out := gohome(t, args...)
+		for _, secret := range secrets {
+			if strings.Contains(out, secret) {
+				t.Errorf("gohome %s shows %s:\n%s", strings.Join(args, " "), secret, out)
+			}
+		}
+		if !strings.Contains(out, "https://hooks.slack.com/********") {
+			t.Errorf("gohome %s does not keep the webhook host:\n%s", strings.Join(args, " "), out)
+		}
+
+		out = gohome(t, append(args, "--show-secrets")...)
+		for _, secret := range secrets {
+			if !strings.Contains(out, secret) {
+				t.Errorf("gohome %s --show-secrets hides %s:\n%s", strings.Join(args, " "), secret, out)
+			}
+		}
+	}
+
+	// Asking for a key prints it as it is
+	if out := gohome(t, "config", "get", "email.password"); out != "hunter2\n" {
+		t.Errorf("config get email.password = %q", out)
+	}
+}
+
+func TestSaveSampleTasks(t *testing.T) {
+	tasks := func(t *testing.T, path string) []any {
+		t.Helper()
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var file struct {
+			Tasks []any `json:"tasks"`
+		}
+		if err := json.Unmarshal(data, &file); err != nil {
+			t.Fatal(err)
+		}
+		return file.Tasks
+	}
+
+	t.Run("new file", func(t *testing.T) {
+		isolate(t)
+		gohome(t, "--save")
+		path := filepath.Join(os.Getenv("XDG_CONFIG_HOME"), "gohome", "config.json")
+		if got := tasks(t, path); len(got) == 0 {
+			t.Error("a new file gets no sample tasks")
+		}
+	})
+
+	t.Run("empty tasks", func(t *testing.T) {
+		isolate(t)
+		path := writeConfig(t, "{\n  \"tasks\": []\n}\n")
+		gohome(t, "--save", "-d", "2")
+		if got := tasks(t, path); got == nil || len(got) != 0 {
+			t.Errorf("tasks = %v, want the empty list kept", got)
+		}
+	})
+
+	t.Run("no tasks key", func(t *testing.T) {
+		isolate(t)
+		path := writeConfig(t, "{\n  \"author\": \"tester\"\n}\n")
+		gohome(t, "--save", "-d", "2")
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(data), "tasks") {
+			t.Errorf("tasks added to an existing file:\n%s", data)
+		}
+	})
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/anIcedAntFA/gohome/internal/config"
+	"github.com/anIcedAntFA/gohome/internal/entity"
+	"github.com/anIcedAntFA/gohome/internal/mail"
+	"github.com/anIcedAntFA/gohome/internal/renderer"
+	"github.com/anIcedAntFA/gohome/internal/spinner"
+	"github.com/anIcedAntFA/gohome/internal/webhook"
+)
+
+// handlePost renders the report for each requested webhook and sends it.
+// With --dry-run the payloads are printed instead. Every webhook is tried
+// even when an earlier one fails, and the number that succeeded is returned.
+func handlePost(cfg *config.AppConfig, report *entity.Report) (int, error) {
+	failed := 0
+
+	for _, name := range cfg.Post {
+		if err := postReport(cfg, name, report); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Failed to post to %s: %v\n", name, err)
+			failed++
+		}
+	}
+
+	posted := len(cfg.Post) - failed
+	if failed > 0 {
+		return posted, fmt.Errorf("%d of %d webhook(s) failed", failed, len(cfg.Post))
+	}
+	return posted, nil
+}
+
+// postReport delivers the report to a single named webhook.
+func postReport(cfg *config.AppConfig, name string, report *entity.Report) error {
+	hook, ok := cfg.Webhooks[name]
+	if !ok {
+		return fmt.Errorf("no webhook named %q in config", name)
+	}
+
+	dest := webhook.Destination{Name: name, Type: hook.Type, URL: hook.URL, Headers: hook.Headers}
+	if err := dest.Validate(); err != nil {
+		return err
+	}
+
+	format := hook.Format
+	if format == "" {
+		format = webhook.DefaultFormat(hook.Type)
+	}
+
+	var buf bytes.Buffer
+	if err := renderer.NewPrinter(printerConfig(cfg, format, false)).Render(&buf, report); err != nil {
+		return err
+	}
+
+	payloads, err := webhook.Payloads(dest, format, buf.String(), report)
+	if err != nil {
+		return err
+	}
+
+	if cfg.DryRun {
+		fmt.Printf("\n🧪 Dry run: POST %s (%s, %d request(s))\n", name, hook.Type, len(payloads))
+		for _, payload := range payloads {
+			fmt.Println(string(payload))
+		}
+		return nil
+	}
+
+	timeout := webhook.DefaultTimeout
+	if hook.Timeout > 0 {
+		timeout = time.Duration(hook.Timeout) * time.Second
+	}
+	retries := webhook.DefaultRetries
+	if hook.Retries != 0 {
+		retries = max(hook.Retries, 0)
+	}
+
+	sp := spinner.New(fmt.Sprintf("📤 Posting to %s...", name))
+	sp.Start()
+	err = webhook.NewClient(timeout, retries).Post(context.Background(), dest, payloads)
+	sp.Stop()
+
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "✅ Report posted to %s\n", name)
+	return nil
+}
+
+// emailReport builds and delivers the report email.
+func emailReport(cfg *config.AppConfig, report *entity.Report) error {
+	ec := cfg.Email
+	if ec == nil || ec.Host == "" {
+		return fmt.Errorf("no email settings in config (see \"email\" in %s)", config.GetConfigPath())
+	}
+
+	// Plain-text part keeps the chosen text/table layout, other formats fall back to text
+	textFormat := cfg.OutputFmt
+	if textFormat != "table" {
+		textFormat = "text"
+	}
+
+	var text, html bytes.Buffer
+	if err := renderer.NewPrinter(printerConfig(cfg, textFormat, false)).Render(&text, report); err != nil {
+		return err
+	}
+	if err := renderer.NewPrinter(printerConfig(cfg, "html", false)).Render(&html, report); err != nil {
+		return err
+	}
+
+	subject, err := mail.Subject(ec.Subject, mail.SubjectData{
+		Author: report.Author,
+		Period: report.Period,
+		Date:   report.GeneratedAt.Format("2006-01-02"),
+	})
+	if err != nil {
+		return err
+	}
+
+	msg := &mail.Message{
+		From:    ec.From,
+		To:      ec.To,
+		Subject: subject,
+		Text:    text.String(),
+		HTML:    html.String(),
+		Date:    report.GeneratedAt,
+	}
+
+	if cfg.DryRun {
+		if err := msg.Validate(); err != nil {
+			return err
+		}
+		data, err := msg.Bytes()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("\n🧪 Dry run: email to %s via %s\n%s\n", strings.Join(ec.To, ", "), ec.Host, data)
+		return nil
+	}
+
+	security := ec.Security
+	if security == "" {
+		security = mail.SecurityStartTLS
+	}
+	timeout := 30 * time.Second
+	if ec.Timeout > 0 {
+		timeout = time.Duration(ec.Timeout) * time.Second
+	}
+
+	server := mail.Server{
+		Host:     ec.Host,
+		Port:     smtpPort(ec.Port, security),
+		Security: security,
+		Username: ec.Username,
+		Password: ec.Password,
+		Timeout:  timeout,
+	}
+
+	sp := spinner.New(fmt.Sprintf("📧 Sending email via %s...", ec.Host))
+	sp.Start()
+	err = mail.Send(context.Background(), server, msg)
+	sp.Stop()
+
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "✅ Report emailed to %s\n", strings.Join(ec.To, ", "))
+	return nil
+}
+
+// smtpPort returns the configured port or the conventional one for the security mode.
+func smtpPort(port int, security string) int {
+	if port > 0 {
+		return port
+	}
+	switch security {
+	case mail.SecurityTLS:
+		return 465
+	case mail.SecurityNone:
+		return 25
+	default:
+		return 587
+	}
+}
@@ -0,0 +1,353 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/anIcedAntFA/gohome/internal/config"
+	"github.com/anIcedAntFA/gohome/internal/entity"
+	"github.com/anIcedAntFA/gohome/internal/history"
+	"github.com/anIcedAntFA/gohome/internal/renderer"
+)
+
+// historyOptions holds the flags of the history commands.
+type historyOptions struct {
+	all     bool
+	limit   int
+	details bool
+}
+
+// historyCommand describes the history command, which lists the reports
+// when no other command is given.
+func historyCommand(opts *historyOptions) *config.Command {
+	return &config.Command{
+		Name:    "history",
+		Usage:   "gohome history [<command>] [flags] [args]",
+		Summary: "Show, compare and list the reports generated before.\nA report is its number in the list (1 is the latest), its ID or the start of its ID, e.g. a date.",
+		Examples: []string{
+			"gohome history",
+			"gohome history show 2",
+			"gohome history show -f table 20260109",
+			"gohome history diff",
+		},
+		Flags: func(fs *flag.FlagSet) {
+			fs.BoolVar(&opts.all, "all", false, "")
+			fs.IntVar(&opts.limit, "limit", 20, "")
+			fs.IntVar(&opts.limit, "n", 20, "")
+		},
+		Help: func(w io.Writer) {
+			fmt.Fprintln(w, "   list\tList the reports of the profile and author, latest first (default)")
+			fmt.Fprintln(w, "   show [flags] [report]\tShow a report again, the latest by default")
+			fmt.Fprintln(w, "   diff [flags] [old] [new]\tCompare two reports, the latest with the previous one of the same period by default")
+			fmt.Fprintln(w, "\t")
+			fmt.Fprintln(w, "       --all\tList the reports of every profile and author")
+			fmt.Fprintln(w, "   -n, --limit <int>\tNumber of reports to list, 0 for all (default 20)")
+		},
+	}
+}
+
+// historyShowCommand describes the history show command.
+var historyShowCommand = &config.Command{
+	Name:     "history show",
+	Usage:    "gohome history show [flags] [report]",
+	Summary:  "Show a report of the history again, with the output flags of the report command.",
+	Examples: []string{"gohome history show", "gohome history show -f slack --copy 2"},
+	Groups:   config.OutputFlags,
+}
+
+// historyDiffCommand describes the history diff command.
+func historyDiffCommand(opts *historyOptions) *config.Command {
+	return &config.Command{
+		Name:     "history diff",
+		Usage:    "gohome history diff [flags] [old] [new]",
+		Summary:  "Compare the commits and tasks of two reports of the history.",
+		Examples: []string{"gohome history diff", "gohome history diff 3", "gohome history diff --details 20260102 20260109"},
+		Flags: func(fs *flag.FlagSet) {
+			fs.BoolVar(&opts.details, "details", false, "")
+		},
+		Help: func(w io.Writer) {
+			fmt.Fprintln(w, "       --details\tAlso list the commits and tasks found in only one of the reports")
+		},
+	}
+}
+
+// historyDir returns the location of the history.
+func historyDir() string {
+	return filepath.Join(config.StateDir(), history.DirName)
+}
+
+// saveHistory keeps a generated report in the history, then drops the
+// oldest reports of the profile and author beyond history_limit. A report
+// that cannot be saved is a warning, not a reason to lose it.
+func saveHistory(cfg *config.AppConfig, report *entity.Report) {
+	rec := history.Record{Profile: cfg.Profile, From: cfg.PeriodStart(report.GeneratedAt), Report: *report}
+	if err := history.Save(historyDir(), &rec); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️ Warning: cannot save the report in the history: %v\n", err)
+		return
+	}
+
+	limit := cfg.HistoryLimit
+	if limit == 0 {
+		limit = history.DefaultLimit
+	}
+	if limit < 0 {
+		return
+	}
+	sameOwner := func(r history.Record) bool { return r.Profile == rec.Profile && r.Report.Author == rec.Report.Author }
+	if err := history.Prune(historyDir(), limit, sameOwner); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️ Warning: cannot prune the history: %v\n", err)
+	}
+}
+
+// runHistory dispatches the history subcommands.
+func runHistory(args []string) error {
+	sub, params := "list", args
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		sub, params = args[0], args[1:]
+	}
+
+	switch sub {
+	case "list":
+		return historyList(params)
+	case "show":
+		return historyShow(params)
+	case "diff":
+		return historyDiff(params)
+	}
+	return fmt.Errorf("unknown history command %q (see 'gohome history --help')", sub)
+}
+
+// profileRecords returns the records of the history made with the
+// profile of the configuration for its author, oldest first. Without a
+// known author, the records of every author of the profile are returned.
+func profileRecords(cfg *config.AppConfig, all bool) ([]history.Record, error) {
+	records, err := history.List(historyDir())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the history: %w", err)
+	}
+	if all {
+		return records, nil
+	}
+
+	author := historyAuthor(cfg)
+	kept := records[:0]
+	for _, rec := range records {
+		if rec.Profile == cfg.Profile && (author == "" || rec.Report.Author == author) {
+			kept = append(kept, rec)
+		}
+	}
+	return kept, nil
+}
+
+// historyAuthor returns the author reports are made for, as the report
+// command finds it, or "" when it is unknown.
+func historyAuthor(cfg *config.AppConfig) string {
+	gitClient, err := newBackend(cfg.GitBackend)
+	if err != nil {
+		return cfg.Author
+	}
+	author, _ := resolveAuthor(gitClient, cfg)
+	return author
+}
+
+// historyList prints the reports of the history, latest first, with the
+// numbers the other subcommands take.
+func historyList(args []string) error {
+	var opts historyOptions
+	cfg, rest, err := config.Load(historyCommand(&opts), args)
+	if err != nil {
+		return err
+	}
+	if len(rest) > 0 {
+		return fmt.Errorf("history list expects no arguments (see 'gohome history --help')")
+	}
+
+	records, err := profileRecords(cfg, opts.all)
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		fmt.Fprintln(os.Stderr, "📭 No reports in the history.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "#\tID\tGENERATED\tPROFILE\tAUTHOR\tPERIOD\tCOMMITS\tTASKS")
+	for n := 1; n <= len(records) && (opts.limit <= 0 || n <= opts.limit); n++ {
+		rec := records[len(records)-n]
+		profile := rec.Profile
+		if profile == "" {
+			profile = "-"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%d\t%d\n", n, rec.ID, rec.Report.GeneratedAt.Format("Mon Jan 2 15:04"),
+			profile, rec.Report.Author, rec.Report.Period, rec.Report.CommitCount(), len(rec.Report.Tasks))
+	}
+	return w.Flush()
+}
+
+// historyShow renders a report of the history again and delivers it
+// like a new one.
+func historyShow(args []string) error {
+	cfg, rest, err := config.Load(historyShowCommand, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) > 1 {
+		return fmt.Errorf("history show expects at most one report (see 'gohome history show --help')")
+	}
+	if cfg.SaveConfig || len(cfg.DynamicTasks) > 0 {
+		return errors.New("history show does not take --save or --task")
+	}
+	if err := validateOutput(cfg); err != nil {
+		return err
+	}
+
+	records, err := profileRecords(cfg, false)
+	if err != nil {
+		return err
+	}
+	ref := "1"
+	if len(rest) == 1 {
+		ref = rest[0]
+	}
+	rec, err := findRecord(records, ref)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "🗂️ Report %s, generated %s, period: %s\n",
+		rec.ID, rec.Report.GeneratedAt.Format("Mon Jan 2 15:04"), rec.Report.Period)
+	printer := renderer.NewPrinter(printerConfig(cfg, cfg.OutputFmt, colorEnabled(cfg)))
+	_, err = deliver(cfg, printer, &rec.Report)
+	return err
+}
+
+// historyDiff prints the differences between two reports of the history.
+func historyDiff(args []string) error {
+	var opts historyOptions
+	cfg, rest, err := config.Load(historyDiffCommand(&opts), args)
+	if err != nil {
+		return err
+	}
+	if len(rest) > 2 {
+		return fmt.Errorf("history diff expects at most two reports (see 'gohome history diff --help')")
+	}
+
+	records, err := profileRecords(cfg, false)
+	if err != nil {
+		return err
+	}
+
+	newRef := "1"
+	if len(rest) == 2 {
+		newRef = rest[1]
+	}
+	newer, err := findRecord(records, newRef)
+	if err != nil {
+		return err
+	}
+
+	var older history.Record
+	if len(rest) > 0 {
+		older, err = findRecord(records, rest[0])
+	} else {
+		older, err = previousRecord(records, newer)
+	}
+	if err != nil {
+		return err
+	}
+
+	printDiff(os.Stdout, older, newer, history.Compare(&older.Report, &newer.Report), opts.details)
+	return nil
+}
+
+// findRecord wraps history.Find with a hint for an empty history.
+func findRecord(records []history.Record, ref string) (history.Record, error) {
+	if len(records) == 0 {
+		return history.Record{}, errors.New("no reports in the history yet, run 'gohome' first")
+	}
+	return history.Find(records, ref)
+}
+
+// previousRecord returns the latest record of the same author before rec
+// covering the same period, e.g. last week's report for a weekly one, or
+// the one just before.
+func previousRecord(records []history.Record, rec history.Record) (history.Record, error) {
+	var before []history.Record
+	for _, r := range records {
+		if r.Report.Author == rec.Report.Author && r.Report.GeneratedAt.Before(rec.Report.GeneratedAt) {
+			before = append(before, r)
+		}
+	}
+	if len(before) == 0 {
+		return history.Record{}, fmt.Errorf("no report before %s to compare with", rec.ID)
+	}
+	for i := len(before) - 1; i >= 0; i-- {
+		if before[i].Report.Period == rec.Report.Period {
+			return before[i], nil
+		}
+	}
+	return before[len(before)-1], nil
+}
+
+// printDiff prints the counts of both reports and, with details, the
+// commits and tasks found in only one of them.
+func printDiff(out io.Writer, older, newer history.Record, d history.Diff, details bool) {
+	for _, line := range []struct {
+		mark string
+		rec  history.Record
+	}{{"---", older}, {"+++", newer}} {
+		fmt.Fprintf(out, "%s %s  %s  %s\n", line.mark, line.rec.ID,
+			line.rec.Report.GeneratedAt.Format("Mon Jan 2 15:04"), line.rec.Report.Period)
+	}
+	fmt.Fprintln(out)
+
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	printCounts := func(title string, counts []history.Count) {
+		fmt.Fprintf(w, "%s\tOLD\tNEW\tCHANGE\n", title)
+		for _, c := range counts {
+			fmt.Fprintf(w, "%s\t%d\t%d\t%s\n", c.Name, c.Old, c.New, change(c))
+		}
+		fmt.Fprintln(w, "\t\t\t")
+	}
+	if len(d.Types) > 0 {
+		printCounts("TYPE", d.Types)
+		printCounts("REPOSITORY", d.Repos)
+	}
+	fmt.Fprintf(w, "COMMITS\t%d\t%d\t%s\n", d.Total.Old, d.Total.New, change(d.Total))
+	fmt.Fprintf(w, "TASKS\t%d\t%d\t%s\n", d.Tasks.Old, d.Tasks.New, change(d.Tasks))
+	_ = w.Flush()
+
+	if !details {
+		return
+	}
+	if len(d.Added)+len(d.Removed)+len(d.AddedTasks)+len(d.RemovedTasks) > 0 {
+		fmt.Fprintln(out)
+	}
+	for _, c := range d.Removed {
+		fmt.Fprintf(out, "- %s: %s\n", c.Repo, c.Commit.Raw)
+	}
+	for _, c := range d.Added {
+		fmt.Fprintf(out, "+ %s: %s\n", c.Repo, c.Commit.Raw)
+	}
+	for _, t := range d.RemovedTasks {
+		fmt.Fprintf(out, "- task %s: %s\n", t.Type, t.Message)
+	}
+	for _, t := range d.AddedTasks {
+		fmt.Fprintf(out, "+ task %s: %s\n", t.Type, t.Message)
+	}
+}
+
+// change writes the difference of a count: "+3", "-1" or "=".
+func change(c history.Count) string {
+	if c.New == c.Old {
+		return "="
+	}
+	return fmt.Sprintf("%+d", c.New-c.Old)
+}
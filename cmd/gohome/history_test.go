@@ -0,0 +1,55 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHistoryRetention(t *testing.T) {
+	ws := workspace(t)
+	isolate(t)
+	writeConfig(t, `{"config_version": 1, "history_limit": 2}`)
+	records := func() []string {
+		t.Helper()
+		paths, err := filepath.Glob(filepath.Join(os.Getenv("XDG_STATE_HOME"), "gohome", "history", "*.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return paths
+	}
+
+	for i := 0; i < 3; i++ {
+		now := testNow.Add(time.Duration(i) * time.Minute)
+		clock = func() time.Time { return now }
+		gohome(t, "-d", "3", "-p", ws, "-a", "tester")
+	}
+	gohome(t, "-d", "3", "-p", ws, "-a", "someone")
+	gohome(t, "-d", "3", "-p", ws, "-a", "tester", "--dry-run")
+	if got := records(); len(got) != 3 {
+		t.Errorf("history has %d reports, want 2 of tester and 1 of someone:\n%s", len(got), strings.Join(got, "\n"))
+	}
+
+	// The history of an author leaves the reports of others out, and
+	// someone's single report has nothing to compare with
+	t.Setenv("GOHOME_AUTHOR", "someone")
+	out := gohome(t, "history")
+	if strings.Count(out, "someone") != 1 || strings.Contains(out, "tester") {
+		t.Errorf("history of someone:\n%s", out)
+	}
+	if err := run([]string{"history", "diff"}); err == nil || !strings.Contains(err.Error(), "no report before") {
+		t.Errorf("history diff of someone: err = %v", err)
+	}
+	t.Setenv("GOHOME_AUTHOR", "tester")
+	if out := gohome(t, "history"); strings.Count(out, "tester") != 2 || strings.Contains(out, "someone") {
+		t.Errorf("history of tester:\n%s", out)
+	}
+	if out := gohome(t, "history", "--all"); strings.Count(out, "tester")+strings.Count(out, "someone") != 3 {
+		t.Errorf("history --all:\n%s", out)
+	}
+	if out := gohome(t, "history", "diff"); !strings.Contains(out, "COMMITS") {
+		t.Errorf("history diff of tester:\n%s", out)
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/anIcedAntFA/gohome/internal/config"
+	"github.com/anIcedAntFA/gohome/internal/entity"
+	"github.com/anIcedAntFA/gohome/internal/journal"
+)
+
+// logOptions holds the flags of the log command.
+type logOptions struct {
+	taskType string
+	at       string
+}
+
+// logCommand describes the log command.
+func logCommand(opts *logOptions) *config.Command {
+	return &config.Command{
+		Name:    "log",
+		Usage:   "gohome log [flags] [message]",
+		Summary: "Write a dated note to the journal, or list the notes of the period.",
+		Examples: []string{
+			`gohome log "Debugged prod incident with SRE"`,
+			`gohome log "meeting: Architecture review"`,
+			`gohome log --at "2026-01-09 16:00" --type review "Reviewed the API design"`,
+			"gohome log -w 1",
+		},
+		Groups: config.PeriodFlags,
+		Flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&opts.taskType, "type", "", "")
+			fs.StringVar(&opts.at, "at", "", "")
+		},
+		Help: func(w io.Writer) {
+			fmt.Fprintln(w, "       --type <type>\tType of the note, \"type: message\" works too (default misc)")
+			fmt.Fprintln(w, "       --at <time>\tWhen it happened: YYYY-MM-DD, HH:MM or \"YYYY-MM-DD HH:MM\" (default now)")
+		},
+	}
+}
+
+// journalPath returns the location of the journal.
+func journalPath() string {
+	return filepath.Join(config.StateDir(), journal.FileName)
+}
+
+// runLog appends a note of the active profile to the journal, or lists the
+// notes of the period when no message is given.
+func runLog(args []string) error {
+	var opts logOptions
+	cfg, rest, err := config.Load(logCommand(&opts), args)
+	if err != nil {
+		return err
+	}
+
+	message := strings.TrimSpace(strings.Join(rest, " "))
+	if message == "" {
+		return listJournal(cfg)
+	}
+
+	at := clock().Truncate(time.Second)
+	if opts.at != "" {
+		if at, err = parseLogTime(opts.at, at); err != nil {
+			return err
+		}
+	}
+
+	task := entity.ParseTask(message)
+	if opts.taskType != "" {
+		task = entity.Task{Type: opts.taskType, Message: message, Icon: entity.TaskIcon(opts.taskType)}
+	}
+
+	entry := journal.Entry{Time: at, Profile: cfg.Profile, Type: task.Type, Message: task.Message, Icon: task.Icon}
+	if err := journal.Append(journalPath(), entry); err != nil {
+		return fmt.Errorf("failed to write the journal: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "✅ Logged %s %s: %s (%s)\n", entry.Icon, entry.Type, entry.Message, at.Format("Mon Jan 2 15:04"))
+	return nil
+}
+
+// listJournal prints the notes of the report period.
+func listJournal(cfg *config.AppConfig) error {
+	now := clock()
+	entries, err := journal.Read(journalPath(), cfg.Profile, cfg.PeriodStart(now), now)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		fmt.Fprintf(os.Stderr, "📭 No notes since %s.\n", cfg.GetPeriod())
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tTYPE\tNOTE")
+	for _, e := range entries {
+		fmt.Fprintf(w, "%s\t%s\t%s %s\n", e.Time.Format("Mon Jan 2 15:04"), e.Type, e.Icon, e.Message)
+	}
+	return w.Flush()
+}
+
+// parseLogTime reads the time of a note: a date, a time of today or both.
+func parseLogTime(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02T15:04", entity.DateLayout} {
+		if t, err := time.ParseInLocation(layout, value, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+	if t, err := time.ParseInLocation("15:04", value, now.Location()); err == nil {
+		return time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, now.Location()), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (use YYYY-MM-DD, HH:MM or \"YYYY-MM-DD HH:MM\")", value)
+}
+
+// journalTasks returns the notes of the report period as tasks. Notes of
+// longer periods carry their day. A broken journal is a warning, not a
+// reason to lose the report.
+func journalTasks(cfg *config.AppConfig, now time.Time) []entity.Task {
+	from := cfg.PeriodStart(now)
+	entries, err := journal.Read(journalPath(), cfg.Profile, from, now)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️ Warning: cannot read the journal: %v\n", err)
+		return nil
+	}
+
+	multiDay := len(entity.ReportDays(from, now)) > 1
+	tasks := make([]entity.Task, 0, len(entries))
+	for _, e := range entries {
+		task := e.Task()
+		task.Message = datedMessage(task.Message, e.Time, multiDay)
+		tasks = append(tasks, task)
+	}
+	return tasks
+}
+
+// datedMessage adds the day to the message of a dated task when the
+// report covers several days.
+func datedMessage(message string, t time.Time, multiDay bool) string {
+	if !multiDay {
+		return message
+	}
+	return fmt.Sprintf("%s (%s)", message, t.Format("Mon Jan 2"))
+}
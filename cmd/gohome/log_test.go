@@ -0,0 +1,75 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anIcedAntFA/gohome/internal/config"
+)
+
+func TestParseLogTime(t *testing.T) {
+	now := time.Date(2026, time.January, 9, 18, 30, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"2026-01-08 16:00", time.Date(2026, time.January, 8, 16, 0, 0, 0, time.UTC)},
+		{"2026-01-08T16:00", time.Date(2026, time.January, 8, 16, 0, 0, 0, time.UTC)},
+		{"2026-01-08", time.Date(2026, time.January, 8, 0, 0, 0, 0, time.UTC)},
+		{" 09:15 ", time.Date(2026, time.January, 9, 9, 15, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := parseLogTime(tt.value, now)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("parseLogTime(%q) = %v, %v; want %v", tt.value, got, err, tt.want)
+		}
+	}
+	for _, value := range []string{"", "yesterday", "25:00", "2026-13-01"} {
+		if _, err := parseLogTime(value, now); err == nil {
+			t.Errorf("parseLogTime(%q): expected an error", value)
+		}
+	}
+}
+
+func TestLogProfiles(t *testing.T) {
+	isolate(t)
+	writeConfig(t, `{"config_version": 1, "profiles": {"work": {"author": "tester"}}}`)
+	today := testNow.Format("2006-01-02")
+	gohome(t, "log", "--at", today+" 09:00", "meeting: Standup")
+	gohome(t, "log", "--profile", "work", "--at", today+" 10:00", "Work note")
+	gohome(t, "log", "--at", testNow.AddDate(0, 0, -3).Format("2006-01-02 15:04"), "Old note")
+
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"log", "-d", "1"}, []string{"Standup"}},
+		{[]string{"log", "-d", "7"}, []string{"Old note", "Standup"}},
+		{[]string{"log", "--profile", "work", "-d", "7"}, []string{"Work note"}},
+	}
+	for _, tt := range tests {
+		out := gohome(t, tt.args...)
+		var got []string
+		for _, note := range []string{"Old note", "Standup", "Work note"} {
+			if strings.Contains(out, note) {
+				got = append(got, note)
+			}
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("gohome %s lists %q, want %q:\n%s", strings.Join(tt.args, " "), got, tt.want, out)
+		}
+	}
+
+	// Reports of several days date the notes
+	tasks := journalTasks(&config.AppConfig{Days: 7}, testNow)
+	var messages []string
+	for _, task := range tasks {
+		messages = append(messages, task.Message)
+	}
+	want := []string{"Old note (Tue Sep 29)", "Standup (Fri Oct 2)"}
+	if !reflect.DeepEqual(messages, want) {
+		t.Errorf("journalTasks = %q, want %q", messages, want)
+	}
+}
@@ -3,214 +3,81 @@
 package main
 
 import (
-	"bytes"
-	"context"
+	"errors"
+	"flag"
 	"fmt"
-	"io"
-	"log"
 	"os"
-	"path/filepath"
-	"time"
+	"text/tabwriter"
 
 	"github.com/anIcedAntFA/gohome/internal/config"
-	"github.com/anIcedAntFA/gohome/internal/entity"
-	"github.com/anIcedAntFA/gohome/internal/git"
-	"github.com/anIcedAntFA/gohome/internal/parser"
-	"github.com/anIcedAntFA/gohome/internal/renderer"
-	"github.com/anIcedAntFA/gohome/internal/scanner"
-	"github.com/anIcedAntFA/gohome/internal/spinner"
-	"github.com/anIcedAntFA/gohome/internal/sys"
 )
 
-func main() {
-	// 1. Load configuration (handles --version flag internally)
-	cfg := config.Load()
-
-	// 2. Handle config save and exit early
-	if cfg.SaveConfig {
-		handleSaveConfig(cfg)
-	}
-
-	// 3. Initialize dependencies
-	deps := initDependencies(cfg)
-
-	// 4. Setup output writer
-	outputWriter, clipboardBuffer := setupWriter(cfg.CopyToClipboard)
-
-	// 5. Process and render
-	foundAny := processAndRender(deps, cfg, outputWriter)
-
-	// 6. Handle clipboard copy
-	handleClipboard(foundAny, cfg.CopyToClipboard, clipboardBuffer)
+// command is a gohome subcommand.
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+	spec    func() *config.Command // Flags and help, used by shell completion
 }
 
-// handleSaveConfig saves configuration to file and exits.
-func handleSaveConfig(cfg *config.AppConfig) {
-	if err := cfg.SaveToFile(); err != nil {
-		log.Fatalf("❌ Failed to save config: %v", err)
+// commands lists the subcommands in the order shown by the help screen.
+// The report command is the default when no command is given.
+func commands() []command {
+	return []command{
+		{name: "report", summary: "Generate the work report (default)", run: runReport, spec: func() *config.Command { return reportCommand }},
+		{name: "config", summary: "Inspect the configuration file", run: runConfig, spec: configCommand},
+		{name: "tasks", summary: "Manage recurring tasks", run: runTasks, spec: tasksCommand},
+		{name: "log", summary: "Write a dated note to the journal", run: runLog, spec: func() *config.Command { return logCommand(new(logOptions)) }},
+		{name: "history", summary: "Show, compare and list past reports", run: runHistory, spec: func() *config.Command { return historyCommand(new(historyOptions)) }},
+		{name: "repos", summary: "List repositories found under the scan path", run: runRepos, spec: func() *config.Command { return reposCommand(new(bool)) }},
+		{name: "stats", summary: "Show commit statistics per type and repository", run: runStats, spec: statsCommand},
+		{name: "version", summary: "Show version information", run: runVersion, spec: func() *config.Command { return versionCommand(new(bool)) }},
+		{name: "completion", summary: "Generate a shell completion script", run: runCompletion, spec: completionCommand},
 	}
-
-	configPath := config.GetConfigPath()
-	fmt.Println("✅ Configuration saved successfully!")
-	fmt.Printf("💡 Tip: You can edit this file to customize your daily recurring tasks.\n   Config location: %s\n", configPath)
-	fmt.Println("You can now run 'gohome' without flags to use these settings.")
-	os.Exit(0)
 }
 
-// dependencies holds all service instances.
-type dependencies struct {
-	gitClient *git.Client
-	parser    *parser.Service
-	printer   *renderer.Printer
-	author    string
-	period    string
-	repos     []string
-}
-
-// initDependencies creates and initializes all required services.
-func initDependencies(cfg *config.AppConfig) *dependencies {
-	gitClient := git.NewClient()
-	parserSvc := parser.NewService()
-	printer := renderer.NewPrinter(renderer.Config{
-		Format:    cfg.OutputFmt,
-		Style:     cfg.Preset,
-		ShowIcon:  cfg.ShowIcon,
-		ShowScope: cfg.ShowScope,
-	})
-
-	// Determine author
-	author := cfg.Author
-	if author == "" {
-		if val := gitClient.GetUser(context.Background()); val != "" {
-			author = val
-		} else {
-			log.Fatal("❌ Author not found. Please use -a flag or check git config.")
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
 		}
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(1)
 	}
-
-	// Get period and scan repos
-	period := cfg.GetPeriod()
-	fmt.Println("🗓️ Period:", period)
-
-	absPath, _ := filepath.Abs(cfg.Path)
-
-	sp := spinner.New("🔍 Scanning repositories...").
-		WithFrames(spinner.PacmanGhost).
-		WithInterval(100 * time.Millisecond)
-	sp.Start()
-
-	repos, err := scanner.ScanGitRepos(absPath)
-	sp.Stop()
-
-	if err != nil {
-		log.Fatal(err)
-	}
-	fmt.Printf("✓ Found %d repositories\n", len(repos))
-
-	return &dependencies{
-		gitClient: gitClient,
-		parser:    parserSvc,
-		printer:   printer,
-		author:    author,
-		period:    period,
-		repos:     repos,
-	}
-}
-
-// setupWriter creates output writer and optional clipboard buffer.
-func setupWriter(copyToClipboard bool) (io.Writer, *bytes.Buffer) {
-	var clipboardBuffer bytes.Buffer
-	var outputWriter io.Writer = os.Stdout
-
-	if copyToClipboard {
-		outputWriter = io.MultiWriter(os.Stdout, &clipboardBuffer)
-	}
-
-	return outputWriter, &clipboardBuffer
-}
-
-// processAndRender handles git commits and tasks rendering.
-func processAndRender(deps *dependencies, cfg *config.AppConfig, w io.Writer) bool {
-	foundCommits := processCommits(deps, w)
-	foundTasks := processTasks(deps.printer, cfg, w)
-
-	return foundCommits || foundTasks
 }
 
-// processCommits fetches and renders git commits.
-func processCommits(deps *dependencies, w io.Writer) bool {
-	foundAny := false
-
-	for _, repo := range deps.repos {
-		repoName := filepath.Base(repo)
-		sp := spinner.New(fmt.Sprintf("📥 Fetching commits from %s...", repoName))
-		sp.Start()
-
-		rawLogs, err := deps.gitClient.GetLogs(context.Background(), repo, deps.author, deps.period)
-		sp.Stop()
-
-		if err != nil || len(rawLogs) == 0 {
-			continue
-		}
-
-		commits := make([]entity.Commit, 0, len(rawLogs))
-		for _, line := range rawLogs {
-			commits = append(commits, deps.parser.Parse(line))
+// run dispatches the arguments to a command.
+func run(args []string) error {
+	if len(args) > 0 {
+		switch args[0] {
+		case "help", "-h", "-help", "--help":
+			printHelp()
+			return nil
+		case "-v", "--version":
+			// Kept for compatibility with the flag-based CLI
+			return runVersion(nil)
 		}
 
-		if len(commits) > 0 {
-			foundAny = true
-			deps.printer.Print(w, filepath.Base(repo), commits)
+		for _, cmd := range commands() {
+			if cmd.name == args[0] {
+				return cmd.run(args[1:])
+			}
 		}
 	}
 
-	return foundAny
+	return runReport(args)
 }
 
-// processTasks renders static (enabled only) and dynamic tasks.
-func processTasks(printer *renderer.Printer, cfg *config.AppConfig, w io.Writer) bool {
-	activeTasks := make([]entity.Task, 0, len(cfg.Tasks))
+// printHelp displays the report help screen followed by the list of commands.
+func printHelp() {
+	config.PrintUsage(os.Stderr, reportCommand)
 
-	// 1. Filter Static Tasks: Only include tasks where Enabled = true
-	for _, t := range cfg.Tasks {
-		if t.Enabled {
-			activeTasks = append(activeTasks, t)
-		}
-	}
-
-	// 2. Add Dynamic Tasks: Tasks from CLI are always displayed by default
-	for _, msg := range cfg.DynamicTasks {
-		activeTasks = append(activeTasks, entity.Task{
-			Message: msg,
-			Type:    "misc",
-			Icon:    "📌",
-			// Enabled: true, // (Optional: set true if struct requires it, but typically not necessary for printing)
-		})
+	fmt.Fprintf(os.Stderr, "COMMANDS:\n")
+	w := tabwriter.NewWriter(os.Stderr, 0, 8, 2, ' ', 0)
+	for _, cmd := range commands() {
+		fmt.Fprintf(w, "   %s\t%s\n", cmd.name, cmd.summary)
 	}
+	_ = w.Flush()
 
-	// 3. Print if any tasks exist
-	if len(activeTasks) > 0 {
-		printer.PrintTasks(w, activeTasks)
-		return true
-	}
-
-	return false
-}
-
-// handleClipboard copies content to clipboard if enabled.
-func handleClipboard(foundAny, copyEnabled bool, buffer *bytes.Buffer) {
-	if !foundAny {
-		fmt.Println("📭 No commits or tasks found.")
-		return
-	}
-
-	if copyEnabled {
-		content := buffer.String()
-		if err := sys.CopyToClipboard(context.Background(), content); err != nil {
-			fmt.Printf("\n⚠️  Failed to copy: %v\n", err)
-			fmt.Println("   (Linux users: please install 'wl-clipboard' or 'xclip')")
-		} else {
-			fmt.Println("\n📋 Report copied to clipboard!")
-		}
-	}
+	fmt.Fprintf(os.Stderr, "\nRun 'gohome <command> --help' for details about a command.\n\n")
 }
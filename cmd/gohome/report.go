@@ -0,0 +1,445 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/anIcedAntFA/gohome/internal/commitcache"
+	"github.com/anIcedAntFA/gohome/internal/config"
+	"github.com/anIcedAntFA/gohome/internal/entity"
+	"github.com/anIcedAntFA/gohome/internal/git"
+	"github.com/anIcedAntFA/gohome/internal/renderer"
+	"github.com/anIcedAntFA/gohome/internal/spinner"
+	"github.com/anIcedAntFA/gohome/internal/sys"
+)
+
+// reportCommand is the default command generating the work report.
+var reportCommand = &config.Command{
+	Name:    "report",
+	Usage:   "gohome [report] [flags]",
+	Summary: "🚀 GO HOME TOOL (Go CLI)\nA simple tool to aggregate git commit reports.",
+	Examples: []string{
+		"gohome -d 3",
+		"gohome -f table -s markdown -i -w 1",
+		"gohome -f html -w 1 -o report.html",
+	},
+	Groups: config.PeriodFlags | config.OutputFlags,
+}
+
+// newBackend and clock are replaced by tests, which read fake repositories
+// at a fixed time.
+var (
+	newBackend = git.NewBackend
+	clock      = time.Now
+)
+
+// runReport generates, renders and delivers the report.
+func runReport(args []string) error {
+	// 1. Load configuration
+	cfg, rest, err := config.Load(reportCommand, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) > 0 {
+		return fmt.Errorf("unknown command %q (see 'gohome help')", rest[0])
+	}
+
+	// 2. Handle config save and exit early
+	if cfg.SaveConfig {
+		return handleSaveConfig(cfg)
+	}
+
+	// 3. List table styles and exit early
+	if cfg.Preset == "list" {
+		return handleListStyles(cfg)
+	}
+
+	if err := validateOutput(cfg); err != nil {
+		return err
+	}
+
+	// 4. Initialize dependencies
+	deps, err := initDependencies(cfg)
+	if err != nil {
+		return err
+	}
+
+	// 5. Collect commits and tasks into a report
+	report := buildReport(deps, cfg)
+
+	// 6. Keep the report in the history, dry runs aside
+	if !report.IsEmpty() && !cfg.DryRun {
+		saveHistory(cfg, report)
+	}
+
+	// 7. Render and deliver
+	delivered, err := deliver(cfg, deps.printer, report)
+
+	// 8. Remember the report for --since-last once it reached someone,
+	// even when another destination failed
+	if delivered {
+		if err := config.SetLastReport(cfg.Profile, report.GeneratedAt); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️ Warning: cannot save the last report time: %v\n", err)
+		}
+	}
+	return err
+}
+
+// deliver writes the report to stdout or the output file, then copies,
+// posts and emails it as requested. It reports whether the report was
+// copied, posted or emailed, dry runs aside. A failed destination does not
+// stop the others; their errors are returned together.
+func deliver(cfg *config.AppConfig, printer *renderer.Printer, report *entity.Report) (bool, error) {
+	// 1. Setup output writer
+	outputWriter, closeOutput, err := setupWriter(cfg.OutputFile)
+	if err != nil {
+		return false, err
+	}
+
+	// 2. Render
+	content, foundAny, err := render(printer, report, outputWriter)
+	closeOutput()
+	if err != nil {
+		return false, err
+	}
+
+	// 3. Handle clipboard copy
+	delivered := handleClipboard(foundAny, cfg.CopyToClipboard, content)
+
+	// 4. Post to webhooks
+	var errs []error
+	if foundAny && len(cfg.Post) > 0 {
+		posted, err := handlePost(cfg, report)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		delivered = delivered || posted > 0 && !cfg.DryRun
+	}
+
+	// 5. Send by email
+	if foundAny && cfg.SendEmail {
+		if err := emailReport(cfg, report); err != nil {
+			errs = append(errs, fmt.Errorf("failed to send email: %w", err))
+		} else {
+			delivered = delivered || !cfg.DryRun
+		}
+	}
+
+	return delivered, errors.Join(errs...)
+}
+
+// handleSaveConfig saves configuration to file.
+func handleSaveConfig(cfg *config.AppConfig) error {
+	if err := cfg.SaveToFile(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	configPath := config.GetConfigPath()
+	fmt.Println("✅ Configuration saved successfully!")
+	fmt.Printf("💡 Tip: You can edit this file to customize your daily recurring tasks.\n   Config location: %s\n", configPath)
+	fmt.Println("You can now run 'gohome' without flags to use these settings.")
+	return nil
+}
+
+// handleListStyles prints the table style catalogue.
+func handleListStyles(cfg *config.AppConfig) error {
+	styles, err := customStyles(cfg)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "STYLE\tDESCRIPTION")
+	for _, s := range renderer.Styles(styles) {
+		fmt.Fprintf(w, "%s\t%s\n", s.Name, s.Description)
+	}
+	_ = w.Flush()
+
+	fmt.Printf("\nBorder sets for custom styles: %s\n", strings.Join(renderer.BorderSets(), ", "))
+	fmt.Printf("Header colors: %s\n", strings.Join(renderer.Colors(), ", "))
+	return nil
+}
+
+// validateOutput checks the format, color mode, table style and theme before any work is done.
+func validateOutput(cfg *config.AppConfig) error {
+	if !slices.Contains(renderer.Formats(), cfg.OutputFmt) {
+		return fmt.Errorf("invalid --format value %q (use %s)", cfg.OutputFmt, strings.Join(renderer.Formats(), ", "))
+	}
+
+	switch cfg.Color {
+	case sys.ColorAuto, sys.ColorAlways, sys.ColorNever:
+	default:
+		return fmt.Errorf("invalid --color value %q (use auto, always or never)", cfg.Color)
+	}
+
+	styles, err := customStyles(cfg)
+	if err != nil {
+		return err
+	}
+	if _, ok := renderer.LookupStyle(cfg.Preset, styles); !ok {
+		names := []string{"list"}
+		for _, s := range renderer.Styles(styles) {
+			names = append(names, s.Name)
+		}
+		return fmt.Errorf("invalid --style value %q (use %s)", cfg.Preset, strings.Join(names, ", "))
+	}
+
+	for key, spec := range cfg.Theme {
+		if err := renderer.ValidateColor(spec); err != nil {
+			return fmt.Errorf("invalid theme color for %q: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+// customStyles converts the configured table styles into renderer specs.
+func customStyles(cfg *config.AppConfig) (map[string]renderer.StyleSpec, error) {
+	styles := make(map[string]renderer.StyleSpec, len(cfg.TableStyles))
+	for name, s := range cfg.TableStyles {
+		spec := renderer.StyleSpec{Border: s.Border, HeaderColor: s.HeaderColor}
+		if err := renderer.ValidateStyleSpec(spec); err != nil {
+			return nil, fmt.Errorf("invalid table style %q: %w", name, err)
+		}
+		styles[strings.ToLower(name)] = spec
+	}
+	return styles, nil
+}
+
+// printerConfig builds the renderer configuration for the given format.
+// The configuration must have been checked by validateOutput.
+func printerConfig(cfg *config.AppConfig, format string, color bool) renderer.Config {
+	styles, _ := customStyles(cfg)
+
+	return renderer.Config{
+		Format:    format,
+		Style:     cfg.Preset,
+		ShowIcon:  cfg.ShowIcon,
+		ShowScope: cfg.ShowScope,
+
+		CustomStyles: styles,
+		Color:        color,
+		Theme:        cfg.Theme,
+	}
+}
+
+// colorEnabled decides whether the report is colorized.
+// Colors are only used when the report goes to stdout.
+func colorEnabled(cfg *config.AppConfig) bool {
+	if cfg.OutputFile != "" {
+		return false
+	}
+	return sys.ColorEnabled(cfg.Color, os.Stdout)
+}
+
+// dependencies holds all service instances.
+type dependencies struct {
+	gitClient git.Backend
+	cache     *commitcache.Cache // Nil with --no-cache
+	printer   *renderer.Printer
+	author    string
+	period    string    // Shown in the report
+	now       time.Time // End of the period
+	from      time.Time // Start of the period
+	repos     []repository
+}
+
+// initDependencies creates and initializes all required services.
+func initDependencies(cfg *config.AppConfig) (*dependencies, error) {
+	gitClient, err := newBackend(cfg.GitBackend)
+	if err != nil {
+		return nil, err
+	}
+	printer := renderer.NewPrinter(printerConfig(cfg, cfg.OutputFmt, colorEnabled(cfg)))
+
+	author, err := resolveAuthor(gitClient, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get period and scan repos
+	period := cfg.GetPeriod()
+	if cfg.Profile != "" {
+		fmt.Fprintln(os.Stderr, "👤 Profile:", cfg.Profile)
+	}
+	fmt.Fprintln(os.Stderr, "🗓️ Period:", period)
+
+	repos, err := scanRepos(cfg)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(os.Stderr, "✓ Found %d repositories\n", len(repos))
+
+	var cache *commitcache.Cache
+	if !cfg.NoCache {
+		cache = commitcache.New(filepath.Join(config.CacheDir(), commitcache.DirName))
+	}
+
+	now := clock()
+	return &dependencies{
+		gitClient: gitClient,
+		cache:     cache,
+		printer:   printer,
+		author:    author,
+		period:    period,
+		now:       now,
+		from:      cfg.PeriodStart(now),
+		repos:     repos,
+	}, nil
+}
+
+// resolveAuthor returns the configured author or the one from git config.
+func resolveAuthor(gitClient git.Backend, cfg *config.AppConfig) (string, error) {
+	if cfg.Author != "" {
+		return cfg.Author, nil
+	}
+	if val := gitClient.GetUser(context.Background()); val != "" {
+		return val, nil
+	}
+	return "", errors.New("author not found. Please use -a flag or check git config")
+}
+
+// setupWriter creates the output writer.
+// When outputFile is set the report is written to that file instead of stdout.
+// The returned function closes the output file, if any.
+func setupWriter(outputFile string) (io.Writer, func(), error) {
+	if outputFile == "" {
+		return os.Stdout, func() {}, nil
+	}
+
+	// #nosec G304 -- the output path is chosen by the user running the tool
+	file, err := os.Create(outputFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot create output file: %w", err)
+	}
+
+	return file, func() {
+		if err := file.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️ Warning: Failed to close file: %v\n", err)
+		}
+	}, nil
+}
+
+// buildReport collects commits and tasks into a single report.
+func buildReport(deps *dependencies, cfg *config.AppConfig) *entity.Report {
+	return &entity.Report{
+		Author:      deps.author,
+		Period:      deps.period,
+		GeneratedAt: deps.now,
+		Repos:       processCommits(deps),
+		Tasks:       reportTasks(cfg, deps.now),
+	}
+}
+
+// render writes the report and returns its content without ANSI colors,
+// which is what ends up on the clipboard. It returns false for empty reports.
+func render(printer *renderer.Printer, report *entity.Report, w io.Writer) (string, bool, error) {
+	if report.IsEmpty() {
+		return "", false, nil
+	}
+
+	var buf bytes.Buffer
+	if err := printer.Render(&buf, report); err != nil {
+		return "", false, fmt.Errorf("failed to render report: %w", err)
+	}
+
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return "", false, fmt.Errorf("failed to write report: %w", err)
+	}
+
+	return renderer.StripANSI(buf.String()), true, nil
+}
+
+// processCommits fetches and parses the commits of every repository.
+func processCommits(deps *dependencies) []entity.RepoReport {
+	var repos []entity.RepoReport
+
+	for _, repo := range deps.repos {
+		sp := spinner.New(fmt.Sprintf("📥 Fetching commits from %s...", repo.name))
+		sp.Start()
+
+		commits, err := repoCommits(context.Background(), deps, repo)
+		sp.Stop()
+
+		if err != nil || len(commits) == 0 {
+			continue
+		}
+
+		repos = append(repos, entity.RepoReport{
+			Name:      repo.name,
+			Path:      repo.path,
+			URL:       git.WebURL(deps.gitClient.GetRemoteURL(context.Background(), repo.path)),
+			Commits:   commits,
+			TicketURL: repo.ticketURL,
+		})
+	}
+
+	return repos
+}
+
+// reportTasks returns the tasks of the report: configured and dynamic
+// tasks, then the journal notes and the tasks done in task sources.
+func reportTasks(cfg *config.AppConfig, now time.Time) []entity.Task {
+	tasks := processTasks(cfg, now)
+	tasks = append(tasks, journalTasks(cfg, now)...)
+	return append(tasks, sourceTasks(cfg, now)...)
+}
+
+// processTasks returns static (enabled and scheduled in the period) and dynamic tasks.
+func processTasks(cfg *config.AppConfig, now time.Time) []entity.Task {
+	activeTasks := make([]entity.Task, 0, len(cfg.Tasks))
+	days := entity.ReportDays(cfg.PeriodStart(now), now)
+
+	// 1. Filter Static Tasks: Only include enabled tasks that happen in the
+	// period, with their count when they happen several times
+	for _, t := range cfg.Tasks {
+		if !t.Enabled {
+			continue
+		}
+		n := t.Occurrences(days)
+		if n == 0 {
+			continue
+		}
+		if n > 1 {
+			t.Message = fmt.Sprintf("%s ×%d", t.Message, n)
+		}
+		activeTasks = append(activeTasks, t)
+	}
+
+	// 2. Add Dynamic Tasks: Tasks from CLI are always displayed by default,
+	// "meeting: Sprint planning" gives the task a type
+	for _, msg := range cfg.DynamicTasks {
+		activeTasks = append(activeTasks, entity.ParseTask(msg))
+	}
+
+	return activeTasks
+}
+
+// handleClipboard copies content to clipboard if enabled and reports
+// whether it was copied.
+func handleClipboard(foundAny, copyEnabled bool, content string) bool {
+	if !foundAny {
+		fmt.Fprintln(os.Stderr, "📭 No commits or tasks found.")
+		return false
+	}
+
+	if !copyEnabled {
+		return false
+	}
+	if err := sys.CopyToClipboard(context.Background(), content); err != nil {
+		fmt.Fprintf(os.Stderr, "\n⚠️  Failed to copy: %v\n", err)
+		fmt.Fprintln(os.Stderr, "   (Linux users: please install 'wl-clipboard' or 'xclip')")
+		return false
+	}
+	fmt.Fprintln(os.Stderr, "\n📋 Report copied to clipboard!")
+	return true
+}
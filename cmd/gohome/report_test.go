@@ -0,0 +1,247 @@
+package main
+
+import (
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anIcedAntFA/gohome/internal/config"
+	"github.com/anIcedAntFA/gohome/internal/git"
+	"github.com/anIcedAntFA/gohome/internal/git/gittest"
+	"github.com/anIcedAntFA/gohome/internal/sys"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// testNow is the time the tests run gohome at, the day after the commits
+// of the workspace.
+var testNow = gittest.Start.Add(36 * time.Hour)
+
+// workspace builds a scan path with two repositories: api, with a merged
+// branch, commits of another author and an old commit, and web, renamed by
+// its .gohome-repo.json and with uncommitted changes.
+func workspace(t *testing.T) string {
+	t.Helper()
+	ws := t.TempDir()
+
+	api := gittest.NewRepo(t, filepath.Join(ws, "api"))
+	api.Git("remote", "add", "origin", "git@github.com:acme/api.git")
+	api.Date = gittest.Start.Add(-10 * 24 * time.Hour)
+	api.Commit("tester", "chore: too old for the period")
+	api.Date = gittest.Start.Add(-time.Hour)
+	api.Commit("tester", "feat(auth): add login")
+	api.Commit("someone", "docs: not mine")
+	api.Git("checkout", "-q", "-b", "topic")
+	api.Commit("tester", "fix(auth): handle expired tokens")
+	api.Git("checkout", "-q", "main")
+	api.Commit("tester", "feat!: drop the v1 API")
+	api.Merge("topic")
+
+	web := gittest.NewRepo(t, filepath.Join(ws, "web"))
+	web.Git("remote", "add", "origin", "https://github.com/acme/web.git")
+	web.WriteFile(".gohome-repo.json", `{"name": "acme/web", "ticket_pattern": "[A-Z]+-[0-9]+", "ticket_url": "https://jira.example.com/browse/{id}"}`)
+	web.Commit("tester", "feat(ui): dark mode WEB-12")
+	web.Commit("tester", "wip: experiments")
+	web.Commit("tester", "perf: lazy load images")
+	web.WriteFile("draft.txt", "changed\n")
+	web.Git("add", "draft.txt")
+	return ws
+}
+
+// isolate runs gohome with empty config, cache and state directories and at
+// testNow.
+func isolate(t *testing.T) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "config"))
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(home, "cache"))
+	t.Setenv("XDG_STATE_HOME", filepath.Join(home, "state"))
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	for _, kv := range os.Environ() {
+		if name, _, _ := strings.Cut(kv, "="); strings.HasPrefix(name, "GOHOME_") {
+			t.Setenv(name, "")
+			_ = os.Unsetenv(name)
+		}
+	}
+
+	clock = func() time.Time { return testNow }
+	t.Cleanup(func() { clock = time.Now })
+}
+
+// gohome runs the CLI and returns what it wrote to stdout.
+func gohome(t *testing.T, args ...string) string {
+	t.Helper()
+	dir := t.TempDir()
+	stdout, err := os.Create(filepath.Join(dir, "stdout"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stderr, err := os.Create(filepath.Join(dir, "stderr"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oldStdout, oldStderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = stdout, stderr
+	runErr := run(args)
+	os.Stdout, os.Stderr = oldStdout, oldStderr
+	_ = stdout.Close()
+	_ = stderr.Close()
+
+	if runErr != nil {
+		logs, _ := os.ReadFile(stderr.Name())
+		t.Fatalf("gohome %s: %v\n%s", strings.Join(args, " "), runErr, logs)
+	}
+	out, err := os.ReadFile(stdout.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+// golden compares output with testdata/<name>.golden, which -update
+// rewrites.
+func golden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.MkdirAll("testdata", 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run go test -update to create it)", err)
+	}
+	if got != string(want) {
+		t.Errorf("output differs from %s (run go test -update to accept it)\n got:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+func TestReportGolden(t *testing.T) {
+	ws := workspace(t)
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"text", []string{"-d", "3"}},
+		{"text-icons-scope", []string{"-d", "3", "-i", "-c"}},
+		{"table-markdown", []string{"-d", "3", "-f", "table", "-s", "markdown"}},
+		{"html", []string{"-d", "3", "-f", "html", "-t", "meeting: Sprint planning"}},
+		{"slack-blocks", []string{"-d", "3", "-f", "slack-blocks"}},
+		{"repos", []string{"repos", "--active", "-d", "3"}},
+	}
+
+	for _, backend := range git.Backends() {
+		for _, tt := range tests {
+			t.Run(backend+"/"+tt.name, func(t *testing.T) {
+				isolate(t)
+				args := append([]string{}, tt.args...)
+				args = append(args, "-p", ws, "-a", "tester", "--git-backend", backend)
+				got := strings.ReplaceAll(gohome(t, args...), ws, "$WORKSPACE")
+				golden(t, tt.name, got)
+
+				// The second run reads the commit cache
+				if again := strings.ReplaceAll(gohome(t, args...), ws, "$WORKSPACE"); again != got {
+					t.Errorf("cached run differs:\n%s\nfirst run:\n%s", again, got)
+				}
+			})
+		}
+	}
+}
+
+func TestValidateOutputStyle(t *testing.T) {
+	tests := []struct {
+		style   string
+		wantErr bool
+	}{
+		{"normal", false},
+		{"Markdown", false},
+		{"mine", false}, // Custom
+		{"unknown", true},
+		{"", true},
+	}
+	for _, tt := range tests {
+		cfg := &config.AppConfig{
+			OutputFmt:   "table",
+			Color:       sys.ColorAuto,
+			Preset:      tt.style,
+			TableStyles: map[string]config.TableStyle{"mine": {Border: "rounded"}},
+		}
+		err := validateOutput(cfg)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("style %q: err = %v, want error %v", tt.style, err, tt.wantErr)
+		}
+		if err != nil && !strings.Contains(err.Error(), "markdown") {
+			t.Errorf("style %q: error %q does not list the styles", tt.style, err)
+		}
+	}
+}
+
+func TestLastReportDelivery(t *testing.T) {
+	ws := workspace(t)
+	isolate(t)
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer broken.Close()
+	writeConfig(t, `{"config_version": 1, "webhooks": {
+		"team": {"type": "generic", "url": "`+server.URL+`", "retries": -1},
+		"broken": {"type": "generic", "url": "`+broken.URL+`", "retries": -1}}}`)
+
+	report := []string{"-d", "3", "-p", ws, "-a", "tester"}
+	lastReport := func() time.Time {
+		t.Helper()
+		last, err := config.LastReport("")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return last
+	}
+
+	// Printed, dry run and failed reports reach no one
+	gohome(t, report...)
+	gohome(t, append(report, "--post", "team", "--dry-run")...)
+	status = http.StatusInternalServerError
+	if err := run(append(report, "--post", "team")); err == nil {
+		t.Error("expected the failed post to be an error")
+	}
+	if last := lastReport(); !last.IsZero() {
+		t.Fatalf("last report = %v before any delivery", last)
+	}
+
+	// A report posted to one webhook reached someone, even when another failed
+	status = http.StatusOK
+	if err := run(append(report, "--post", "broken", "--post", "team")); err == nil || !strings.Contains(err.Error(), "1 of 2 webhook(s) failed") {
+		t.Errorf("err = %v, want the failed webhook reported", err)
+	}
+	if last := lastReport(); !last.Equal(testNow) {
+		t.Errorf("last report = %v, want %v", last, testNow)
+	}
+}
+
+func TestCommandsRejectArguments(t *testing.T) {
+	isolate(t)
+	for _, args := range [][]string{{"stats", "foo"}, {"history", "list", "foo"}, {"tasks", "list", "foo"}, {"foo"}} {
+		err := run(args)
+		if err == nil || !strings.Contains(err.Error(), "expects no arguments") && !strings.Contains(err.Error(), "unknown command") {
+			t.Errorf("gohome %s: err = %v, want the argument refused", strings.Join(args, " "), err)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/anIcedAntFA/gohome/internal/config"
+	"github.com/anIcedAntFA/gohome/internal/git"
+)
+
+// reposCommand describes the repos command.
+func reposCommand(active *bool) *config.Command {
+	return &config.Command{
+		Name:    "repos",
+		Usage:   "gohome repos [flags]",
+		Summary: "List the git repositories found under the scan path.",
+		Examples: []string{
+			"gohome repos -p ~/work",
+			"gohome repos --active -w 1",
+		},
+		Groups: config.PeriodFlags,
+		Flags: func(fs *flag.FlagSet) {
+			fs.BoolVar(active, "active", false, "")
+		},
+		Help: func(w io.Writer) {
+			fmt.Fprintln(w, "       --active\tOnly list repositories with commits by the author in the period")
+		},
+	}
+}
+
+// runRepos lists the repositories with their branch, uncommitted changes
+// and remote and, with --active, the number of commits of the author in the
+// period.
+func runRepos(args []string) error {
+	var active bool
+	cfg, _, err := config.Load(reposCommand(&active), args)
+	if err != nil {
+		return err
+	}
+
+	repos, err := scanRepos(cfg)
+	if err != nil {
+		return err
+	}
+
+	gitClient, err := newBackend(cfg.GitBackend)
+	if err != nil {
+		return err
+	}
+	var author, since string
+	if active {
+		if author, err = resolveAuthor(gitClient, cfg); err != nil {
+			return err
+		}
+		since = gitTime(cfg.PeriodStart(clock()))
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	if active {
+		fmt.Fprintln(w, "REPOSITORY\tCOMMITS\tBRANCH\tSTATUS\tREMOTE\tPATH")
+	} else {
+		fmt.Fprintln(w, "REPOSITORY\tBRANCH\tSTATUS\tREMOTE\tPATH")
+	}
+
+	for _, repo := range repos {
+		remote := git.WebURL(gitClient.GetRemoteURL(context.Background(), repo.path))
+		if remote == "" {
+			remote = "-"
+		}
+		branch, status := repoState(gitClient, repo.path)
+
+		if !active {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", repo.name, branch, status, remote, repo.path)
+			continue
+		}
+
+		logs, err := gitClient.GetLogs(context.Background(), repo.path, author, since)
+		if err != nil {
+			continue
+		}
+		count := 0
+		for _, entry := range logs {
+			if !repo.parser.Ignored(entry.Subject) {
+				count++
+			}
+		}
+		if count > 0 {
+			fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\n", repo.name, count, branch, status, remote, repo.path)
+		}
+	}
+
+	return w.Flush()
+}
+
+// repoState describes the checked out branch and the uncommitted changes of
+// a repository, "-" when they cannot be read.
+func repoState(gitClient git.Backend, path string) (string, string) {
+	branch, status := "-", "-"
+	if current, _, err := gitClient.Branches(context.Background(), path); err == nil {
+		branch = current
+		if branch == "" {
+			branch = "(detached)"
+		}
+	}
+
+	if s, err := gitClient.Status(context.Background(), path); err == nil {
+		var changes []string
+		if s.Staged > 0 {
+			changes = append(changes, fmt.Sprintf("%d staged", s.Staged))
+		}
+		if s.Unstaged > 0 {
+			changes = append(changes, fmt.Sprintf("%d modified", s.Unstaged))
+		}
+		status = "clean"
+		if len(changes) > 0 {
+			status = strings.Join(changes, ", ")
+		}
+	}
+	return branch, status
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/anIcedAntFA/gohome/internal/config"
+	"github.com/anIcedAntFA/gohome/internal/parser"
+	"github.com/anIcedAntFA/gohome/internal/scanner"
+	"github.com/anIcedAntFA/gohome/internal/spinner"
+)
+
+// repository is a scanned repository with its overrides applied.
+type repository struct {
+	path      string
+	name      string // Display name
+	ticketURL string
+	parser    *parser.Service
+}
+
+// scanRepos discovers git repositories under the configured path and reads
+// their overrides. Excluded repositories are left out.
+func scanRepos(cfg *config.AppConfig) ([]repository, error) {
+	absPath, _ := filepath.Abs(cfg.Path)
+
+	sp := spinner.New("🔍 Scanning repositories...").
+		WithFrames(spinner.PacmanGhost).
+		WithInterval(100 * time.Millisecond)
+	sp.Start()
+
+	paths, err := scanner.ScanGitRepos(absPath)
+	sp.Stop()
+	if err != nil {
+		return nil, err
+	}
+
+	repos := make([]repository, 0, len(paths))
+	for _, path := range paths {
+		repo, exclude, err := loadRepository(cfg, path)
+		if err != nil {
+			return nil, err
+		}
+		if !exclude {
+			repos = append(repos, repo)
+		}
+	}
+	return repos, nil
+}
+
+// loadRepository applies the .gohome-repo.* overrides of a repository,
+// unless --ignore-config is given. It reports whether the repository is
+// excluded.
+func loadRepository(cfg *config.AppConfig, path string) (repository, bool, error) {
+	repo := repository{path: path, name: filepath.Base(path), parser: parser.NewService()}
+	if cfg.IgnoreConfig {
+		return repo, false, nil
+	}
+
+	overrides, err := config.LoadRepoConfig(path)
+	if err != nil {
+		return repo, false, err
+	}
+
+	if overrides.Name != "" {
+		repo.name = overrides.Name
+	}
+	repo.ticketURL = overrides.TicketURL
+
+	rules, err := parserRules(overrides)
+	if err != nil {
+		return repo, false, fmt.Errorf("%s: %w", path, err)
+	}
+	repo.parser = repo.parser.WithRules(rules)
+
+	return repo, overrides.Exclude, nil
+}
+
+// parserRules compiles the parsing overrides of a repository.
+func parserRules(overrides *config.RepoConfig) (parser.Rules, error) {
+	rules := parser.Rules{
+		TypeAliases:  overrides.TypeAliases,
+		ScopeAliases: overrides.ScopeAliases,
+	}
+
+	var err error
+	if rules.Patterns, err = compileAll(overrides.ParsePatterns); err != nil {
+		return rules, err
+	}
+	if rules.Ignore, err = compileAll(overrides.Ignore); err != nil {
+		return rules, err
+	}
+	if overrides.TicketPattern != "" {
+		if rules.Tickets, err = regexp.Compile(overrides.TicketPattern); err != nil {
+			return rules, err
+		}
+	}
+	return rules, nil
+}
+
+// compileAll compiles a list of regular expressions.
+func compileAll(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, err
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/anIcedAntFA/gohome/internal/config"
+	"github.com/anIcedAntFA/gohome/internal/entity"
+	"github.com/anIcedAntFA/gohome/internal/tasksource"
+)
+
+// sourceTasks returns the tasks of the task sources done in the report
+// period. Sources that cannot be read are warnings, not a reason to lose
+// the report.
+func sourceTasks(cfg *config.AppConfig, now time.Time) []entity.Task {
+	from := cfg.PeriodStart(now)
+	multiDay := len(entity.ReportDays(from, now)) > 1
+
+	var tasks []entity.Task
+	for _, src := range cfg.TaskSources {
+		items, err := readSource(src, from, now)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️ Warning: cannot read task source %s: %v\n", src.Path, err)
+			continue
+		}
+
+		taskType, icon := tasksource.Defaults(src.Type)
+		if src.TaskType != "" {
+			taskType = src.TaskType
+		}
+		if src.Icon != "" {
+			icon = src.Icon
+		}
+		for _, group := range groupItems(items) {
+			tasks = append(tasks, entity.Task{
+				Type:    taskType,
+				Message: sourceMessage(group, multiDay),
+				Icon:    icon,
+				Enabled: true,
+			})
+		}
+	}
+	return tasks
+}
+
+// readSource returns the items of a task source done between from and
+// now, filtered by its match and ignore rules.
+func readSource(src config.TaskSource, from, now time.Time) ([]tasksource.Item, error) {
+	var match *regexp.Regexp
+	if src.Match != "" {
+		var err error
+		if match, err = regexp.Compile("(?i)" + src.Match); err != nil {
+			return nil, err
+		}
+	}
+	ignore := make([]string, 0, len(src.Ignore))
+	for _, p := range src.Ignore {
+		ignore = append(ignore, "(?i)"+p)
+	}
+	ignored, err := compileAll(ignore)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := tasksource.Read(src.Type, sourcePath(src.Path), from, now)
+	if err != nil {
+		return nil, err
+	}
+
+	kept := items[:0]
+	for _, item := range items {
+		if match != nil && !match.MatchString(item.Message) {
+			continue
+		}
+		if matchesAny(ignored, item.Message) {
+			continue
+		}
+		kept = append(kept, item)
+	}
+	return kept, nil
+}
+
+func matchesAny(patterns []*regexp.Regexp, s string) bool {
+	for _, re := range patterns {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// groupItems gathers the items with the same message, such as the
+// occurrences of a recurring meeting, in the order they first appear.
+func groupItems(items []tasksource.Item) [][]tasksource.Item {
+	var groups [][]tasksource.Item
+	index := make(map[string]int)
+	for _, item := range items {
+		i, ok := index[item.Message]
+		if !ok {
+			i = len(groups)
+			index[item.Message] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], item)
+	}
+	return groups
+}
+
+// sourceMessage describes a group of items: "Standup ×5, 1h15", or
+// "Design review, 1h30 (Wed Oct 14)" for a single item.
+func sourceMessage(group []tasksource.Item, multiDay bool) string {
+	message := group[0].Message
+	if len(group) > 1 {
+		message = fmt.Sprintf("%s ×%d", message, len(group))
+	}
+
+	var total time.Duration
+	for _, item := range group {
+		total += item.Duration
+	}
+	if total > 0 {
+		message += ", " + formatDuration(total)
+	}
+
+	if len(group) > 1 {
+		return message
+	}
+	return datedMessage(message, group[0].Done, multiDay)
+}
+
+// formatDuration writes a duration as "45m", "1h" or "1h30".
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	h, m := int(d.Hours()), int(d.Minutes())%60
+	switch {
+	case h == 0:
+		return fmt.Sprintf("%dm", m)
+	case m == 0:
+		return fmt.Sprintf("%dh", h)
+	default:
+		return fmt.Sprintf("%dh%02d", h, m)
+	}
+}
+
+// sourcePath resolves the path of a task source: ~ is expanded and
+// relative paths start at the directory of the config file.
+func sourcePath(path string) string {
+	path = config.ExpandHome(path)
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(filepath.Dir(config.GetConfigPath()), path)
+}
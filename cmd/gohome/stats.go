@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/anIcedAntFA/gohome/internal/config"
+	"github.com/anIcedAntFA/gohome/internal/entity"
+)
+
+// statsCommand describes the stats command.
+func statsCommand() *config.Command {
+	return &config.Command{
+		Name:    "stats",
+		Usage:   "gohome stats [flags]",
+		Summary: "Show commit statistics per type and repository.",
+		Examples: []string{
+			"gohome stats -w 1",
+			"gohome stats -m 1 -p ~/work",
+		},
+		Groups: config.PeriodFlags,
+	}
+}
+
+// statCount is the number of commits of one type or repository.
+type statCount struct {
+	name  string
+	count int
+}
+
+// runStats prints commit counts per type and per repository.
+func runStats(args []string) error {
+	cfg, rest, err := config.Load(statsCommand(), args)
+	if err != nil {
+		return err
+	}
+	if len(rest) > 0 {
+		return fmt.Errorf("stats expects no arguments (see 'gohome stats --help')")
+	}
+
+	deps, err := initDependencies(cfg)
+	if err != nil {
+		return err
+	}
+
+	report := &entity.Report{Author: deps.author, Period: deps.period, Repos: processCommits(deps)}
+	if report.CommitCount() == 0 {
+		fmt.Fprintln(os.Stderr, "📭 No commits found.")
+		return nil
+	}
+
+	types := make(map[string]int)
+	breaking := 0
+	repos := make([]statCount, 0, len(report.Repos))
+	for _, repo := range report.Repos {
+		repos = append(repos, statCount{name: repo.Name, count: len(repo.Commits)})
+		for _, c := range repo.Commits {
+			types[c.Type]++
+			if c.Breaking {
+				breaking++
+			}
+		}
+	}
+
+	byType := make([]statCount, 0, len(types))
+	for t, n := range types {
+		byType = append(byType, statCount{name: t, count: n})
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	printStats(w, "TYPE", byType, report.CommitCount())
+	fmt.Fprintln(w, "\t\t")
+	printStats(w, "REPOSITORY", repos, report.CommitCount())
+	fmt.Fprintln(w, "\t\t")
+	fmt.Fprintf(w, "TOTAL\t%d\t\n", report.CommitCount())
+	if breaking > 0 {
+		fmt.Fprintf(w, "BREAKING\t%d\t\n", breaking)
+	}
+	return w.Flush()
+}
+
+// printStats prints counts sorted by frequency with their share of the total.
+func printStats(w *tabwriter.Writer, title string, counts []statCount, total int) {
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].count != counts[j].count {
+			return counts[i].count > counts[j].count
+		}
+		return counts[i].name < counts[j].name
+	})
+
+	fmt.Fprintf(w, "%s\tCOMMITS\tSHARE\n", title)
+	for _, c := range counts {
+		fmt.Fprintf(w, "%s\t%d\t%.0f%%\n", c.name, c.count, float64(c.count)*100/float64(total))
+	}
+}
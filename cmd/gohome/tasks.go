@@ -0,0 +1,437 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/anIcedAntFA/gohome/internal/config"
+	"github.com/anIcedAntFA/gohome/internal/entity"
+)
+
+// taskOptions holds the flags of tasks add and tasks edit.
+type taskOptions struct {
+	taskType string
+	icon     string
+	disabled bool
+
+	// Schedule rules, see entity.Schedule
+	weekdays   string
+	everyWeeks int
+	days       string
+	start      string
+	end        string
+	noSchedule bool
+}
+
+// scheduleFlags registers the schedule flags of tasks add and tasks edit.
+func scheduleFlags(fs *flag.FlagSet, opts *taskOptions) {
+	fs.StringVar(&opts.weekdays, "weekdays", "", "")
+	fs.IntVar(&opts.everyWeeks, "every-weeks", 0, "")
+	fs.StringVar(&opts.days, "days", "", "")
+	fs.StringVar(&opts.start, "start", "", "")
+	fs.StringVar(&opts.end, "end", "", "")
+}
+
+// printScheduleFlags prints the help of the schedule flags.
+func printScheduleFlags(w io.Writer) {
+	fmt.Fprintln(w, "       --weekdays <days>\tOnly on these weekdays, e.g. mon,thu")
+	fmt.Fprintln(w, "       --every-weeks <n>\tEvery n weeks, counted from --start")
+	fmt.Fprintln(w, "       --days <days>\tOnly on these days of the month, -1 is the last day")
+	fmt.Fprintln(w, "       --start <date>\tFirst day, YYYY-MM-DD")
+	fmt.Fprintln(w, "       --end <date>\tLast day, YYYY-MM-DD")
+}
+
+// applySchedule sets the schedule rules given as flags on the task.
+func applySchedule(task *entity.Task, opts *taskOptions) error {
+	if opts.noSchedule {
+		task.Schedule = nil
+	}
+	if opts.weekdays == "" && opts.everyWeeks == 0 && opts.days == "" && opts.start == "" && opts.end == "" {
+		return nil
+	}
+
+	schedule := entity.Schedule{}
+	if task.Schedule != nil {
+		schedule = *task.Schedule
+	}
+	if opts.weekdays != "" {
+		schedule.Weekdays = nil
+		for _, day := range strings.Split(opts.weekdays, ",") {
+			schedule.Weekdays = append(schedule.Weekdays, strings.ToLower(strings.TrimSpace(day)))
+		}
+	}
+	if opts.everyWeeks != 0 {
+		schedule.EveryWeeks = opts.everyWeeks
+	}
+	if opts.days != "" {
+		schedule.Days = nil
+		for _, day := range strings.Split(opts.days, ",") {
+			n, err := strconv.Atoi(strings.TrimSpace(day))
+			if err != nil {
+				return fmt.Errorf("invalid day of the month %q", day)
+			}
+			schedule.Days = append(schedule.Days, n)
+		}
+	}
+	if opts.start != "" {
+		schedule.Start = opts.start
+	}
+	if opts.end != "" {
+		schedule.End = opts.end
+	}
+
+	if err := schedule.Validate(); err != nil {
+		return fmt.Errorf("invalid schedule: %w", err)
+	}
+	task.Schedule = &schedule
+	return nil
+}
+
+// tasksCommand describes the tasks command.
+func tasksCommand() *config.Command {
+	return &config.Command{
+		Name:    "tasks",
+		Usage:   "gohome tasks [<command>] [args]",
+		Summary: "Manage the recurring tasks of the config file.",
+		Examples: []string{
+			`gohome tasks add --type meeting "Sprint planning"`,
+			`gohome tasks add "review: Check open pull requests"`,
+			"gohome tasks disable 3",
+			`gohome tasks edit 2 --icon 🧪 "Write e2e tests"`,
+			"gohome tasks remove 4",
+		},
+		Help: func(w io.Writer) {
+			fmt.Fprintln(w, "   list\tList the tasks (default)")
+			fmt.Fprintln(w, "   add [options] <message>\tAdd a task, \"type: message\" sets the type")
+			fmt.Fprintln(w, "   edit <n> [options] [message]\tChange task n")
+			fmt.Fprintln(w, "   enable <n>\tInclude task n in reports")
+			fmt.Fprintln(w, "   disable <n>\tLeave task n out of reports")
+			fmt.Fprintln(w, "   remove <n>\tDelete task n")
+			fmt.Fprintln(w, "\t")
+		},
+	}
+}
+
+// tasksAddCommand describes the tasks add command.
+func tasksAddCommand(opts *taskOptions) *config.Command {
+	return &config.Command{
+		Name:    "tasks add",
+		Usage:   "gohome tasks add [options] <message>",
+		Summary: "Add a recurring task.",
+		Examples: []string{
+			`gohome tasks add --type ops "Check the nightly deploy"`,
+			`gohome tasks add --weekdays mon,tue,wed,thu,fri "meeting: Daily standup"`,
+			`gohome tasks add --weekdays mon --every-weeks 2 --start 2026-01-05 "meeting: Sprint planning"`,
+		},
+		Flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&opts.taskType, "type", "", "")
+			fs.StringVar(&opts.icon, "icon", "", "")
+			fs.BoolVar(&opts.disabled, "disabled", false, "")
+			scheduleFlags(fs, opts)
+		},
+		Help: func(w io.Writer) {
+			fmt.Fprintln(w, "       --type <type>\tTask type, e.g. meeting, review, ops (default misc)")
+			fmt.Fprintln(w, "       --icon <icon>\tIcon, defaults to the icon of the type")
+			fmt.Fprintln(w, "       --disabled\tAdd the task without including it in reports")
+			printScheduleFlags(w)
+		},
+	}
+}
+
+// tasksEditCommand describes the tasks edit command.
+func tasksEditCommand(opts *taskOptions) *config.Command {
+	return &config.Command{
+		Name:     "tasks edit",
+		Usage:    "gohome tasks edit <n> [options] [message]",
+		Summary:  "Change the type, icon, schedule or message of a task.",
+		Examples: []string{"gohome tasks edit 2 --type review", "gohome tasks edit 2 --days 1,-1", `gohome tasks edit 2 "Review the release notes"`},
+		Flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&opts.taskType, "type", "", "")
+			fs.StringVar(&opts.icon, "icon", "", "")
+			scheduleFlags(fs, opts)
+			fs.BoolVar(&opts.noSchedule, "no-schedule", false, "")
+		},
+		Help: func(w io.Writer) {
+			fmt.Fprintln(w, "       --type <type>\tNew task type")
+			fmt.Fprintln(w, "       --icon <icon>\tNew icon, follows the type when not given")
+			printScheduleFlags(w)
+			fmt.Fprintln(w, "       --no-schedule\tHappen every day again, before the other schedule flags")
+		},
+	}
+}
+
+// runTasks dispatches the tasks subcommands.
+func runTasks(args []string) error {
+	cfg, rest, err := config.Load(tasksCommand(), args)
+	if err != nil {
+		return err
+	}
+	if len(rest) == 0 {
+		return listTasks(cfg)
+	}
+
+	sub, params := rest[0], rest[1:]
+	switch sub {
+	case "list":
+		if len(params) > 0 {
+			return fmt.Errorf("tasks list expects no arguments (see 'gohome tasks --help')")
+		}
+		return listTasks(cfg)
+	case "add":
+		return tasksAdd(cfg, params)
+	case "edit":
+		return tasksEdit(cfg, params)
+	case "enable", "disable":
+		return tasksEnable(cfg, params, sub == "enable")
+	case "remove":
+		return tasksRemove(cfg, params)
+	}
+	return fmt.Errorf("unknown tasks command %q (see 'gohome tasks --help')", sub)
+}
+
+// listTasks prints the tasks of the config file with the numbers the other
+// subcommands take. Tasks set in the environment cannot be edited, so they
+// are only pointed out.
+func listTasks(cfg *config.AppConfig) error {
+	file, err := config.OpenFile()
+	if err != nil {
+		return err
+	}
+	key := tasksKey(cfg, file)
+	var tasks []entity.Task
+	if list, ok := file.Get(key); ok {
+		if err := config.Decode(list, &tasks); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	if cfg.Origin("tasks") == config.OriginEnv {
+		fmt.Fprintf(os.Stderr, "⚠️ Warning: %s is set and replaces these tasks in reports\n", config.EnvName("tasks"))
+	}
+
+	if len(tasks) == 0 {
+		fmt.Fprintln(os.Stderr, "📭 No tasks configured.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "#\tENABLED\tTYPE\tSCHEDULE\tTASK")
+	for i, t := range tasks {
+		enabled := "no"
+		if t.Enabled {
+			enabled = "yes"
+		}
+		schedule := "-"
+		if t.Schedule != nil {
+			schedule = t.Schedule.String()
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s %s\n", i+1, enabled, t.Type, schedule, t.Icon, t.Message)
+	}
+	return w.Flush()
+}
+
+// tasksAdd appends a task to the config file.
+func tasksAdd(cfg *config.AppConfig, args []string) error {
+	var opts taskOptions
+	params, err := config.ParseArgs(tasksAddCommand(&opts), args)
+	if err != nil {
+		return err
+	}
+	message := strings.TrimSpace(strings.Join(params, " "))
+	if message == "" {
+		return fmt.Errorf("tasks add expects a message (see 'gohome tasks add --help')")
+	}
+
+	task := entity.ParseTask(message)
+	if opts.taskType != "" {
+		task.Type, task.Message = opts.taskType, message
+		task.Icon = entity.TaskIcon(opts.taskType)
+	}
+	if opts.icon != "" {
+		task.Icon = opts.icon
+	}
+	task.Enabled = !opts.disabled
+	if err := applySchedule(&task, &opts); err != nil {
+		return err
+	}
+
+	file, err := config.OpenFile()
+	if err != nil {
+		return err
+	}
+	key := tasksKey(cfg, file)
+	data, err := json.Marshal(task)
+	if err != nil {
+		return err
+	}
+	if err := file.Append(key, string(data)); err != nil {
+		return err
+	}
+
+	list, _ := file.Get(key)
+	n := len(config.Children(list))
+	if err := saveTasks(file, key); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "✅ Added task %d: %s %s: %s\n", n, task.Icon, task.Type, task.Message)
+	return nil
+}
+
+// tasksEdit changes the fields of a task given as flags or arguments.
+func tasksEdit(cfg *config.AppConfig, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("tasks edit expects a task number (see 'gohome tasks edit --help')")
+	}
+	var opts taskOptions
+	params, err := config.ParseArgs(tasksEditCommand(&opts), args[1:])
+	if err != nil {
+		return err
+	}
+
+	file, err := config.OpenFile()
+	if err != nil {
+		return err
+	}
+	key := tasksKey(cfg, file)
+	itemKey, err := taskKey(file, key, args[0])
+	if err != nil {
+		return err
+	}
+
+	value, _ := file.Get(itemKey)
+	var task entity.Task
+	if err := config.Decode(value, &task); err != nil {
+		return fmt.Errorf("task %s: %w", args[0], err)
+	}
+
+	if opts.taskType != "" {
+		// An icon that came with the old type follows the new one
+		if opts.icon == "" && task.Icon == entity.TaskIcon(task.Type) {
+			task.Icon = entity.TaskIcon(opts.taskType)
+		}
+		task.Type = opts.taskType
+	}
+	if opts.icon != "" {
+		task.Icon = opts.icon
+	}
+	if message := strings.TrimSpace(strings.Join(params, " ")); message != "" {
+		task.Message = message
+	}
+	if err := applySchedule(&task, &opts); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(task)
+	if err != nil {
+		return err
+	}
+	if err := file.Set(itemKey, string(data)); err != nil {
+		return err
+	}
+	if err := saveTasks(file, key); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "✅ Task %s: %s %s: %s\n", args[0], task.Icon, task.Type, task.Message)
+	return nil
+}
+
+// tasksEnable includes a task in reports, or leaves it out.
+func tasksEnable(cfg *config.AppConfig, args []string, enabled bool) error {
+	if len(args) != 1 {
+		return fmt.Errorf("tasks enable and disable expect a task number (see 'gohome tasks --help')")
+	}
+
+	file, err := config.OpenFile()
+	if err != nil {
+		return err
+	}
+	key := tasksKey(cfg, file)
+	itemKey, err := taskKey(file, key, args[0])
+	if err != nil {
+		return err
+	}
+	if err := file.Set(itemKey+".enabled", strconv.FormatBool(enabled)); err != nil {
+		return err
+	}
+	if err := saveTasks(file, key); err != nil {
+		return err
+	}
+
+	state := "disabled"
+	if enabled {
+		state = "enabled"
+	}
+	fmt.Fprintf(os.Stderr, "✅ Task %s %s\n", args[0], state)
+	return nil
+}
+
+// tasksRemove deletes a task from the config file.
+func tasksRemove(cfg *config.AppConfig, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("tasks remove expects a task number (see 'gohome tasks --help')")
+	}
+
+	file, err := config.OpenFile()
+	if err != nil {
+		return err
+	}
+	key := tasksKey(cfg, file)
+	itemKey, err := taskKey(file, key, args[0])
+	if err != nil {
+		return err
+	}
+	if _, err := file.Unset(itemKey); err != nil {
+		return err
+	}
+	if err := saveTasks(file, key); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "✅ Removed task %s\n", args[0])
+	return nil
+}
+
+// tasksKey returns the key of the task list being shown: the tasks of the
+// active profile when it has its own, the base tasks otherwise.
+func tasksKey(cfg *config.AppConfig, file *config.File) string {
+	if cfg.Profile != "" {
+		if key := "profiles." + cfg.Profile + ".tasks"; file.Has(key) {
+			return key
+		}
+	}
+	return "tasks"
+}
+
+// taskKey returns the key of the task numbered n, counting from 1 as
+// 'gohome tasks list' does.
+func taskKey(file *config.File, key, n string) (string, error) {
+	list, _ := file.Get(key)
+	count := len(config.Children(list))
+
+	i, err := strconv.Atoi(n)
+	if err != nil || i < 1 || i > count {
+		if count == 0 {
+			return "", fmt.Errorf("no task %s: no tasks configured", n)
+		}
+		return "", fmt.Errorf("no task %s: use a number from 1 to %d (see 'gohome tasks list')", n, count)
+	}
+	return fmt.Sprintf("%s.%d", key, i-1), nil
+}
+
+// saveTasks checks the edited task list and writes the config file.
+func saveTasks(file *config.File, key string) error {
+	for _, p := range file.Validate() {
+		if p.Key == key || strings.HasPrefix(p.Key, key+".") {
+			return fmt.Errorf("invalid task: %s: %s", p.Key, p.Message)
+		}
+	}
+	if err := file.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	warnRewritten(file)
+	return nil
+}
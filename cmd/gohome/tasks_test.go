@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fileTasks returns the tasks at key in the config file as
+// "<enabled> <type> <icon> <message>" lines.
+func fileTasks(t *testing.T, path, key string) []string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var file map[string]any
+	if err := json.Unmarshal(data, &file); err != nil {
+		t.Fatal(err)
+	}
+	var value any = file
+	for _, part := range strings.Split(key, ".") {
+		value = value.(map[string]any)[part]
+	}
+
+	var out []string
+	for _, item := range value.([]any) {
+		task := item.(map[string]any)
+		out = append(out, fmt.Sprintf("%v %v %v %v", task["enabled"], task["type"], task["icon"], task["message"]))
+	}
+	return out
+}
+
+const tasksConfig = `{
+  "config_version": 1,
+  "tasks": [{"type": "misc", "message": "File A", "icon": "📌", "enabled": true}],
+  "profiles": {
+    "work": {"tasks": [{"type": "ops", "message": "Work task", "icon": "🔧", "enabled": true}]},
+    "home": {"author": "me"}
+  }
+}`
+
+func TestTasksCommands(t *testing.T) {
+	isolate(t)
+	path := writeConfig(t, tasksConfig)
+
+	steps := []struct {
+		args []string
+		key  string
+		want []string
+	}{
+		{[]string{"tasks", "add", "meeting: Standup"}, "tasks", []string{
+			"true misc 📌 File A", "true meeting 📅 Standup",
+		}},
+		{[]string{"tasks", "add", "--type", "ops", "--disabled", "Deploy: https://ci.example.com"}, "tasks", []string{
+			"true misc 📌 File A", "true meeting 📅 Standup", "false ops 🚀 Deploy: https://ci.example.com",
+		}},
+		{[]string{"tasks", "edit", "2", "--type", "review"}, "tasks", []string{
+			"true misc 📌 File A", "true review 👀 Standup", "false ops 🚀 Deploy: https://ci.example.com",
+		}},
+		{[]string{"tasks", "edit", "2", "--icon", "🧪", "Review the release notes"}, "tasks", []string{
+			"true misc 📌 File A", "true review 🧪 Review the release notes", "false ops 🚀 Deploy: https://ci.example.com",
+		}},
+		{[]string{"tasks", "disable", "1"}, "tasks", []string{
+			"false misc 📌 File A", "true review 🧪 Review the release notes", "false ops 🚀 Deploy: https://ci.example.com",
+		}},
+		{[]string{"tasks", "enable", "3"}, "tasks", []string{
+			"false misc 📌 File A", "true review 🧪 Review the release notes", "true ops 🚀 Deploy: https://ci.example.com",
+		}},
+		{[]string{"tasks", "remove", "1"}, "tasks", []string{
+			"true review 🧪 Review the release notes", "true ops 🚀 Deploy: https://ci.example.com",
+		}},
+
+		// A profile with its own tasks edits those, one without edits the base tasks
+		{[]string{"tasks", "--profile", "work", "disable", "1"}, "profiles.work.tasks", []string{"false ops 🔧 Work task"}},
+		{[]string{"tasks", "--profile", "home", "remove", "2"}, "tasks", []string{"true review 🧪 Review the release notes"}},
+	}
+	for _, step := range steps {
+		gohome(t, step.args...)
+		if got := fileTasks(t, path, step.key); !reflect.DeepEqual(got, step.want) {
+			t.Errorf("gohome %s: %s =\n%q\nwant\n%q", strings.Join(step.args, " "), step.key, got, step.want)
+		}
+	}
+
+	for _, args := range [][]string{
+		{"tasks", "edit", "9", "x"},
+		{"tasks", "remove", "0"},
+		{"tasks", "disable", "one"},
+		{"tasks", "add"},
+		{"tasks", "add", "--weekdays", "someday", "x"},
+	} {
+		if err := run(args); err == nil {
+			t.Errorf("gohome %s: expected an error", strings.Join(args, " "))
+		}
+	}
+}
+
+func TestTasksListMatchesEdits(t *testing.T) {
+	isolate(t)
+	path := writeConfig(t, tasksConfig)
+	t.Setenv("GOHOME_TASKS", `[{"type": "misc", "message": "Env X", "enabled": true}]`)
+
+	for _, args := range [][]string{{"tasks", "list"}, {"tasks", "--profile", "home"}} {
+		out := gohome(t, args...)
+		if !strings.Contains(out, "1  ") || !strings.Contains(out, "File A") || strings.Contains(out, "Env X") {
+			t.Errorf("gohome %s lists the tasks it does not edit:\n%s", strings.Join(args, " "), out)
+		}
+	}
+	if out := gohome(t, "tasks", "--profile", "work"); !strings.Contains(out, "Work task") || strings.Contains(out, "File A") {
+		t.Errorf("gohome tasks --profile work does not list the profile tasks:\n%s", out)
+	}
+
+	gohome(t, "tasks", "disable", "1")
+	if got := fileTasks(t, path, "tasks"); got[0] != "false misc 📌 File A" {
+		t.Errorf("tasks = %q, want File A disabled", got)
+	}
+}
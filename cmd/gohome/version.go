@@ -0,0 +1,40 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/anIcedAntFA/gohome/internal/config"
+	"github.com/anIcedAntFA/gohome/internal/version"
+)
+
+// versionCommand describes the version command.
+func versionCommand(short *bool) *config.Command {
+	return &config.Command{
+		Name:    "version",
+		Usage:   "gohome version [--short]",
+		Summary: "Show version information.",
+		Flags: func(fs *flag.FlagSet) {
+			fs.BoolVar(short, "short", false, "")
+		},
+		Help: func(w io.Writer) {
+			fmt.Fprintln(w, "       --short\tPrint the version only, without build details")
+		},
+	}
+}
+
+// runVersion prints the version.
+func runVersion(args []string) error {
+	var short bool
+	if _, err := config.ParseArgs(versionCommand(&short), args); err != nil {
+		return err
+	}
+
+	if short {
+		fmt.Println(version.Short())
+		return nil
+	}
+	fmt.Println(version.String())
+	return nil
+}
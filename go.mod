@@ -2,7 +2,11 @@ module github.com/anIcedAntFA/gohome
 
 go 1.21
 
-require github.com/olekukonko/tablewriter v1.1.2
+require (
+	github.com/BurntSushi/toml v1.3.2
+	github.com/olekukonko/tablewriter v1.1.2
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	github.com/clipperhouse/displaywidth v0.6.0 // indirect
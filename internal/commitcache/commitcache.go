@@ -0,0 +1,131 @@
+// Package commitcache keeps the parsed commits of each repository between
+// runs, so repositories that did not change are not read again and moved
+// ones are only read from where they were.
+package commitcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/anIcedAntFA/gohome/internal/entity"
+)
+
+// DirName is the name of the commit cache in the cache directory.
+const DirName = "commits"
+
+// version changes with the layout of the entries, older entries are
+// ignored.
+const version = 1
+
+// Commit is a parsed commit with the date git log --since filters on.
+type Commit struct {
+	entity.Commit
+	Committed time.Time `json:"committed"`
+}
+
+// Entry holds the commits of an author in a repository: every commit
+// reachable from Head and committed since From, newest first.
+type Entry struct {
+	Version int       `json:"version"`
+	Repo    string    `json:"repo"`
+	Author  string    `json:"author"`
+	Rules   string    `json:"rules"` // Fingerprint of the parse rules
+	Head    string    `json:"head"`
+	From    time.Time `json:"from"`
+	Commits []Commit  `json:"commits"`
+}
+
+// Covers reports whether the entry holds the commits parsed with rules
+// since from, up to some head.
+func (e *Entry) Covers(rules string, from time.Time) bool {
+	return e.Rules == rules && !from.Before(e.From)
+}
+
+// Since returns the commits committed since from, newest first. Like git,
+// it counts whole seconds.
+func (e *Entry) Since(from time.Time) []entity.Commit {
+	from = from.Truncate(time.Second)
+	commits := make([]entity.Commit, 0, len(e.Commits))
+	for _, c := range e.Commits {
+		if !c.Committed.Before(from) {
+			commits = append(commits, c.Commit)
+		}
+	}
+	return commits
+}
+
+// Trim drops the commits committed before from and moves the start of
+// the entry there, so an entry updated run after run does not keep every
+// commit since the first one.
+func (e *Entry) Trim(from time.Time) {
+	from = from.Truncate(time.Second)
+	kept := e.Commits[:0]
+	for _, c := range e.Commits {
+		if !c.Committed.Before(from) {
+			kept = append(kept, c)
+		}
+	}
+	e.Commits = kept
+	e.From = from
+}
+
+// Cache is a directory of entries, one file per repository and author.
+type Cache struct {
+	dir string
+}
+
+// New returns the cache stored in dir, which is created when needed.
+func New(dir string) *Cache {
+	return &Cache{dir: dir}
+}
+
+// path returns the file of the entry of a repository and an author.
+func (c *Cache) path(repo, author string) string {
+	sum := sha256.Sum256([]byte(repo + "\x00" + author))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:16])+".json")
+}
+
+// Load returns the entry of a repository and an author, or nil when there
+// is none. Unreadable entries are treated as missing, they are rebuilt.
+func (c *Cache) Load(repo, author string) *Entry {
+	data, err := os.ReadFile(c.path(repo, author))
+	if err != nil {
+		return nil
+	}
+	var e Entry
+	if err := json.Unmarshal(data, &e); err != nil || e.Version != version || e.Repo != repo || e.Author != author {
+		return nil
+	}
+	return &e
+}
+
+// Store saves an entry. The file is written aside and renamed, so
+// concurrent runs never read half an entry: the last one to finish wins.
+func (c *Cache) Store(e *Entry) error {
+	e.Version = version
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.dir, 0o700); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(c.dir, ".entry-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path(e.Repo, e.Author))
+}
@@ -0,0 +1,105 @@
+package commitcache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/anIcedAntFA/gohome/internal/entity"
+)
+
+var base = time.Date(2026, 10, 12, 9, 0, 0, 0, time.UTC)
+
+func entry() *Entry {
+	return &Entry{
+		Repo:   "/work/api",
+		Author: "tester",
+		Rules:  "r1",
+		Head:   "c3",
+		From:   base,
+		Commits: []Commit{
+			{Commit: entity.Commit{Hash: "c3", Raw: "feat: c"}, Committed: base.Add(48 * time.Hour)},
+			{Commit: entity.Commit{Hash: "c2", Raw: "fix: b"}, Committed: base.Add(24 * time.Hour)},
+			{Commit: entity.Commit{Hash: "c1", Raw: "docs: a"}, Committed: base},
+		},
+	}
+}
+
+func TestStoreAndLoad(t *testing.T) {
+	cache := New(filepath.Join(t.TempDir(), DirName))
+	if cache.Load("/work/api", "tester") != nil {
+		t.Fatal("expected no entry in an empty cache")
+	}
+
+	if err := cache.Store(entry()); err != nil {
+		t.Fatal(err)
+	}
+	got := cache.Load("/work/api", "tester")
+	if got == nil || got.Head != "c3" || len(got.Commits) != 3 || !got.Commits[0].Committed.Equal(base.Add(48*time.Hour)) {
+		t.Fatalf("got %+v", got)
+	}
+
+	// Entries are kept per repository and author
+	if cache.Load("/work/web", "tester") != nil || cache.Load("/work/api", "someone") != nil {
+		t.Error("expected no entry for another repository or author")
+	}
+}
+
+func TestLoadIgnoresBrokenEntries(t *testing.T) {
+	cache := New(t.TempDir())
+	e := entry()
+	if err := cache.Store(e); err != nil {
+		t.Fatal(err)
+	}
+
+	path := cache.path(e.Repo, e.Author)
+	if err := os.WriteFile(path, []byte(`{"version": 1, "repo": "/work/api", "comm`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if cache.Load(e.Repo, e.Author) != nil {
+		t.Error("expected a truncated entry to be ignored")
+	}
+
+	if err := os.WriteFile(path, []byte(`{"version": 99, "repo": "/work/api", "author": "tester"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if cache.Load(e.Repo, e.Author) != nil {
+		t.Error("expected an entry of another version to be ignored")
+	}
+}
+
+func TestCoversAndSince(t *testing.T) {
+	e := entry()
+
+	if !e.Covers("r1", base.Add(time.Hour)) {
+		t.Error("a later start should be covered")
+	}
+	if e.Covers("r1", base.Add(-time.Hour)) {
+		t.Error("an earlier start needs older commits")
+	}
+	if e.Covers("r2", base) {
+		t.Error("other parse rules need the commits to be parsed again")
+	}
+
+	// Like git, the start counts in whole seconds
+	got := e.Since(base.Add(24*time.Hour + 500*time.Millisecond))
+	if len(got) != 2 || got[0].Hash != "c3" || got[1].Hash != "c2" {
+		t.Errorf("got %+v, want c3 and c2", got)
+	}
+}
+
+func TestTrim(t *testing.T) {
+	e := entry()
+	e.Trim(base.Add(24*time.Hour + 500*time.Millisecond))
+
+	if len(e.Commits) != 2 || e.Commits[0].Hash != "c3" || e.Commits[1].Hash != "c2" {
+		t.Errorf("commits = %+v, want c3 and c2", e.Commits)
+	}
+	if !e.From.Equal(base.Add(24 * time.Hour)) {
+		t.Errorf("from = %v, want %v", e.From, base.Add(24*time.Hour))
+	}
+	if e.Covers("r1", base) || !e.Covers("r1", base.Add(24*time.Hour)) {
+		t.Error("a trimmed entry covers only the period it kept")
+	}
+}
@@ -4,15 +4,12 @@ package config
 
 import (
 	"encoding/json"
-	"errors"
-	"flag"
 	"fmt"
 	"os"
-	"path/filepath"
-	"text/tabwriter"
+	"strings"
+	"time"
 
 	"github.com/anIcedAntFA/gohome/internal/entity"
-	"github.com/anIcedAntFA/gohome/internal/version"
 )
 
 // StringSlice is a helper type for capturing multiple -t flag values.
@@ -31,231 +28,225 @@ func (s *StringSlice) Set(value string) error {
 
 // AppConfig maps directly to the JSON file.
 type AppConfig struct {
+	// JSON Schema of the file, lets editors complete and check settings
+	Schema string `json:"$schema,omitempty"`
+	// Version of the file format, see ConfigVersion
+	ConfigVersion int `json:"config_version,omitempty"`
+
 	Hours  int  `json:"hours"`
 	Days   int  `json:"days"`
 	Weeks  int  `json:"weeks"`
 	Months int  `json:"months"`
 	Years  int  `json:"years"`
 	Today  bool `json:"today"`
+	// Report since the last delivered report of the profile, see LastReport
+	SinceLast bool `json:"since_last,omitempty"`
+	// Start of the period found for since_last, zero when there is none
+	Since time.Time `json:"-"`
 
 	Path      string `json:"path"`
 	Author    string `json:"author"`
 	OutputFmt string `json:"format"`
 	Preset    string `json:"preset"`
 
+	// Git backend reading the repositories: "exec" (the git binary) or "native"
+	GitBackend string `json:"git_backend,omitempty"`
+
+	// Custom table styles selectable with --style, keyed by style name
+	TableStyles map[string]TableStyle `json:"table_styles,omitempty"`
+
+	// Color mode: "auto", "always" or "never"
+	Color string `json:"color,omitempty"`
+	// Commit type colors overriding the default theme, e.g. {"feat": "bold green"}
+	Theme map[string]string `json:"theme,omitempty"`
+
 	ShowIcon        bool `json:"show_icon"`
 	ShowScope       bool `json:"show_scope"`
 	CopyToClipboard bool `json:"copy_to_clipboard"`
 
+	// Named webhook destinations used by --post
+	Webhooks map[string]Webhook `json:"webhooks,omitempty"`
+
+	// SMTP settings used by --email
+	Email *Email `json:"email,omitempty"`
+
 	// Static Tasks loaded from JSON file (Rich objects)
 	Tasks []entity.Task `json:"tasks"`
 
+	// Files the done tasks are imported from: todo.txt files and Markdown checklists
+	TaskSources []TaskSource `json:"task_sources,omitempty"`
+
+	// Reports kept in the history per profile and author, 100 when 0,
+	// negative to keep them all
+	HistoryLimit int `json:"history_limit,omitempty"`
+
+	// Named sets of settings overlaid on these ones by --profile
+	Profiles map[string]Profile `json:"profiles,omitempty"`
+	// Profile used when --profile is not given
+	DefaultProfile string `json:"default_profile,omitempty"`
+
+	// Unknown keys are errors unless strict is false
+	Strict *bool `json:"strict,omitempty"`
+
 	// Dynamic Tasks from CLI flags (Simple strings) - This field is not loaded from JSON
 	DynamicTasks StringSlice `json:"-"`
 
+	// Webhook destinations to post the report to, not saved to file
+	Post StringSlice `json:"-"`
+	// Send the report by email, not saved to file
+	SendEmail bool `json:"-"`
+	// Print webhook payloads and emails instead of sending them, not saved to file
+	DryRun bool `json:"-"`
+
+	// Output file for the report (stdout when empty), not saved to file
+	OutputFile string `json:"-"`
+
 	// Special flag to save config, not saved to file
 	SaveConfig bool `json:"-"`
-}
 
-// getConfigFilePath returns the config file path in user's home directory.
-func getConfigFilePath() string {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return "./.gohome.json" // Fallback to current directory if error
-	}
-	return filepath.Join(home, ".gohome.json")
-}
+	// Skip the config file entirely, not saved to file
+	IgnoreConfig bool `json:"-"`
 
-// GetConfigPath exports the config file path for external use.
-func GetConfigPath() string {
-	return getConfigFilePath()
-}
+	// Read every commit from git instead of the commit cache, not saved to file
+	NoCache bool `json:"-"`
 
-// validateConfigPath ensures the config file path is safe from path traversal attacks.
-func validateConfigPath(filePath string) error {
-	// Clean the path to remove any '..' or other unsafe elements
-	cleanPath := filepath.Clean(filePath)
+	// Active profile, from --profile, $GOHOME_PROFILE or default_profile
+	Profile string `json:"-"`
+	// Whether Profile comes from --profile or $GOHOME_PROFILE, which --save
+	// writes to. A default_profile leaves --save to the base settings.
+	profileChosen bool
 
-	// Get absolute path
-	absPath, err := filepath.Abs(cleanPath)
-	if err != nil {
-		return err
-	}
+	// Where each setting comes from, filled by Load
+	origins map[string]Origin
+}
 
-	// Ensure the file is named .gohome.json
-	if filepath.Base(absPath) != ".gohome.json" {
-		return errors.New("invalid config file name")
-	}
+// TableStyle is a user-defined table style.
+type TableStyle struct {
+	Border      string `json:"border"`
+	HeaderColor string `json:"header_color,omitempty"`
+}
 
-	return nil
+// Webhook is a named chat or HTTP destination for reports.
+type Webhook struct {
+	Type    string            `json:"type"` // slack, discord, teams or generic
+	URL     string            `json:"url"`
+	Format  string            `json:"format,omitempty"`  // Report format, defaults per type
+	Headers map[string]string `json:"headers,omitempty"` // Extra HTTP headers
+	Timeout int               `json:"timeout,omitempty"` // Seconds per request (default 10)
+	Retries int               `json:"retries,omitempty"` // Retries after a failure (default 2, negative disables)
 }
 
-// loadConfigFromFile reads and parses the config file if it exists.
-func loadConfigFromFile() AppConfig {
-	filePath := getConfigFilePath()
-	var cfg AppConfig
+// TaskSource is a file done tasks are imported from.
+type TaskSource struct {
+	Type string `json:"type"` // todotxt, markdown or ics
+	// File or directory path, ~ is expanded and relative paths start at the config file
+	Path     string `json:"path"`
+	TaskType string `json:"task_type,omitempty"` // Type of the imported tasks (default todo, meeting for ics)
+	Icon     string `json:"icon,omitempty"`      // Icon of the imported tasks (default ✅, 📅 for ics)
+	// Only tasks matching this regular expression are imported (case-insensitive)
+	Match string `json:"match,omitempty"`
+	// Tasks matching one of these regular expressions are skipped (case-insensitive)
+	Ignore []string `json:"ignore,omitempty"`
+}
 
-	// Validate path to prevent path traversal attacks
-	if err := validateConfigPath(filePath); err != nil {
-		fmt.Printf("⚠️ Warning: Invalid config path: %v\\n", err)
-		return cfg
-	}
+// Email holds SMTP server settings and recipients for --email.
+type Email struct {
+	Host     string   `json:"host"`
+	Port     int      `json:"port,omitempty"`     // Defaults to 587 for starttls, 465 for tls, 25 for none
+	Security string   `json:"security,omitempty"` // starttls (default), tls or none
+	Username string   `json:"username,omitempty"`
+	Password string   `json:"password,omitempty"`
+	From     string   `json:"from"`
+	To       []string `json:"to"`
+	Subject  string   `json:"subject,omitempty"` // Template with {{.Author}}, {{.Period}} and {{.Date}}
+	Timeout  int      `json:"timeout,omitempty"` // Seconds (default 30)
+}
 
-	// Open file
-	// #nosec G304 -- filePath is validated above
-	file, err := os.Open(filePath)
+// loadConfigFromFile reads and validates the config file if it exists.
+// Invalid files are reported as a *ValidationError; unknown keys are only
+// warned about when strict mode is off. The settings are parsed by applyEnv.
+// Older files are upgraded in memory only, the commands that save the config
+// write them back.
+func loadConfigFromFile() (*File, error) {
+	file, err := OpenFile()
 	if err != nil {
-		return cfg // Return default config if file doesn't exist
+		return nil, fmt.Errorf("cannot read config file: %w\n   Fix the file (see 'gohome config edit') or run with --ignore-config", err)
+	}
+	if len(file.data) == 0 {
+		return file, nil // Use the default config if file doesn't exist
+	}
+	if _, ignored := findConfigFile(); len(ignored) > 0 {
+		fmt.Fprintf(os.Stderr, "⚠️ Warning: Several config files found, using %s and ignoring %s\n", file.Path, strings.Join(ignored, ", "))
 	}
 
-	defer func() {
-		if err := file.Close(); err != nil {
-			fmt.Printf("⚠️ Warning: Failed to close file: %v\n", err)
-		}
-	}()
-
-	// Parse JSON into struct
-	decoder := json.NewDecoder(file)
-	if err := decoder.Decode(&cfg); err != nil {
-		// If file format is invalid, skip and use default
-		fmt.Printf("⚠️ Warning: Cannot parse config file at %s: %v\n", filePath, err)
+	problems := file.Validate()
+	if errs := file.Errors(problems); len(errs) > 0 {
+		return nil, &ValidationError{Path: file.Path, Problems: errs}
+	}
+	for _, p := range problems {
+		fmt.Fprintf(os.Stderr, "⚠️ Warning: %s: %s (ignored, strict is off)\n", file.Path, p)
 	}
 
-	return cfg
+	return file, nil
 }
 
-// SaveToFile writes the current config to the config file.
+// SaveToFile writes the current config to the config file, or the settings
+// given as flags to the profile chosen by --profile or $GOHOME_PROFILE. The
+// existing file keeps its format, comments and unknown keys.
 func (c *AppConfig) SaveToFile() error {
-	filePath := getConfigFilePath()
-
-	// Validate path to prevent path traversal attacks
-	if err := validateConfigPath(filePath); err != nil {
-		return fmt.Errorf("invalid config path: %w", err)
-	}
-
-	// If user doesn't have any tasks yet (first time saving), add sample tasks as reference
-	if len(c.Tasks) == 0 {
-		c.Tasks = []entity.Task{
-			// Group 1: Communication
-			{Type: "meeting", Message: "Daily Standup & Team Sync", Icon: "📅", Enabled: false},
-			{Type: "collab", Message: "Pair Programming / Mentoring", Icon: "👥", Enabled: false},
-
-			// Group 2: Quality Assurance
-			{Type: "review", Message: "Code Review & PR Feedback", Icon: "👀", Enabled: true},
-			{Type: "testing", Message: "Write Unit/Integration Tests", Icon: "🧪", Enabled: false},
-
-			// Group 3: Operations
-			{Type: "ops", Message: "Monitor CI/CD Pipelines & Deploy", Icon: "🚀", Enabled: false},
-			{Type: "admin", Message: "Check Emails, Jira & Sentry Logs", Icon: "📮", Enabled: false},
-			// Group 4: Maintenance & Knowledge
-			{Type: "docs", Message: "Update Documentation / Wiki", Icon: "📝", Enabled: false},
-			{Type: "learning", Message: "Tech Research & Knowledge Sharing", Icon: "📚", Enabled: true},
-		}
+	// Update the settings one by one instead of overwriting the file
+	file, err := OpenFile()
+	if err != nil {
+		return err
+	}
+	if c.Profile != "" && c.profileChosen {
+		return c.saveProfile(file)
 	}
 
-	// Create or overwrite file
-	// #nosec G304 -- filePath is validated above
-	file, err := os.Create(filePath)
+	// A new file gets sample tasks as a reference. Existing files keep their
+	// tasks, even an empty list.
+	if file.created && len(c.Tasks) == 0 {
+		c.Tasks = sampleTasks()
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	settings, err := ParseSettings(data)
 	if err != nil {
 		return err
 	}
 
-	defer func() {
-		if err := file.Close(); err != nil {
-			fmt.Printf("⚠️ Warning: Failed to close file: %v\n", err)
+	// Settings from the environment, often secrets, stay out of the file,
+	// and those of a default profile stay in the profile. Profiles are only
+	// changed through --save --profile.
+	settings.root.remove("profiles")
+	for key, origin := range c.origins {
+		if origin != OriginEnv && origin != OriginProfile {
+			continue
 		}
-	}()
-
-	// Write JSON from struct to file
-	encoder := json.NewEncoder(file)
-	encoder.SetIndent("", "  ") // Format JSON nicely (Pretty print)
-	return encoder.Encode(c)
-}
-
-// Load parses command-line flags and returns application configuration.
-// It merges defaults from config file with CLI arguments.
-func Load() *AppConfig {
-	// Check for version flag early (before flag parsing)
-	checkVersionFlag()
-
-	// A. Load defaults from file first (if exists)
-	fileCfg := loadConfigFromFile()
-
-	cfg := &AppConfig{}
-
-	// B. Define all flags
-	defineFlags(cfg)
-
-	flag.Usage = printUsage
-	flag.Parse()
-
-	// Track which flags were explicitly set by user
-	userSetFlags := make(map[string]bool)
-	flag.Visit(func(f *flag.Flag) {
-		userSetFlags[f.Name] = true
-	})
-
-	// C. Merge file config with CLI flags
-	mergeConfigs(cfg, &fileCfg, userSetFlags)
-
-	return cfg
-}
-
-// checkVersionFlag handles early version flag detection.
-func checkVersionFlag() {
-	for _, arg := range os.Args[1:] {
-		if arg == "--version" || arg == "-v" {
-			fmt.Println(getVersion())
-			os.Exit(0)
+		if value, ok := file.Get(key); ok {
+			err = setRaw(settings.root, key, value)
+		} else {
+			unsetRaw(settings.root, key)
+		}
+		if err != nil {
+			return err
 		}
 	}
-}
-
-// defineFlags sets up all command-line flags.
-func defineFlags(cfg *AppConfig) {
-	flag.IntVar(&cfg.Hours, "hours", 0, "")
-	flag.IntVar(&cfg.Hours, "H", 0, "")
-
-	flag.IntVar(&cfg.Days, "days", 0, "")
-	flag.IntVar(&cfg.Days, "d", 0, "")
-
-	flag.IntVar(&cfg.Weeks, "weeks", 0, "")
-	flag.IntVar(&cfg.Weeks, "w", 0, "")
-
-	flag.IntVar(&cfg.Months, "months", 0, "")
-	flag.IntVar(&cfg.Months, "m", 0, "")
-
-	flag.IntVar(&cfg.Years, "years", 0, "")
-	flag.IntVar(&cfg.Years, "y", 0, "")
-
-	flag.BoolVar(&cfg.Today, "today", false, "")
 
-	flag.StringVar(&cfg.Path, "path", ".", "")
-	flag.StringVar(&cfg.Path, "p", ".", "")
-
-	flag.StringVar(&cfg.Author, "author", "", "")
-	flag.StringVar(&cfg.Author, "a", "", "")
-
-	flag.StringVar(&cfg.OutputFmt, "format", "text", "")
-	flag.StringVar(&cfg.OutputFmt, "f", "text", "")
-
-	flag.StringVar(&cfg.Preset, "style", "normal", "")
-	flag.StringVar(&cfg.Preset, "s", "normal", "")
-
-	flag.BoolVar(&cfg.ShowIcon, "icon", false, "")
-	flag.BoolVar(&cfg.ShowIcon, "i", false, "")
-
-	flag.BoolVar(&cfg.ShowScope, "scope", false, "")
-	flag.BoolVar(&cfg.ShowScope, "c", false, "")
-
-	flag.BoolVar(&cfg.CopyToClipboard, "copy", false, "")
-	flag.BoolVar(&cfg.CopyToClipboard, "cp", false, "")
-
-	flag.Var(&cfg.DynamicTasks, "task", "")
-	flag.Var(&cfg.DynamicTasks, "t", "")
+	for _, key := range settings.root.keys {
+		value := settings.root.values[key]
+		if value == nil {
+			continue // Unset lists and maps, the file keeps what it has
+		}
+		if err := file.setValue(key, value); err != nil {
+			return fmt.Errorf("cannot save %s: %w", key, err)
+		}
+	}
 
-	// Add flag for user to save config
-	flag.BoolVar(&cfg.SaveConfig, "save", false, "Save current arguments as default configuration")
+	return file.Save()
 }
 
 // mergeConfigs merges file configuration with CLI flags based on user-set flags.
@@ -269,6 +260,7 @@ func mergeConfigs(cfg, fileCfg *AppConfig, userSetFlags map[string]bool) {
 		cfg.Months = fileCfg.Months
 		cfg.Years = fileCfg.Years
 		cfg.Today = fileCfg.Today
+		cfg.SinceLast = fileCfg.SinceLast
 	}
 
 	// Handle other independent flags
@@ -284,6 +276,12 @@ func mergeConfigs(cfg, fileCfg *AppConfig, userSetFlags map[string]bool) {
 	if !isSet(userSetFlags, "style", "s") && fileCfg.Preset != "" {
 		cfg.Preset = fileCfg.Preset
 	}
+	if !userSetFlags["color"] && fileCfg.Color != "" {
+		cfg.Color = fileCfg.Color
+	}
+	if !userSetFlags["git-backend"] && fileCfg.GitBackend != "" {
+		cfg.GitBackend = fileCfg.GitBackend
+	}
 
 	// Boolean flags
 	if !isSet(userSetFlags, "icon", "i") {
@@ -299,6 +297,15 @@ func mergeConfigs(cfg, fileCfg *AppConfig, userSetFlags map[string]bool) {
 	if len(fileCfg.Tasks) > 0 {
 		cfg.Tasks = fileCfg.Tasks
 	}
+	cfg.TableStyles = fileCfg.TableStyles
+	cfg.Theme = fileCfg.Theme
+	cfg.Webhooks = fileCfg.Webhooks
+	cfg.TaskSources = fileCfg.TaskSources
+	cfg.HistoryLimit = fileCfg.HistoryLimit
+	cfg.Email = fileCfg.Email
+	cfg.Strict = fileCfg.Strict
+	cfg.Profiles = fileCfg.Profiles
+	cfg.DefaultProfile = fileCfg.DefaultProfile
 }
 
 // checkTimeFlags checks if user has set any time-related flag.
@@ -310,6 +317,7 @@ func checkTimeFlags(setFlags map[string]bool) bool {
 		"months", "m",
 		"years", "y",
 		"today",
+		"since-last",
 	}
 	for _, k := range keys {
 		if setFlags[k] {
@@ -324,51 +332,6 @@ func isSet(setFlags map[string]bool, name, alias string) bool {
 	return setFlags[name] || setFlags[alias]
 }
 
-// printUsage displays a custom professional help screen.
-func printUsage() {
-	// Header
-	fmt.Fprintf(os.Stderr, "\n🚀 GO HOME TOOL (Go CLI)\n")
-	fmt.Fprintf(os.Stderr, "A simple tool to aggregate git commit reports.\n\n")
-	fmt.Fprintf(os.Stderr, "Config file: %s\n\n", getConfigFilePath()) // Print config file location for user
-
-	fmt.Fprintf(os.Stderr, "USAGE:\n")
-	fmt.Fprintf(os.Stderr, "  gohome [flags]\n\n")
-
-	fmt.Fprintf(os.Stderr, "EXAMPLES:\n")
-	fmt.Fprintf(os.Stderr, "  gohome -d 3\n")
-	fmt.Fprintf(os.Stderr, "  gohome -f table -s markdown -i -w 1\n\n")
-
-	fmt.Fprintf(os.Stderr, "FLAGS:\n")
-
-	// Use tabwriter to align columns
-	// minwidth=0, tabwidth=8, padding=2, padchar=' ', flags=0
-	w := tabwriter.NewWriter(os.Stderr, 0, 8, 2, ' ', 0)
-
-	// Format: Flags \t Description
-	fmt.Fprintln(w, "   -H, --hours <int>\tNumber of hours to look back")
-	fmt.Fprintln(w, "   -d, --days <int>\tNumber of days to look back")
-	fmt.Fprintln(w, "   -w, --weeks <int>\tNumber of weeks to look back")
-	fmt.Fprintln(w, "   -m, --months <int>\tNumber of months to look back")
-	fmt.Fprintln(w, "   -y, --years <int>\tNumber of years to look back")
-	fmt.Fprintln(w, "       --today\tLook back since midnight today")
-	fmt.Fprintln(w, "\t")
-	fmt.Fprintln(w, "   -p, --path <string>\tRepo path to scan (default \".\")")
-	fmt.Fprintln(w, "   -a, --author <string>\tGit author (auto-detect if empty)")
-	fmt.Fprintln(w, "\t")
-	fmt.Fprintln(w, "   -f, --format <string>\tOutput format: text, table (default \"text\")")
-	fmt.Fprintln(w, "   -s, --style <string>\tPreset style: normal, markdown (default \"normal\")")
-	fmt.Fprintln(w, "   -c, --scope\tShow commit scope")
-	fmt.Fprintln(w, "   -i, --icon\tShow commit type icons")
-	fmt.Fprintln(w, "\t")
-	fmt.Fprintln(w, "  -cp, --copy\tCopy output to system clipboard")
-	fmt.Fprintln(w, "       --save\tSave current arguments as default configuration")
-	fmt.Fprintln(w, "\t")
-	fmt.Fprintln(w, "   -v, --version\tShow version information")
-
-	_ = w.Flush() // Flush buffer to screen
-	fmt.Fprintf(os.Stderr, "\n")
-}
-
 // GetPeriod returns a human-readable time period string based on the configuration.
 // It returns the largest non-zero period value (years > months > weeks > days > hours).
 func (c *AppConfig) GetPeriod() string {
@@ -384,6 +347,11 @@ func (c *AppConfig) GetPeriod() string {
 		{c.Hours, "hour"},
 	}
 
+	// Special case: since the last report
+	if !c.Since.IsZero() {
+		return "since " + c.Since.Format("Mon Jan 2 15:04")
+	}
+
 	// Special case: today flag
 	if c.Today {
 		return "midnight"
@@ -400,6 +368,27 @@ func (c *AppConfig) GetPeriod() string {
 	return "24 hours ago"
 }
 
+// PeriodStart returns when the report period starts, matching GetPeriod.
+func (c *AppConfig) PeriodStart(now time.Time) time.Time {
+	switch {
+	case !c.Since.IsZero():
+		return c.Since
+	case c.Today:
+		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	case c.Years > 0:
+		return now.AddDate(-c.Years, 0, 0)
+	case c.Months > 0:
+		return now.AddDate(0, -c.Months, 0)
+	case c.Weeks > 0:
+		return now.AddDate(0, 0, -7*c.Weeks)
+	case c.Days > 0:
+		return now.AddDate(0, 0, -c.Days)
+	case c.Hours > 0:
+		return now.Add(-time.Duration(c.Hours) * time.Hour)
+	}
+	return now.Add(-24 * time.Hour)
+}
+
 // pluralize adds "s" suffix for plural values.
 func pluralize(n int) string {
 	if n == 1 {
@@ -408,7 +397,22 @@ func pluralize(n int) string {
 	return "s"
 }
 
-// getVersion returns the version string from version package.
-func getVersion() string {
-	return version.String()
+// sampleTasks are written to new config files as examples of tasks.
+func sampleTasks() []entity.Task {
+	return []entity.Task{
+		// Group 1: Communication
+		{Type: "meeting", Message: "Daily Standup & Team Sync", Icon: "📅", Enabled: false},
+		{Type: "collab", Message: "Pair Programming / Mentoring", Icon: "👥", Enabled: false},
+
+		// Group 2: Quality Assurance
+		{Type: "review", Message: "Code Review & PR Feedback", Icon: "👀", Enabled: true},
+		{Type: "testing", Message: "Write Unit/Integration Tests", Icon: "🧪", Enabled: false},
+
+		// Group 3: Operations
+		{Type: "ops", Message: "Monitor CI/CD Pipelines & Deploy", Icon: "🚀", Enabled: false},
+		{Type: "admin", Message: "Check Emails, Jira & Sentry Logs", Icon: "📮", Enabled: false},
+		// Group 4: Maintenance & Knowledge
+		{Type: "docs", Message: "Update Documentation / Wiki", Icon: "📝", Enabled: false},
+		{Type: "learning", Message: "Tech Research & Knowledge Sharing", Icon: "📚", Enabled: true},
+	}
 }
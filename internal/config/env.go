@@ -0,0 +1,181 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+)
+
+// envPrefix starts the environment variables holding settings.
+const envPrefix = "GOHOME_"
+
+// envReserved are the GOHOME_ variables that are not settings.
+var envReserved = map[string]bool{"GOHOME_CONFIG": true, "GOHOME_PROFILE": true}
+
+// EnvName returns the environment variable of a config key: the key in upper
+// case with dots written as double underscores, e.g. GOHOME_SHOW_ICON or
+// GOHOME_EMAIL__PASSWORD.
+func EnvName(key string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "__"))
+}
+
+// secretKeys are the settings holding credentials. They are best given as
+// environment variables and are masked when settings are listed.
+var secretKeys = []string{"email.password", "webhooks.*.url", "webhooks.*.headers.*"}
+
+// MaskSecrets replaces the credentials among the settings, profiles
+// included, with a mask. URLs keep their scheme and host.
+func (f *File) MaskSecrets() error {
+	for _, prefix := range []string{"", "profiles.*."} {
+		for _, pattern := range secretKeys {
+			for _, key := range f.match(prefix + pattern) {
+				value, _ := f.Get(key)
+				if s, ok := value.(string); ok && s != "" {
+					if err := f.setValue(key, maskSecret(s)); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// maskSecret hides a credential, keeping where a URL points to.
+func maskSecret(value string) string {
+	const mask = "********"
+	if u, err := url.Parse(value); err == nil && u.Scheme != "" && u.Host != "" {
+		return u.Scheme + "://" + u.Host + "/" + mask
+	}
+	return mask
+}
+
+// envKey returns the config key of an environment variable.
+func envKey(name string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimPrefix(name, envPrefix), "__", "."))
+}
+
+// loadEnv reads the settings given as GOHOME_<KEY> environment variables.
+// Values are written as with 'gohome config set': lists are comma-separated,
+// objects are JSON. Empty variables are ignored, values of the wrong type
+// are errors and variables that are not settings are warned about.
+func loadEnv(environ []string) (*File, error) {
+	env, err := ParseSettings([]byte("{}"))
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(environ)
+	for _, entry := range environ {
+		name, value, _ := strings.Cut(entry, "=")
+		if !strings.HasPrefix(name, envPrefix) || envReserved[name] || value == "" {
+			continue
+		}
+
+		key := envKey(name)
+		t, err := KeyType(key)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️ Warning: ignoring %s: %v\n", name, err)
+			continue
+		}
+		raw, err := parseValue(t, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid environment variable %s: %w", name, err)
+		}
+		if err := setRaw(env.root, key, raw); err != nil {
+			return nil, fmt.Errorf("invalid environment variable %s: %w", name, err)
+		}
+	}
+
+	return env, nil
+}
+
+// leafKeys returns the keys of the values set in a tree, sorted. Lists
+// are values, they are not merged item by item.
+func (f *File) leafKeys() []string {
+	var keys []string
+	var walk func(key string, value any)
+	walk = func(key string, value any) {
+		obj, ok := value.(*object)
+		if !ok || (key != "" && len(obj.keys) == 0) {
+			keys = append(keys, key)
+			return
+		}
+		for _, k := range obj.keys {
+			walk(joinKey(key, k), obj.values[k])
+		}
+	}
+	walk("", f.root)
+	sort.Strings(keys)
+	return keys
+}
+
+// overlay sets the values of src on top of dst. A period in src replaces
+// all the periods of dst, as the period flags do.
+func overlay(dst *object, src *File) error {
+	keys := src.leafKeys()
+	for _, key := range keys {
+		if timeKeys[key] {
+			for k := range timeKeys {
+				dst.remove(k)
+			}
+			break
+		}
+	}
+	for _, key := range keys {
+		value, _ := src.Get(key)
+		if err := setRaw(dst, key, value); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// resolve returns the settings of the file with the selected profile, then
+// the environment on top. The environment values are checked like the file.
+func resolve(file, profile, env *File) (AppConfig, error) {
+	var cfg AppConfig
+
+	data := []byte("{}")
+	if file != nil {
+		var err error
+		if data, err = file.jsonData(); err != nil {
+			return cfg, err
+		}
+	}
+	merged, err := ParseSettings(data)
+	if err != nil {
+		return cfg, err
+	}
+
+	if profile != nil {
+		if err := overlay(merged.root, profile); err != nil {
+			return cfg, fmt.Errorf("invalid profile: %w", err)
+		}
+	}
+	if err := overlay(merged.root, env); err != nil {
+		return cfg, fmt.Errorf("invalid environment variable: %w", err)
+	}
+
+	// Run the checks of the config file on the values from the environment
+	keys := env.leafKeys()
+	for _, p := range merged.Validate() {
+		for _, key := range keys {
+			if p.Key == key || strings.HasPrefix(p.Key, key+".") || strings.HasPrefix(key, p.Key+".") {
+				return cfg, fmt.Errorf("invalid environment variable %s: %s", EnvName(key), p.Message)
+			}
+		}
+	}
+
+	data, err = merged.jsonData()
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("cannot apply environment settings: %w", err)
+	}
+	return cfg, nil
+}
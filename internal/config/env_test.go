@@ -0,0 +1,172 @@
+package config
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEnvName(t *testing.T) {
+	tests := []struct{ key, name string }{
+		{"author", "GOHOME_AUTHOR"},
+		{"show_icon", "GOHOME_SHOW_ICON"},
+		{"email.password", "GOHOME_EMAIL__PASSWORD"},
+		{"webhooks.team.url", "GOHOME_WEBHOOKS__TEAM__URL"},
+	}
+	for _, tt := range tests {
+		if got := EnvName(tt.key); got != tt.name {
+			t.Errorf("EnvName(%q) = %q, want %q", tt.key, got, tt.name)
+		}
+		if got := envKey(tt.name); got != tt.key {
+			t.Errorf("envKey(%q) = %q, want %q", tt.name, got, tt.key)
+		}
+	}
+}
+
+func TestLoadEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		environ []string
+		want    string // Settings as JSON
+		wantErr string
+	}{
+		{
+			name:    "scalars",
+			environ: []string{"GOHOME_DAYS=3", "GOHOME_SHOW_ICON=true", "GOHOME_FORMAT=table", "HOME=/home/me"},
+			want:    `{"days":3,"format":"table","show_icon":true}`,
+		},
+		{
+			name:    "nested keys and lists",
+			environ: []string{"GOHOME_EMAIL__TO=a@example.com,b@example.com", "GOHOME_EMAIL__PASSWORD=secret"},
+			want:    `{"email":{"password":"secret","to":["a@example.com","b@example.com"]}}`,
+		},
+		{
+			name:    "JSON objects",
+			environ: []string{`GOHOME_WEBHOOKS={"team":{"type":"slack","url":"https://hooks.example.com/x"}}`},
+			want:    `{"webhooks":{"team":{"type":"slack","url":"https://hooks.example.com/x"}}}`,
+		},
+		{
+			name:    "empty, reserved and unknown variables are skipped",
+			environ: []string{"GOHOME_DAYS=", "GOHOME_CONFIG=/tmp/x.json", "GOHOME_PROFILE=work", "GOHOME_COLOUR=auto"},
+			want:    `{}`,
+		},
+		{name: "wrong type", environ: []string{"GOHOME_DAYS=three"}, wantErr: "invalid environment variable GOHOME_DAYS"},
+		{name: "invalid JSON", environ: []string{"GOHOME_WEBHOOKS={"}, wantErr: "invalid environment variable GOHOME_WEBHOOKS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, err := loadEnv(tt.environ)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := env.jsonData()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("settings = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveEnv(t *testing.T) {
+	file, err := parseFile("config.json", FormatJSON, []byte(`{"days": 2, "format": "table", "author": "file"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		environ []string
+		check   func(cfg AppConfig) bool
+		wantErr string
+	}{
+		{"file only", nil, func(cfg AppConfig) bool { return cfg.Days == 2 && cfg.Author == "file" }, ""},
+		{"env over file", []string{"GOHOME_AUTHOR=env"}, func(cfg AppConfig) bool { return cfg.Author == "env" && cfg.OutputFmt == "table" }, ""},
+		{"env period replaces the file period", []string{"GOHOME_WEEKS=1"}, func(cfg AppConfig) bool { return cfg.Weeks == 1 && cfg.Days == 0 }, ""},
+		{"env values are validated", []string{"GOHOME_FORMAT=tabel"}, nil, `invalid environment variable GOHOME_FORMAT: unknown format "tabel"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, err := loadEnv(tt.environ)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := resolve(file, nil, env)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !tt.check(cfg) {
+				t.Errorf("unexpected config %+v", cfg)
+			}
+		})
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	home := isolate(t)
+	touch(t, filepath.Join(home, "xdg", "gohome", "config.json"), `{"author": "file", "format": "table", "path": "/file", "days": 2}`)
+	t.Setenv("GOHOME_FORMAT", "html")
+	t.Setenv("GOHOME_PATH", "/env")
+
+	cmd := &Command{Name: "report", Groups: PeriodFlags | OutputFlags}
+	cfg, _, err := Load(cmd, []string{"-p", "/flag"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string]string{"author": cfg.Author, "format": cfg.OutputFmt, "path": cfg.Path, "preset": cfg.Preset}
+	want := map[string]string{"author": "file", "format": "html", "path": "/flag", "preset": "normal"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("values = %v, want %v", got, want)
+	}
+	origins := map[string]Origin{"author": OriginFile, "format": OriginEnv, "path": OriginFlag, "preset": OriginDefault, "days": OriginFile}
+	for key, origin := range origins {
+		if got := cfg.Origin(key); got != origin {
+			t.Errorf("Origin(%s) = %s, want %s", key, got, origin)
+		}
+	}
+}
+
+func TestMaskSecrets(t *testing.T) {
+	settings, err := ParseSettings([]byte(`{
+  "author": "me",
+  "email": {"host": "smtp.example.com", "password": "hunter2"},
+  "webhooks": {"team": {"url": "https://hooks.slack.com/services/T0/B0/x", "headers": {"Authorization": "Bearer y"}}},
+  "profiles": {"work": {"email": {"password": "p"}, "webhooks": {"ci": {"url": "not a url"}}}}
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := settings.MaskSecrets(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"author":"me","email":{"host":"smtp.example.com","password":"********"},` +
+		`"webhooks":{"team":{"url":"https://hooks.slack.com/********","headers":{"Authorization":"********"}}},` +
+		`"profiles":{"work":{"email":{"password":"********"},"webhooks":{"ci":{"url":"********"}}}}}`
+	got, err := json.Marshal(settings.Root())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != want {
+		t.Errorf("masked settings\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,925 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config file formats, detected from the file extension.
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+	FormatTOML = "toml"
+)
+
+// File is the raw content of the config file. Unlike AppConfig it keeps
+// unknown keys, key order and the position of every key, so single settings
+// can be edited and problems reported with line numbers. YAML and TOML
+// files are edited in place so their comments survive.
+type File struct {
+	Path   string
+	Format string
+
+	data      []byte
+	root      *object
+	pos       map[string]position // Position of each key path
+	yamlDoc   *yaml.Node          // YAML document, edited to keep comments
+	rewritten bool                // Whether an edit had to rewrite the whole file
+	created   bool                // Whether the file did not exist when opened
+	original  []byte              // Content before a migration changed it, backed up by Save
+	from      int                 // config_version before the migrations
+}
+
+// position is a 1-based line and column in the file.
+type position struct {
+	line, col int
+}
+
+// object is a JSON object that remembers the order of its keys.
+type object struct {
+	keys   []string
+	values map[string]any
+}
+
+func newObject() *object {
+	return &object{values: make(map[string]any)}
+}
+
+func (o *object) get(key string) (any, bool) {
+	v, ok := o.values[key]
+	return v, ok
+}
+
+func (o *object) set(key string, value any) {
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = value
+}
+
+func (o *object) remove(key string) bool {
+	if _, ok := o.values[key]; !ok {
+		return false
+	}
+	delete(o.values, key)
+	for i, k := range o.keys {
+		if k == key {
+			o.keys = append(o.keys[:i], o.keys[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// MarshalJSON writes the keys in their original order.
+func (o *object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := marshal(o.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// marshal encodes a value without escaping HTML characters.
+func marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+// Problem is an invalid setting of the config file.
+type Problem struct {
+	Key     string
+	Line    int // 1-based, 0 when unknown
+	Column  int
+	Message string
+	Unknown bool // The key does not exist, tolerated when strict mode is off
+}
+
+func (p Problem) String() string {
+	msg := p.Message
+	if p.Key != "" {
+		msg = p.Key + ": " + msg
+	}
+	if p.Line > 0 {
+		return fmt.Sprintf("line %d, column %d: %s", p.Line, p.Column, msg)
+	}
+	return msg
+}
+
+// Check validates the settings whose key matches Pattern.
+// A "*" segment in the pattern matches any map key or list index, e.g. "webhooks.*".
+type Check struct {
+	Pattern  string
+	Validate func(value any) error
+}
+
+// OpenFile reads the config file and upgrades it in memory. A missing file
+// yields an empty File.
+func OpenFile() (*File, error) {
+	filePath := getConfigFilePath()
+
+	// Validate path to prevent path traversal attacks
+	if err := validateConfigPath(filePath); err != nil {
+		return nil, fmt.Errorf("invalid config path: %w", err)
+	}
+
+	// #nosec G304 -- filePath is validated above
+	data, err := os.ReadFile(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		data, err = nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := parseFile(filePath, formatOf(filePath), data)
+	if err != nil {
+		return nil, err
+	}
+	f.created = data == nil
+	if err := f.upgrade(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// ParseSettings parses JSON settings, such as a marshaled AppConfig,
+// so they can be looked up by key.
+func ParseSettings(data []byte) (*File, error) {
+	return parseFile("", FormatJSON, data)
+}
+
+// formatOf returns the format of a config file from its extension.
+func formatOf(filePath string) string {
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".yaml", ".yml":
+		return FormatYAML
+	case ".toml":
+		return FormatTOML
+	default:
+		return FormatJSON
+	}
+}
+
+// parseFile parses the config file content.
+func parseFile(filePath, format string, data []byte) (*File, error) {
+	f := &File{Path: filePath, Format: format, data: data, pos: make(map[string]position)}
+
+	switch format {
+	case FormatYAML:
+		return f, f.parseYAML()
+	case FormatTOML:
+		return f, f.parseTOML()
+	}
+
+	if len(bytes.TrimSpace(data)) == 0 {
+		f.root = newObject()
+		return f, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	root, err := f.readValue(dec, "")
+	if err == nil && dec.More() {
+		err = errors.New("unexpected data after the top-level object")
+	}
+	if err != nil {
+		return nil, f.syntaxError(err, dec)
+	}
+
+	obj, ok := root.(*object)
+	if !ok {
+		return nil, fmt.Errorf("%s: the config must be a JSON object", filePath)
+	}
+	f.root = obj
+	return f, nil
+}
+
+// syntaxError adds the line and column to a JSON parse error.
+func (f *File) syntaxError(err error, dec *json.Decoder) error {
+	offset := dec.InputOffset()
+	var syntax *json.SyntaxError
+	if errors.As(err, &syntax) && syntax.Offset > 0 {
+		offset = syntax.Offset - 1 // Offset is just past the offending byte
+	}
+	line, col := f.lineColumn(int(offset))
+	return fmt.Errorf("%s:%d:%d: %w", f.Path, line, col, err)
+}
+
+// readValue decodes the next value, recording the position of object keys
+// and array elements under their dotted path.
+func (f *File) readValue(dec *json.Decoder, path string) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tok {
+	case json.Delim('{'):
+		obj := newObject()
+		for dec.More() {
+			start := f.skipSeparators(int(dec.InputOffset()))
+			keyTok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			key, _ := keyTok.(string)
+			keyPath := joinKey(path, key)
+			f.pos[keyPath] = f.position(start)
+
+			value, err := f.readValue(dec, keyPath)
+			if err != nil {
+				return nil, err
+			}
+			obj.set(key, value)
+		}
+		_, err := dec.Token() // Closing brace
+		return obj, err
+
+	case json.Delim('['):
+		list := []any{}
+		for i := 0; dec.More(); i++ {
+			itemPath := joinKey(path, strconv.Itoa(i))
+			f.pos[itemPath] = f.position(f.skipSeparators(int(dec.InputOffset())))
+
+			value, err := f.readValue(dec, itemPath)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		_, err := dec.Token() // Closing bracket
+		return list, err
+	}
+
+	return tok, nil
+}
+
+// skipSeparators moves an offset past whitespace, commas and colons.
+func (f *File) skipSeparators(offset int) int {
+	for offset < len(f.data) && strings.IndexByte(" \t\r\n,:", f.data[offset]) >= 0 {
+		offset++
+	}
+	return offset
+}
+
+// lineColumn converts a byte offset into a 1-based line and column.
+func (f *File) lineColumn(offset int) (int, int) {
+	if offset > len(f.data) {
+		offset = len(f.data)
+	}
+	before := f.data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	col := offset - bytes.LastIndexByte(before, '\n')
+	return line, col
+}
+
+// position converts a byte offset into a position.
+func (f *File) position(offset int) position {
+	line, col := f.lineColumn(offset)
+	return position{line: line, col: col}
+}
+
+// problem builds a Problem located at the key, or at its closest parent
+// present in the file.
+func (f *File) problem(key, msg string) Problem {
+	p := Problem{Key: key, Message: msg}
+	for k := key; k != ""; k = parentKey(k) {
+		if pos, ok := f.pos[k]; ok {
+			p.Line, p.Column = pos.line, pos.col
+			break
+		}
+	}
+	return p
+}
+
+// Root returns the raw top-level object.
+func (f *File) Root() any {
+	return f.root
+}
+
+// Children returns the keys of a raw object or the indexes of a raw list,
+// in order. It returns nil for other values.
+func Children(value any) []string {
+	switch v := value.(type) {
+	case *object:
+		return append([]string{}, v.keys...)
+	case []any:
+		keys := make([]string, len(v))
+		for i := range v {
+			keys[i] = strconv.Itoa(i)
+		}
+		return keys
+	}
+	return nil
+}
+
+// Has reports whether the key is present in the file.
+func (f *File) Has(key string) bool {
+	_, ok := f.Get(key)
+	return ok
+}
+
+// Get returns the raw value of a key in the file.
+func (f *File) Get(key string) (any, bool) {
+	return getRaw(f.root, key)
+}
+
+// getRaw looks up a key in a raw tree. The empty key is the root.
+func getRaw(root *object, key string) (any, bool) {
+	var current any = root
+	if key == "" {
+		return current, true
+	}
+	for _, part := range strings.Split(key, ".") {
+		switch node := current.(type) {
+		case *object:
+			v, ok := node.get(part)
+			if !ok {
+				return nil, false
+			}
+			current = v
+		case []any:
+			i, err := strconv.Atoi(part)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			current = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// Set parses the value according to the type of the key and stores it,
+// creating intermediate objects as needed. Lists of strings accept a JSON
+// array or a comma-separated value; objects and other lists must be JSON.
+func (f *File) Set(key, value string) error {
+	t, err := KeyType(key)
+	if err != nil {
+		return err
+	}
+
+	parsed, err := parseValue(t, value)
+	if err != nil {
+		return fmt.Errorf("invalid value for %s: %w", key, err)
+	}
+
+	return f.setValue(key, parsed)
+}
+
+// Append parses the value according to the type of the list items and adds
+// it at the end of the list, creating the list when the key is not set.
+func (f *File) Append(key, value string) error {
+	t, err := KeyType(key + ".0")
+	if err != nil {
+		return err
+	}
+	parsed, err := parseValue(t, value)
+	if err != nil {
+		return fmt.Errorf("invalid value for %s: %w", key, err)
+	}
+
+	current, _ := f.Get(key)
+	list, isList := current.([]any)
+	if current != nil && !isList {
+		return fmt.Errorf("%s is not a list", key)
+	}
+	// YAML appends the node and TOML a [[table]], so the comments of the
+	// other items stay
+	if f.Format == FormatYAML && isList {
+		return f.setValue(joinKey(key, strconv.Itoa(len(list))), parsed)
+	}
+	if f.Format == FormatTOML && isList {
+		if data, ok := f.tomlAppend(key, parsed); ok {
+			return f.reload(data)
+		}
+	}
+	return f.setValue(key, append(append([]any{}, list...), parsed))
+}
+
+// setValue stores a raw value in the format of the file.
+func (f *File) setValue(key string, value any) error {
+	var data []byte
+	var err error
+
+	switch f.Format {
+	case FormatYAML:
+		data, err = f.yamlSet(key, value)
+	case FormatTOML:
+		data, err = f.tomlSet(key, value)
+	default:
+		if err := setRaw(f.root, key, value); err != nil {
+			return err
+		}
+		data, err = encodeJSON(f.root)
+	}
+	if err != nil {
+		return err
+	}
+
+	return f.reload(data)
+}
+
+// Unset removes a key from the file. It reports whether the key was present.
+func (f *File) Unset(key string) (bool, error) {
+	if !f.Has(key) {
+		return false, nil
+	}
+
+	var data []byte
+	var err error
+
+	switch f.Format {
+	case FormatYAML:
+		data, err = f.yamlUnset(key)
+	case FormatTOML:
+		data, err = f.tomlUnset(key)
+	default:
+		unsetRaw(f.root, key)
+		data, err = encodeJSON(f.root)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, f.reload(data)
+}
+
+// Rewritten reports whether an edit could not be made in place, so the
+// whole file was rewritten and its comments were lost.
+func (f *File) Rewritten() bool {
+	return f.rewritten
+}
+
+// reload parses edited content, refreshing values and positions.
+func (f *File) reload(data []byte) error {
+	edited, err := parseFile(f.Path, f.Format, data)
+	if err != nil {
+		return fmt.Errorf("edit produced an invalid file: %w", err)
+	}
+	edited.rewritten, edited.created = f.rewritten, f.created
+	edited.original, edited.from = f.original, f.from
+	*f = *edited
+	return nil
+}
+
+// Save writes the file back.
+func (f *File) Save() error {
+	if err := validateConfigPath(f.Path); err != nil {
+		return fmt.Errorf("invalid config path: %w", err)
+	}
+
+	if version, err := f.Version(); err == nil && version > ConfigVersion {
+		return fmt.Errorf("%s has config_version %d, this gohome supports up to %d: please upgrade gohome", f.Path, version, ConfigVersion)
+	}
+	if f.created {
+		if err := f.stamp(); err != nil {
+			return err
+		}
+	} else if !f.Has("config_version") {
+		// Version 0 files are up to date once migrated, record it
+		if err := f.setValue("config_version", json.Number(strconv.Itoa(ConfigVersion))); err != nil {
+			return err
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(f.Path), 0o700); err != nil {
+		return err
+	}
+	if err := f.saveBackup(); err != nil {
+		return err
+	}
+	return os.WriteFile(f.Path, f.data, 0o600)
+}
+
+// jsonData returns the settings as JSON, whatever the file format.
+func (f *File) jsonData() ([]byte, error) {
+	return marshal(f.root)
+}
+
+// encodeJSON formats a raw tree as an indented JSON document.
+func encodeJSON(root *object) ([]byte, error) {
+	data, err := marshal(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, "", "  "); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+// setRaw stores a value in a raw tree, creating intermediate objects as needed.
+func setRaw(root *object, key string, value any) error {
+	parts := strings.Split(key, ".")
+	var current any = root
+	for i, part := range parts {
+		last := i == len(parts)-1
+		switch node := current.(type) {
+		case *object:
+			if last {
+				node.set(part, value)
+				return nil
+			}
+			next, ok := node.get(part)
+			if _, isList := next.([]any); !ok || (!isList && !isObject(next)) {
+				next = newObject()
+				node.set(part, next)
+			}
+			current = next
+		case []any:
+			idx, err := strconv.Atoi(part)
+			if err != nil || idx < 0 || idx >= len(node) {
+				return fmt.Errorf("index %s of %s is out of range", part, strings.Join(parts[:i], "."))
+			}
+			if last {
+				node[idx] = value
+				return nil
+			}
+			current = node[idx]
+		}
+	}
+	return nil
+}
+
+// unsetRaw removes a key from a raw tree. It reports whether the key was present.
+func unsetRaw(root *object, key string) bool {
+	parent, last := parentKey(key), key[strings.LastIndex(key, ".")+1:]
+
+	node, ok := getRaw(root, parent)
+	if !ok {
+		return false
+	}
+
+	switch n := node.(type) {
+	case *object:
+		return n.remove(last)
+	case []any:
+		i, err := strconv.Atoi(last)
+		if err != nil || i < 0 || i >= len(n) {
+			return false
+		}
+		// Rebuild the parent list without the element
+		list := append(append([]any{}, n[:i]...), n[i+1:]...)
+		return setRaw(root, parent, list) == nil
+	}
+	return false
+}
+
+// Validate reports unknown keys and values of the wrong type, then runs
+// the built-in value checks and the given ones on the keys they match.
+// Problems are sorted by position.
+func (f *File) Validate(checks ...Check) []Problem {
+	return f.validate(reflect.TypeOf(AppConfig{}), append(f.builtinChecks(), checks...))
+}
+
+// validate checks the file against the type it is decoded into, then runs
+// the checks on the keys they match.
+func (f *File) validate(t reflect.Type, checks []Check) []Problem {
+	var problems []Problem
+	report := func(key, msg string, unknown bool) {
+		p := f.problem(key, msg)
+		p.Unknown = unknown
+		problems = append(problems, p)
+	}
+
+	f.validateValue("", f.root, t, report)
+
+	for _, check := range checks {
+		for _, key := range f.match(check.Pattern) {
+			value, _ := f.Get(key)
+			if value == nil {
+				continue
+			}
+			if err := check.Validate(value); err != nil {
+				report(key, err.Error(), false)
+			}
+		}
+	}
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].Line != problems[j].Line {
+			return problems[i].Line < problems[j].Line
+		}
+		return problems[i].Column < problems[j].Column
+	})
+	return problems
+}
+
+// validateValue checks a raw value against the Go type it is decoded into.
+func (f *File) validateValue(key string, value any, t reflect.Type, report func(key, msg string, unknown bool)) {
+	if value == nil {
+		return // null leaves the default
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	inProfile := t == profileType
+	if inProfile {
+		t = reflect.TypeOf(AppConfig{})
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		obj, ok := value.(*object)
+		if !ok {
+			report(key, "expected an object", false)
+			return
+		}
+		for _, k := range obj.keys {
+			if inProfile && isBaseOnly(k) {
+				report(joinKey(key, k), "can only be set outside profiles", false)
+				continue
+			}
+			field, ok := fieldByKey(t, k)
+			if !ok {
+				report(joinKey(key, k), "unknown key"+suggestKey(t, k), true)
+				continue
+			}
+			f.validateValue(joinKey(key, k), obj.values[k], field.Type, report)
+		}
+
+	case reflect.Map:
+		obj, ok := value.(*object)
+		if !ok {
+			report(key, "expected an object", false)
+			return
+		}
+		for _, k := range obj.keys {
+			f.validateValue(joinKey(key, k), obj.values[k], t.Elem(), report)
+		}
+
+	case reflect.Slice:
+		list, ok := value.([]any)
+		if !ok {
+			report(key, "expected a list", false)
+			return
+		}
+		for i, item := range list {
+			f.validateValue(joinKey(key, strconv.Itoa(i)), item, t.Elem(), report)
+		}
+
+	case reflect.String:
+		if _, ok := value.(string); !ok {
+			report(key, "expected a string", false)
+		}
+
+	case reflect.Bool:
+		if _, ok := value.(bool); !ok {
+			report(key, "expected true or false", false)
+		}
+
+	case reflect.Int:
+		n, ok := value.(json.Number)
+		if _, err := n.Int64(); !ok || err != nil {
+			report(key, "expected an integer", false)
+		}
+	}
+}
+
+// match returns the keys of the file matching a pattern.
+func (f *File) match(pattern string) []string {
+	keys := []string{""}
+	for _, part := range strings.Split(pattern, ".") {
+		var next []string
+		for _, key := range keys {
+			if part != "*" {
+				if f.Has(joinKey(key, part)) {
+					next = append(next, joinKey(key, part))
+				}
+				continue
+			}
+			node := any(f.root)
+			if key != "" {
+				node, _ = f.Get(key)
+			}
+			for _, k := range Children(node) {
+				next = append(next, joinKey(key, k))
+			}
+		}
+		keys = next
+	}
+	return keys
+}
+
+// Decode converts a raw value returned by Get or passed to a Check into v.
+func Decode(value any, v any) error {
+	data, err := marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// KeyType returns the Go type of a config key, e.g. "email.port" is an int.
+// Map entries and list indexes are addressed by their key or position,
+// e.g. "webhooks.team.url" or "tasks.0.enabled".
+func KeyType(key string) (reflect.Type, error) {
+	t := reflect.TypeOf(AppConfig{})
+	inProfile := false
+	for i, part := range strings.Split(key, ".") {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		// Profiles hold the same keys as the base settings
+		if t == profileType {
+			if isBaseOnly(part) {
+				return nil, fmt.Errorf("%s can only be set outside profiles", part)
+			}
+			t, inProfile = reflect.TypeOf(AppConfig{}), true
+		}
+		switch t.Kind() {
+		case reflect.Struct:
+			field, ok := fieldByKey(t, part)
+			if !ok {
+				if i == 0 || inProfile && t == reflect.TypeOf(AppConfig{}) {
+					return nil, fmt.Errorf("unknown config key %q%s", key, suggestKey(t, part))
+				}
+				return nil, fmt.Errorf("unknown config key %q", key)
+			}
+			t = field.Type
+		case reflect.Map:
+			t = t.Elem()
+		case reflect.Slice:
+			if _, err := strconv.Atoi(part); err != nil {
+				return nil, fmt.Errorf("unknown config key %q: %s is a list, use an index", key, part)
+			}
+			t = t.Elem()
+		default:
+			return nil, fmt.Errorf("unknown config key %q", key)
+		}
+	}
+	return t, nil
+}
+
+// Keys returns the top-level config keys in declaration order.
+func Keys() []string {
+	t := reflect.TypeOf(AppConfig{})
+	keys := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		if name := jsonName(t.Field(i)); name != "" {
+			keys = append(keys, name)
+		}
+	}
+	return keys
+}
+
+// parseValue converts a command-line value into the raw form of the type.
+func parseValue(t reflect.Type, value string) (any, error) {
+	switch t.Kind() {
+	case reflect.String:
+		return value, nil
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, errors.New("expected true or false")
+		}
+		return b, nil
+	case reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, errors.New("expected an integer")
+		}
+		return json.Number(strconv.Itoa(n)), nil
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.String && !strings.HasPrefix(strings.TrimSpace(value), "[") {
+			var list []any
+			for _, item := range strings.Split(value, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					list = append(list, item)
+				}
+			}
+			return list, nil
+		}
+	}
+
+	// Objects and lists are given as JSON
+	if err := json.Unmarshal([]byte(value), reflect.New(t).Interface()); err != nil {
+		return nil, fmt.Errorf("expected JSON for %s: %w", t, err)
+	}
+	f, err := parseFile("", FormatJSON, []byte(`{"v":`+value+`}`))
+	if err != nil {
+		return nil, err
+	}
+	v, _ := f.root.get("v")
+	return v, nil
+}
+
+// fieldByKey finds the struct field with the given JSON name.
+func fieldByKey(t reflect.Type, key string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		if field := t.Field(i); jsonName(field) == key {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// jsonName returns the JSON key of a field, empty when it is not stored.
+func jsonName(field reflect.StructField) string {
+	if !field.IsExported() {
+		return ""
+	}
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// suggestKey proposes a known key close to a misspelled one.
+func suggestKey(t reflect.Type, key string) string {
+	best, bestDist := "", 3
+	for i := 0; i < t.NumField(); i++ {
+		name := jsonName(t.Field(i))
+		if name == "" {
+			continue
+		}
+		if d := editDistance(strings.ToLower(key), name); d < bestDist {
+			best, bestDist = name, d
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (did you mean %q?)", best)
+}
+
+// editDistance is the Levenshtein distance between two strings.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr := make([]int, len(b)+1)
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev = curr
+	}
+	return prev[len(b)]
+}
+
+func isObject(v any) bool {
+	_, ok := v.(*object)
+	return ok
+}
+
+func joinKey(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
+
+func parentKey(key string) string {
+	if i := strings.LastIndex(key, "."); i >= 0 {
+		return key[:i]
+	}
+	return ""
+}
@@ -0,0 +1,318 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// problems formats the problems of a file as "line:col key: message".
+func problems(ps []Problem) []string {
+	var out []string
+	for _, p := range ps {
+		out = append(out, fmt.Sprintf("%d:%d %s: %s", p.Line, p.Column, p.Key, p.Message))
+	}
+	return out
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		format string
+		data   string
+		want   string
+	}{
+		{FormatJSON, "{\n  \"days\": 3,\n  \"format\" \"x\"\n}", `config.json:3:12: invalid character '"' after object key`},
+		{FormatJSON, "{\n  \"days\": 3\n} {}", "config.json:3:3: unexpected data after the top-level object"},
+		{FormatJSON, "[1, 2]", "config.json: the config must be a JSON object"},
+		{FormatYAML, "days: 3\n  bad: indent\n", "config.json: yaml: line 2: mapping values are not allowed in this context"},
+		{FormatYAML, "- a\n- b\n", "config.json: the config must be a YAML mapping"},
+		{FormatTOML, "days = 3\nformat = \n", `config.json:2:10: expected value but found '\n' instead`},
+		{FormatTOML, "days = 3\ndays = 4\n", "config.json:2:1: "},
+	}
+
+	for _, tt := range tests {
+		_, err := parseFile("config.json", tt.format, []byte(tt.data))
+		if err == nil {
+			t.Errorf("%s %q: expected an error", tt.format, tt.data)
+			continue
+		}
+		if !strings.HasPrefix(err.Error(), tt.want) {
+			t.Errorf("%s %q: error %q, want %q", tt.format, tt.data, err, tt.want)
+		}
+	}
+}
+
+func TestValidatePositions(t *testing.T) {
+	tests := []struct {
+		format string
+		data   string
+		want   []string
+	}{
+		{
+			FormatJSON,
+			`{
+  "format": "tabel",
+  "colour": "auto",
+  "days": "3",
+  "email": {"port": 1.5, "hots": "x"},
+  "tasks": [{"type": "a", "message": "b", "enabled": "yes"}]
+}`,
+			[]string{
+				`2:3 format: unknown format "tabel" (use text, table, html, slack, slack-blocks)`,
+				`3:3 colour: unknown key (did you mean "color"?)`,
+				"4:3 days: expected an integer",
+				"5:13 email.port: expected an integer",
+				`5:26 email.hots: unknown key (did you mean "host"?)`,
+				"6:43 tasks.0.enabled: expected true or false",
+			},
+		},
+		{
+			FormatYAML,
+			`format: tabel
+colour: auto
+days: "3"
+email:
+  port: 1.5
+  hots: x
+tasks:
+  - type: a
+    message: b
+    enabled: "yes"
+`,
+			[]string{
+				`1:1 format: unknown format "tabel" (use text, table, html, slack, slack-blocks)`,
+				`2:1 colour: unknown key (did you mean "color"?)`,
+				"3:1 days: expected an integer",
+				"5:3 email.port: expected an integer",
+				`6:3 email.hots: unknown key (did you mean "host"?)`,
+				"10:5 tasks.0.enabled: expected true or false",
+			},
+		},
+		{
+			FormatTOML,
+			`format = "tabel"
+colour = "auto"
+days = "3"
+
+[email]
+port = 1.5
+  hots = "x"
+
+[[tasks]]
+type = "a"
+message = "b"
+enabled = "yes"
+`,
+			[]string{
+				`1:1 format: unknown format "tabel" (use text, table, html, slack, slack-blocks)`,
+				`2:1 colour: unknown key (did you mean "color"?)`,
+				"3:1 days: expected an integer",
+				"6:1 email.port: expected an integer",
+				`7:3 email.hots: unknown key (did you mean "host"?)`,
+				"12:1 tasks.0.enabled: expected true or false",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		f, err := parseFile("config", tt.format, []byte(tt.data))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.format, err)
+		}
+		if got := problems(f.Validate()); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: problems\n%s\nwant\n%s", tt.format, strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+		}
+	}
+}
+
+func TestValidateChecks(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []string
+	}{
+		{"valid", `{"format": "table", "preset": "markdown", "days": 3, "theme": {"feat": "bold green"}}`, nil},
+		{"custom style", `{"preset": "mine", "table_styles": {"mine": {"border": "ascii"}}}`, nil},
+		{"unknown style", `{"preset": "mine"}`, []string{`1:2 preset: unknown style "mine" (see 'gohome --style list')`}},
+		{"border set", `{"table_styles": {"mine": {"border": "wavy"}}}`, []string{`1:19 table_styles.mine: unknown border set "wavy" (available: arrow, ascii, blocks, circuit, dotted, double, heavy, light, nature, none, rounded, starry, vintage, zen)`}},
+		{"git backend", `{"git_backend": "libgit2"}`, []string{`1:2 git_backend: unknown git backend "libgit2" (use exec, native)`}},
+		{"null keeps the default", `{"days": null, "email": null}`, nil},
+	}
+
+	for _, tt := range tests {
+		f, err := parseFile("config", FormatJSON, []byte(tt.data))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got := problems(f.Validate()); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: problems %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestStrictMode(t *testing.T) {
+	tests := []struct {
+		data string
+		want int // Errors
+	}{
+		{`{"colour": "auto"}`, 1},
+		{`{"strict": true, "colour": "auto"}`, 1},
+		{`{"strict": false, "colour": "auto"}`, 0},
+		{`{"strict": false, "colour": "auto", "days": "3"}`, 1},
+	}
+
+	for _, tt := range tests {
+		f, err := parseFile("config", FormatJSON, []byte(tt.data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := f.Errors(f.Validate()); len(got) != tt.want {
+			t.Errorf("%s: errors %q, want %d", tt.data, problems(got), tt.want)
+		}
+	}
+}
+
+func TestSetUnsetKeepsFormatting(t *testing.T) {
+	tests := []struct {
+		format   string
+		data     string
+		edited   string
+		restored string // After undoing the edits
+	}{
+		{
+			FormatJSON,
+			"{\n  \"days\": 3,\n  \"format\": \"text\",\n  \"unknown_key\": 1,\n  \"email\": {\n    \"host\": \"smtp\"\n  }\n}\n",
+			"{\n  \"days\": 5,\n  \"unknown_key\": 1,\n  \"email\": {\n    \"host\": \"smtp\",\n    \"port\": 2525,\n    \"to\": [\n      \"a@example.com\",\n      \"b@example.com\"\n    ]\n  },\n  \"author\": \"me\"\n}\n",
+			"{\n  \"days\": 3,\n  \"unknown_key\": 1,\n  \"email\": {\n    \"host\": \"smtp\"\n  },\n  \"format\": \"text\"\n}\n",
+		},
+		{
+			FormatYAML,
+			"# Report settings\ndays: 3 # period\nformat: text\nunknown_key: 1\n# SMTP\nemail:\n  host: smtp # server\n",
+			"# Report settings\ndays: 5 # period\nunknown_key: 1\n# SMTP\nemail:\n  host: smtp # server\n  port: 2525\n  to: [a@example.com, b@example.com]\nauthor: me\n",
+			"# Report settings\ndays: 3 # period\nunknown_key: 1\n# SMTP\nemail:\n  host: smtp # server\nformat: text\n",
+		},
+		{
+			FormatTOML,
+			"# Report settings\ndays = 3 # period\nformat = \"text\"\nunknown_key = 1\n\n# SMTP\n[email]\nhost = \"smtp\" # server\n",
+			"# Report settings\ndays = 5 # period\nunknown_key = 1\nauthor = \"me\"\n\n# SMTP\n[email]\nhost = \"smtp\" # server\nport = 2525\nto = [\"a@example.com\", \"b@example.com\"]\n",
+			"# Report settings\ndays = 3 # period\nunknown_key = 1\nformat = \"text\"\n\n# SMTP\n[email]\nhost = \"smtp\" # server\n",
+		},
+	}
+
+	for _, tt := range tests {
+		f, err := parseFile("config", tt.format, []byte(tt.data))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.format, err)
+		}
+		edit := func(edits ...func() error) {
+			t.Helper()
+			for _, e := range edits {
+				if err := e(); err != nil {
+					t.Fatalf("%s: %v", tt.format, err)
+				}
+			}
+		}
+		unset := func(key string) func() error {
+			return func() error {
+				removed, err := f.Unset(key)
+				if err == nil && !removed {
+					err = fmt.Errorf("%s was not removed", key)
+				}
+				return err
+			}
+		}
+		set := func(key, value string) func() error {
+			return func() error { return f.Set(key, value) }
+		}
+
+		edit(set("days", "5"), set("email.port", "2525"), set("author", "me"), set("email.to", "a@example.com,b@example.com"), unset("format"))
+		if got := string(f.data); got != tt.edited {
+			t.Errorf("%s: edited file\n%s\nwant\n%s", tt.format, got, tt.edited)
+		}
+
+		edit(unset("email.port"), unset("author"), unset("email.to"), set("days", "3"), set("format", "text"))
+		if got := string(f.data); got != tt.restored {
+			t.Errorf("%s: restored file\n%s\nwant\n%s", tt.format, got, tt.restored)
+		}
+		if f.Rewritten() {
+			t.Errorf("%s: the file was rewritten", tt.format)
+		}
+		if problems := f.Validate(); len(problems) != 1 || problems[0].Key != "unknown_key" {
+			t.Errorf("%s: problems %q after the edits", tt.format, problems)
+		}
+	}
+}
+
+func TestAppendKeepsComments(t *testing.T) {
+	tests := []struct {
+		format string
+		data   string
+		want   string
+	}{
+		{
+			FormatYAML,
+			"tasks:\n  # first\n  - type: a # kind\n    message: one\n",
+			"tasks:\n  # first\n  - type: a # kind\n    message: one\n  - type: b\n    message: two\n    schedule:\n      weekdays: [fri]\n",
+		},
+		{
+			FormatTOML,
+			"# tasks\n[[tasks]]\ntype = \"a\" # kind\nmessage = \"one\"\n\n[tasks.schedule]\nweekdays = [\"mon\"]\n\n[email]\nhost = \"smtp\"\n",
+			"# tasks\n[[tasks]]\ntype = \"a\" # kind\nmessage = \"one\"\n\n[tasks.schedule]\nweekdays = [\"mon\"]\n\n[[tasks]]\ntype = \"b\"\nmessage = \"two\"\n\n[tasks.schedule]\nweekdays = [\"fri\"]\n\n[email]\nhost = \"smtp\"\n",
+		},
+	}
+
+	for _, tt := range tests {
+		f, err := parseFile("config", tt.format, []byte(tt.data))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.format, err)
+		}
+		if err := f.Append("tasks", `{"type": "b", "message": "two", "schedule": {"weekdays": ["fri"]}}`); err != nil {
+			t.Fatalf("%s: %v", tt.format, err)
+		}
+		if got := string(f.data); got != tt.want || f.Rewritten() {
+			t.Errorf("%s: file (rewritten %v)\n%s\nwant\n%s", tt.format, f.Rewritten(), got, tt.want)
+		}
+		if value, _ := f.Get("tasks.1.schedule.weekdays.0"); value != "fri" {
+			t.Errorf("%s: tasks.1.schedule.weekdays.0 = %v after reading the file back", tt.format, value)
+		}
+	}
+}
+
+func TestTOMLInlineEditRewrites(t *testing.T) {
+	f, err := parseFile("config", FormatTOML, []byte("# SMTP\nemail = { host = \"smtp\", port = 25 }\ndays = 1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Unset("email.port"); err != nil {
+		t.Fatal(err)
+	}
+	if !f.Rewritten() {
+		t.Error("editing an inline table is not reported as a rewrite")
+	}
+	if want := "days = 1\n\n[email]\nhost = \"smtp\"\n"; string(f.data) != want {
+		t.Errorf("file\n%s\nwant\n%s", f.data, want)
+	}
+}
+
+func TestSetErrors(t *testing.T) {
+	f, err := parseFile("config", FormatJSON, []byte("{}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct{ key, value, want string }{
+		{"colour", "auto", `unknown config key "colour" (did you mean "color"?)`},
+		{"days", "three", "invalid value for days"},
+		{"show_icon", "maybe", "invalid value for show_icon"},
+		{"tasks", "not json", "invalid value for tasks"},
+	}
+	for _, tt := range tests {
+		err := f.Set(tt.key, tt.value)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Set(%q, %q) = %v, want %q", tt.key, tt.value, err, tt.want)
+		}
+	}
+	if string(f.data) != "{}" {
+		t.Errorf("failed edits changed the file: %s", f.data)
+	}
+}
@@ -0,0 +1,752 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// tomlDoc is a line-level view of a TOML file, precise enough to edit
+// single keys without touching comments and formatting.
+type tomlDoc struct {
+	lines    []string
+	root     *tomlSection
+	sections []*tomlSection // Table headers in document order
+	entries  []*tomlEntry   // Key/value lines in document order
+}
+
+// tomlSection is the root table or a [table] / [[array]] header.
+type tomlSection struct {
+	path   string // Key path, with the index of array tables, e.g. "tasks.0"
+	header int    // Line of the header, -1 for the root table
+	end    int    // Last line of the section's content
+}
+
+// tomlEntry is a key = value pair, possibly spanning several lines.
+type tomlEntry struct {
+	path       string
+	start, end int // First and last line
+	col        int // Column of the key
+	valueStart int // Byte offset of the value in the first line
+	valueEnd   int // Byte offset just past the value in the last line
+}
+
+// parseTOML reads a TOML config. Key order and positions come from the
+// line scanner since the decoder does not report them.
+func (f *File) parseTOML() error {
+	var values map[string]any
+	if _, err := toml.Decode(string(f.data), &values); err != nil {
+		var perr toml.ParseError
+		if errors.As(err, &perr) {
+			line, col := f.lineColumn(perr.Position.Start)
+			return fmt.Errorf("%s:%d:%d: %s", f.Path, line, col, tomlMessage(perr))
+		}
+		return fmt.Errorf("%s: %w", f.Path, err)
+	}
+
+	doc := scanTOML(f.data)
+	order := make(map[string]int)
+	remember := func(path string) {
+		for k := path; k != ""; k = parentKey(k) {
+			if _, ok := order[k]; !ok {
+				order[k] = len(order)
+			}
+		}
+	}
+	for _, s := range doc.sections {
+		remember(s.path)
+		f.pos[s.path] = position{line: s.header + 1, col: 1}
+	}
+	for _, e := range doc.entries {
+		remember(e.path)
+		f.pos[e.path] = position{line: e.start + 1, col: e.col + 1}
+	}
+
+	root := fromTOML(values, "", order)
+	f.root = root.(*object)
+	return nil
+}
+
+// tomlErrorPrefix starts the text of TOML parse errors.
+var tomlErrorPrefix = regexp.MustCompile(`^toml: line \d+(?: \(last key ".*?"\))?: `)
+
+// tomlMessage returns the message of a parse error without its position.
+// Lexer errors leave Message empty and only have the full text.
+func tomlMessage(perr toml.ParseError) string {
+	if perr.Message != "" {
+		return perr.Message
+	}
+	return tomlErrorPrefix.ReplaceAllString(perr.Error(), "")
+}
+
+// fromTOML converts decoded TOML values into raw values, ordering the
+// keys as they appear in the file.
+func fromTOML(value any, path string, order map[string]int) any {
+	switch v := value.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.SliceStable(keys, func(i, j int) bool {
+			oi, iok := order[joinKey(path, keys[i])]
+			oj, jok := order[joinKey(path, keys[j])]
+			if iok != jok {
+				return iok
+			}
+			if iok && oi != oj {
+				return oi < oj
+			}
+			return keys[i] < keys[j]
+		})
+
+		obj := newObject()
+		for _, k := range keys {
+			obj.set(k, fromTOML(v[k], joinKey(path, k), order))
+		}
+		return obj
+
+	case []map[string]any:
+		list := make([]any, len(v))
+		for i, item := range v {
+			list[i] = fromTOML(item, joinKey(path, strconv.Itoa(i)), order)
+		}
+		return list
+
+	case []any:
+		list := make([]any, len(v))
+		for i, item := range v {
+			list[i] = fromTOML(item, joinKey(path, strconv.Itoa(i)), order)
+		}
+		return list
+
+	case int64:
+		return json.Number(strconv.FormatInt(v, 10))
+
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return strconv.FormatFloat(v, 'g', -1, 64)
+		}
+		return json.Number(strconv.FormatFloat(v, 'g', -1, 64))
+
+	case time.Time:
+		return v.Format(time.RFC3339)
+	}
+
+	if s, ok := value.(fmt.Stringer); ok {
+		return s.String() // Local dates and times
+	}
+	return value
+}
+
+// tomlSet edits the key in place when possible and rewrites the file otherwise.
+func (f *File) tomlSet(key string, value any) ([]byte, error) {
+	doc := scanTOML(f.data)
+
+	if text, ok := tomlInline(value); ok {
+		if doc.setInline(key, text) {
+			return doc.bytes(), nil
+		}
+	} else if doc.setBlock(key, value) {
+		return doc.bytes(), nil
+	}
+
+	if err := setRaw(f.root, key, value); err != nil {
+		return nil, err
+	}
+	f.rewritten = true
+	return encodeTOML(f.root), nil
+}
+
+// tomlAppend adds a table to an array of tables in place. It reports false
+// when the item or the array is written inline.
+func (f *File) tomlAppend(key string, item any) ([]byte, bool) {
+	if _, ok := tomlInline(item); ok {
+		return nil, false
+	}
+	doc := scanTOML(f.data)
+	if !doc.appendTable(key, item) {
+		return nil, false
+	}
+	return doc.bytes(), true
+}
+
+// tomlUnset removes the lines of a key, or rewrites the file when the key
+// lives inside an inline table or array.
+func (f *File) tomlUnset(key string) ([]byte, error) {
+	doc := scanTOML(f.data)
+	if doc.remove(key) {
+		return doc.bytes(), nil
+	}
+
+	unsetRaw(f.root, key)
+	f.rewritten = true
+	return encodeTOML(f.root), nil
+}
+
+// scanTOML splits a TOML file into sections and entries.
+func scanTOML(data []byte) *tomlDoc {
+	doc := &tomlDoc{lines: strings.Split(string(data), "\n")}
+	doc.root = &tomlSection{header: -1, end: -1}
+	current := doc.root
+	arrays := make(map[string]int) // Number of [[name]] headers seen
+
+	for i := 0; i < len(doc.lines); i++ {
+		line := doc.lines[i]
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || trimmed[0] == '#' {
+			continue
+		}
+
+		if trimmed[0] == '[' {
+			array := strings.HasPrefix(trimmed, "[[")
+			start := strings.IndexByte(line, '[') + 1
+			if array {
+				start++
+			}
+			parts, _, ok := parseTOMLKey(line, start)
+			if !ok {
+				continue
+			}
+
+			name := strings.Join(parts, ".")
+			path := resolveArrayTables(name, arrays)
+			if array {
+				path = joinKey(path, strconv.Itoa(arrays[name]))
+				arrays[name]++
+			}
+
+			current = &tomlSection{path: path, header: i, end: i}
+			doc.sections = append(doc.sections, current)
+			continue
+		}
+
+		keyCol := len(line) - len(strings.TrimLeft(line, " \t"))
+		parts, next, ok := parseTOMLKey(line, keyCol)
+		if !ok || next >= len(line) || line[next] != '=' {
+			continue
+		}
+		valueStart := next + 1
+		for valueStart < len(line) && (line[valueStart] == ' ' || line[valueStart] == '\t') {
+			valueStart++
+		}
+
+		end, valueEnd := scanTOMLValue(doc.lines, i, valueStart)
+		doc.entries = append(doc.entries, &tomlEntry{
+			path:       joinKey(current.path, strings.Join(parts, ".")),
+			start:      i,
+			end:        end,
+			col:        keyCol,
+			valueStart: valueStart,
+			valueEnd:   valueEnd,
+		})
+		current.end = end
+		i = end
+	}
+
+	return doc
+}
+
+// resolveArrayTables adds the current index of enclosing array tables to a
+// table name, e.g. "tasks.meta" becomes "tasks.1.meta" after two [[tasks]].
+func resolveArrayTables(name string, arrays map[string]int) string {
+	best := ""
+	for array := range arrays {
+		if strings.HasPrefix(name, array+".") && len(array) > len(best) {
+			best = array
+		}
+	}
+	if best == "" {
+		return name
+	}
+	return best + "." + strconv.Itoa(arrays[best]-1) + name[len(best):]
+}
+
+// parseTOMLKey reads a dotted key starting at offset i. It returns the key
+// parts and the offset after the key and trailing whitespace.
+func parseTOMLKey(s string, i int) ([]string, int, bool) {
+	var parts []string
+	for {
+		for i < len(s) && (s[i] == ' ' || s[i] == '\t') {
+			i++
+		}
+		if i >= len(s) {
+			return nil, i, false
+		}
+
+		switch s[i] {
+		case '"':
+			j := i + 1
+			for j < len(s) && s[j] != '"' {
+				if s[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(s) {
+				return nil, i, false
+			}
+			part, err := strconv.Unquote(s[i : j+1])
+			if err != nil {
+				part = s[i+1 : j]
+			}
+			parts = append(parts, part)
+			i = j + 1
+		case '\'':
+			j := strings.IndexByte(s[i+1:], '\'')
+			if j < 0 {
+				return nil, i, false
+			}
+			parts = append(parts, s[i+1:i+1+j])
+			i += j + 2
+		default:
+			j := i
+			for j < len(s) && isBareKeyChar(s[j]) {
+				j++
+			}
+			if j == i {
+				return nil, i, false
+			}
+			parts = append(parts, s[i:j])
+			i = j
+		}
+
+		for i < len(s) && (s[i] == ' ' || s[i] == '\t') {
+			i++
+		}
+		if i < len(s) && s[i] == '.' {
+			i++
+			continue
+		}
+		return parts, i, true
+	}
+}
+
+func isBareKeyChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+// scanTOMLValue finds the end of a value starting at lines[line][col],
+// following strings, arrays and inline tables across lines. It returns the
+// last line and the offset just past the value, before any comment.
+func scanTOMLValue(lines []string, line, col int) (int, int) {
+	const (
+		none = iota
+		basic
+		literal
+		multiBasic
+		multiLiteral
+	)
+
+	state, depth := none, 0
+	lastLine, lastCol := line, col
+
+	for li := line; li < len(lines); li++ {
+		s := lines[li]
+		j := 0
+		if li == line {
+			j = col
+		}
+
+	scan:
+		for ; j < len(s); j++ {
+			c := s[j]
+			switch state {
+			case basic:
+				if c == '\\' {
+					j++
+				} else if c == '"' {
+					state = none
+				}
+			case literal:
+				if c == '\'' {
+					state = none
+				}
+			case multiBasic:
+				if c == '\\' {
+					j++
+				} else if strings.HasPrefix(s[j:], `"""`) {
+					state = none
+					j += 2
+				}
+			case multiLiteral:
+				if strings.HasPrefix(s[j:], "'''") {
+					state = none
+					j += 2
+				}
+			default:
+				switch {
+				case c == '#':
+					break scan
+				case strings.HasPrefix(s[j:], `"""`):
+					state = multiBasic
+					j += 2
+				case strings.HasPrefix(s[j:], "'''"):
+					state = multiLiteral
+					j += 2
+				case c == '"':
+					state = basic
+				case c == '\'':
+					state = literal
+				case c == '[' || c == '{':
+					depth++
+				case c == ']' || c == '}':
+					depth--
+				case c == ' ' || c == '\t' || c == '\r':
+					continue
+				}
+			}
+			lastLine, lastCol = li, min(j+1, len(s))
+		}
+
+		// Single-line strings cannot continue on the next line
+		if state == basic || state == literal {
+			state = none
+		}
+		if state == none && depth <= 0 {
+			break
+		}
+	}
+
+	return lastLine, lastCol
+}
+
+// entry returns the entry with the given path, or nil.
+func (d *tomlDoc) entry(path string) *tomlEntry {
+	for _, e := range d.entries {
+		if e.path == path {
+			return e
+		}
+	}
+	return nil
+}
+
+// section returns the table with the given path, or nil. The empty path is the root table.
+func (d *tomlDoc) section(path string) *tomlSection {
+	if path == "" {
+		return d.root
+	}
+	for _, s := range d.sections {
+		if s.path == path {
+			return s
+		}
+	}
+	return nil
+}
+
+// inlined reports whether a parent of the key is an inline value, which
+// cannot be edited line by line.
+func (d *tomlDoc) inlined(key string) bool {
+	for k := parentKey(key); k != ""; k = parentKey(k) {
+		if d.entry(k) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// setInline replaces or inserts a key = value line.
+func (d *tomlDoc) setInline(key, text string) bool {
+	if d.inlined(key) {
+		return false
+	}
+
+	if e := d.entry(key); e != nil {
+		line := d.lines[e.start][:e.valueStart] + text + d.lines[e.end][e.valueEnd:]
+		d.replaceLines(e.start, e.end, line)
+		return true
+	}
+
+	parent, name := parentKey(key), key[strings.LastIndex(key, ".")+1:]
+	line := tomlKey(name) + " = " + text
+
+	if s := d.section(parent); s != nil {
+		d.insertLines(d.insertionPoint(s), line)
+		return true
+	}
+
+	if hasIndex(parent) {
+		return false // Array table elements are only created by setBlock
+	}
+
+	// New table at the end of the file
+	d.appendLines("["+tomlPath(parent)+"]", line)
+	return true
+}
+
+// setBlock replaces a table or array of tables with a newly written one.
+func (d *tomlDoc) setBlock(key string, value any) bool {
+	if hasIndex(key) || d.inlined(key) {
+		return false
+	}
+
+	d.remove(key)
+	var b strings.Builder
+	writeTOMLTable(&b, key, value)
+	d.appendLines(strings.Split(strings.Trim(b.String(), "\n"), "\n")...)
+	return true
+}
+
+// appendTable adds a [[key]] table after the last one of the array. It
+// reports false when the array is not written as tables.
+func (d *tomlDoc) appendTable(key string, item any) bool {
+	last := -1
+	for _, s := range d.sections {
+		if strings.HasPrefix(s.path, key+".") && s.end > last {
+			last = s.end
+		}
+	}
+	if last < 0 || d.inlined(key) {
+		return false
+	}
+
+	var b strings.Builder
+	writeTOMLTable(&b, key, []any{item})
+	d.insertLines(last+1, strings.Split(strings.TrimRight(b.String(), "\n"), "\n")...)
+	return true
+}
+
+// remove deletes the lines of a key: its entry, its tables and the dotted
+// keys below it. It reports whether anything was removed.
+func (d *tomlDoc) remove(key string) bool {
+	type span struct{ start, end int }
+	var spans []span
+
+	below := func(path string) bool {
+		return path == key || strings.HasPrefix(path, key+".")
+	}
+
+	removed := make(map[*tomlSection]bool)
+	for _, s := range d.sections {
+		if below(s.path) {
+			spans = append(spans, span{s.header, s.end})
+			removed[s] = true
+		}
+	}
+	for _, e := range d.entries {
+		if !below(e.path) {
+			continue
+		}
+		inRemoved := false
+		for s := range removed {
+			if e.start > s.header && e.end <= s.end {
+				inRemoved = true
+				break
+			}
+		}
+		if !inRemoved {
+			spans = append(spans, span{e.start, e.end})
+		}
+	}
+
+	if len(spans) == 0 {
+		return false
+	}
+
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start > spans[j].start })
+	blank := func(i int) bool { return i < len(d.lines) && strings.TrimSpace(d.lines[i]) == "" }
+	for _, sp := range spans {
+		d.replaceLines(sp.start, sp.end)
+		// Don't leave two blank lines where a table was
+		if sp.start > 0 && blank(sp.start-1) && blank(sp.start) {
+			d.replaceLines(sp.start, sp.start)
+		}
+	}
+	return true
+}
+
+// insertionPoint returns the line after the last entry of a section. Keys
+// of an empty root table go before the first header and its comments.
+func (d *tomlDoc) insertionPoint(s *tomlSection) int {
+	if s.header >= 0 || s.end >= 0 {
+		return s.end + 1
+	}
+	if len(d.sections) == 0 {
+		return len(d.lines)
+	}
+
+	at := d.sections[0].header
+	for at > 0 && strings.HasPrefix(strings.TrimSpace(d.lines[at-1]), "#") {
+		at--
+	}
+	return at
+}
+
+// replaceLines replaces lines start..end (inclusive) with new ones.
+func (d *tomlDoc) replaceLines(start, end int, lines ...string) {
+	rest := append(append([]string{}, lines...), d.lines[end+1:]...)
+	d.lines = append(d.lines[:start], rest...)
+}
+
+func (d *tomlDoc) insertLines(at int, lines ...string) {
+	rest := append(append([]string{}, lines...), d.lines[at:]...)
+	d.lines = append(d.lines[:at], rest...)
+}
+
+// appendLines adds lines at the end, separated by a blank line.
+func (d *tomlDoc) appendLines(lines ...string) {
+	for len(d.lines) > 0 && strings.TrimSpace(d.lines[len(d.lines)-1]) == "" {
+		d.lines = d.lines[:len(d.lines)-1]
+	}
+	if len(d.lines) > 0 {
+		d.lines = append(d.lines, "")
+	}
+	d.lines = append(d.lines, lines...)
+	d.lines = append(d.lines, "")
+}
+
+func (d *tomlDoc) bytes() []byte {
+	return []byte(strings.Join(d.lines, "\n"))
+}
+
+// encodeTOML writes a whole raw tree as TOML, keeping the key order.
+func encodeTOML(root *object) []byte {
+	var b strings.Builder
+	writeTOMLTable(&b, "", root)
+	return []byte(strings.TrimLeft(b.String(), "\n"))
+}
+
+// writeTOMLTable writes an object as a [table] or a list of objects as
+// [[array]] tables, followed by their nested tables.
+func writeTOMLTable(b *strings.Builder, path string, value any) {
+	if list, ok := value.([]any); ok {
+		for _, item := range list {
+			if obj, ok := item.(*object); ok {
+				fmt.Fprintf(b, "\n[[%s]]\n", tomlPath(path))
+				writeTOMLBody(b, path, obj)
+			}
+		}
+		return
+	}
+
+	obj, ok := value.(*object)
+	if !ok {
+		return
+	}
+
+	// Skip headers of tables that only hold other tables
+	hasInline := false
+	for _, k := range obj.keys {
+		if _, ok := tomlInline(obj.values[k]); ok {
+			hasInline = true
+			break
+		}
+	}
+	if path != "" && (hasInline || len(obj.keys) == 0) {
+		fmt.Fprintf(b, "\n[%s]\n", tomlPath(path))
+	}
+	writeTOMLBody(b, path, obj)
+}
+
+// writeTOMLBody writes the key = value lines of a table, then its sub-tables.
+func writeTOMLBody(b *strings.Builder, path string, obj *object) {
+	var nested []string
+	for _, k := range obj.keys {
+		v := obj.values[k]
+		if v == nil {
+			continue // TOML has no null
+		}
+		if text, ok := tomlInline(v); ok {
+			fmt.Fprintf(b, "%s = %s\n", tomlKey(k), text)
+		} else {
+			nested = append(nested, k)
+		}
+	}
+
+	// Sub-tables of an array item are addressed without its index
+	for _, k := range nested {
+		writeTOMLTable(b, joinKey(path, k), obj.values[k])
+	}
+}
+
+// tomlInline formats scalars and lists of scalars. Objects and lists of
+// objects are written as tables instead.
+func tomlInline(value any) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return tomlString(v), true
+	case bool:
+		return strconv.FormatBool(v), true
+	case json.Number:
+		return v.String(), true
+	case []any:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			text, ok := tomlInline(item)
+			if !ok {
+				return "", false
+			}
+			items = append(items, text)
+		}
+		return "[" + strings.Join(items, ", ") + "]", true
+	}
+	return "", false
+}
+
+// tomlString quotes a TOML basic string.
+func tomlString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04x`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// tomlKey quotes a key when it is not a bare key.
+func tomlKey(key string) string {
+	for i := 0; i < len(key); i++ {
+		if !isBareKeyChar(key[i]) {
+			return tomlString(key)
+		}
+	}
+	if key == "" {
+		return `""`
+	}
+	return key
+}
+
+// tomlPath quotes the parts of a dotted key path.
+func tomlPath(path string) string {
+	parts := strings.Split(path, ".")
+	for i, p := range parts {
+		parts[i] = tomlKey(p)
+	}
+	return strings.Join(parts, ".")
+}
+
+// hasIndex reports whether a key path addresses a list element.
+func hasIndex(path string) bool {
+	for _, part := range strings.Split(path, ".") {
+		if _, err := strconv.Atoi(part); err == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,317 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// parseYAML reads a YAML config, keeping the node tree for in-place edits.
+func (f *File) parseYAML() error {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(f.data, &doc); err != nil {
+		return fmt.Errorf("%s: %w", f.Path, err)
+	}
+
+	// Empty documents (or only comments) start with an empty mapping
+	if doc.Kind == 0 || len(doc.Content) == 0 {
+		comment := doc.HeadComment
+		doc = yaml.Node{Kind: yaml.DocumentNode, HeadComment: comment, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	if doc.Content[0].Kind != yaml.MappingNode {
+		return fmt.Errorf("%s: the config must be a YAML mapping", f.Path)
+	}
+
+	root, err := f.fromYAML(doc.Content[0], "")
+	if err != nil {
+		return fmt.Errorf("%s: %w", f.Path, err)
+	}
+
+	f.yamlDoc = &doc
+	f.root = root.(*object)
+	return nil
+}
+
+// fromYAML converts a node into a raw value, recording key positions.
+func (f *File) fromYAML(node *yaml.Node, path string) (any, error) {
+	switch node.Kind {
+	case yaml.AliasNode:
+		return f.fromYAML(node.Alias, path)
+
+	case yaml.MappingNode:
+		obj := newObject()
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			k, v := node.Content[i], node.Content[i+1]
+
+			// Merge keys (<<: *anchor) copy the keys not defined here
+			if k.ShortTag() == "!!merge" {
+				merged, err := f.fromYAML(v, path)
+				if err != nil {
+					return nil, err
+				}
+				if m, ok := merged.(*object); ok {
+					for _, mk := range m.keys {
+						if _, exists := obj.get(mk); !exists {
+							obj.set(mk, m.values[mk])
+						}
+					}
+				}
+				continue
+			}
+
+			keyPath := joinKey(path, k.Value)
+			f.pos[keyPath] = position{line: k.Line, col: k.Column}
+
+			value, err := f.fromYAML(v, keyPath)
+			if err != nil {
+				return nil, err
+			}
+			obj.set(k.Value, value)
+		}
+		return obj, nil
+
+	case yaml.SequenceNode:
+		list := []any{}
+		for i, item := range node.Content {
+			itemPath := joinKey(path, strconv.Itoa(i))
+			f.pos[itemPath] = position{line: item.Line, col: item.Column}
+
+			value, err := f.fromYAML(item, itemPath)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		return list, nil
+
+	case yaml.ScalarNode:
+		switch node.ShortTag() {
+		case "!!int":
+			var n int64
+			if err := node.Decode(&n); err != nil {
+				return nil, fmt.Errorf("line %d: %w", node.Line, err)
+			}
+			return json.Number(strconv.FormatInt(n, 10)), nil
+		case "!!float":
+			var n float64
+			if err := node.Decode(&n); err != nil {
+				return nil, fmt.Errorf("line %d: %w", node.Line, err)
+			}
+			return json.Number(strconv.FormatFloat(n, 'g', -1, 64)), nil
+		case "!!bool":
+			var b bool
+			if err := node.Decode(&b); err != nil {
+				return nil, fmt.Errorf("line %d: %w", node.Line, err)
+			}
+			return b, nil
+		case "!!null":
+			return nil, nil
+		}
+		return node.Value, nil
+	}
+
+	return nil, fmt.Errorf("line %d: unsupported YAML node", node.Line)
+}
+
+// yamlSet stores a value in the node tree. The replaced node's comments are
+// carried over to the new one.
+func (f *File) yamlSet(key string, value any) ([]byte, error) {
+	node := f.yamlDoc.Content[0]
+	parts := strings.Split(key, ".")
+
+	for i, part := range parts {
+		last := i == len(parts)-1
+		for node.Kind == yaml.AliasNode {
+			node = node.Alias
+		}
+
+		switch node.Kind {
+		case yaml.MappingNode:
+			idx := yamlKeyIndex(node, part)
+			if last {
+				if idx < 0 {
+					node.Content = append(node.Content, yamlKey(part), toYAML(value))
+				} else {
+					node.Content[idx+1] = withComments(toYAML(value), node.Content[idx+1])
+				}
+				return encodeYAML(f.yamlDoc)
+			}
+
+			if idx < 0 {
+				node.Content = append(node.Content, yamlKey(part), &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"})
+				idx = len(node.Content) - 2
+			} else if k := node.Content[idx+1].Kind; k != yaml.MappingNode && k != yaml.SequenceNode && k != yaml.AliasNode {
+				node.Content[idx+1] = withComments(&yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}, node.Content[idx+1])
+			}
+			node = node.Content[idx+1]
+
+		case yaml.SequenceNode:
+			n, err := strconv.Atoi(part)
+			if last && err == nil && n == len(node.Content) {
+				// One past the end appends, see Append
+				node.Content = append(node.Content, toYAML(value))
+				return encodeYAML(f.yamlDoc)
+			}
+			if err != nil || n < 0 || n >= len(node.Content) {
+				return nil, fmt.Errorf("index %s of %s is out of range", part, strings.Join(parts[:i], "."))
+			}
+			if last {
+				node.Content[n] = withComments(toYAML(value), node.Content[n])
+				return encodeYAML(f.yamlDoc)
+			}
+			node = node.Content[n]
+
+		default:
+			return nil, fmt.Errorf("%s is not an object", strings.Join(parts[:i], "."))
+		}
+	}
+
+	return encodeYAML(f.yamlDoc)
+}
+
+// yamlUnset removes a key or list item from the node tree.
+func (f *File) yamlUnset(key string) ([]byte, error) {
+	node := f.yamlDoc.Content[0]
+	parts := strings.Split(key, ".")
+
+	for i, part := range parts {
+		last := i == len(parts)-1
+		for node.Kind == yaml.AliasNode {
+			node = node.Alias
+		}
+
+		var next *yaml.Node
+		switch node.Kind {
+		case yaml.MappingNode:
+			idx := yamlKeyIndex(node, part)
+			if idx < 0 {
+				return nil, errors.New("key not found")
+			}
+			if last {
+				node.Content = append(node.Content[:idx], node.Content[idx+2:]...)
+				return encodeYAML(f.yamlDoc)
+			}
+			next = node.Content[idx+1]
+
+		case yaml.SequenceNode:
+			n, err := strconv.Atoi(part)
+			if err != nil || n < 0 || n >= len(node.Content) {
+				return nil, errors.New("key not found")
+			}
+			if last {
+				node.Content = append(node.Content[:n], node.Content[n+1:]...)
+				return encodeYAML(f.yamlDoc)
+			}
+			next = node.Content[n]
+
+		default:
+			return nil, errors.New("key not found")
+		}
+		node = next
+	}
+
+	return encodeYAML(f.yamlDoc)
+}
+
+// yamlKeyIndex returns the index of a key node in a mapping, or -1.
+func yamlKeyIndex(mapping *yaml.Node, key string) int {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func yamlKey(key string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}
+}
+
+// withComments copies the comments of the node being replaced.
+func withComments(node, old *yaml.Node) *yaml.Node {
+	node.HeadComment = old.HeadComment
+	node.LineComment = old.LineComment
+	node.FootComment = old.FootComment
+	return node
+}
+
+// toYAML converts a raw value into a node. Lists of scalars use the flow
+// style, e.g. [a, b].
+func toYAML(value any) *yaml.Node {
+	switch v := value.(type) {
+	case *object:
+		node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		for _, k := range v.keys {
+			node.Content = append(node.Content, yamlKey(k), toYAML(v.values[k]))
+		}
+		return node
+
+	case []any:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Style: yaml.FlowStyle}
+		for _, item := range v {
+			child := toYAML(item)
+			if child.Kind != yaml.ScalarNode {
+				node.Style = 0
+			}
+			node.Content = append(node.Content, child)
+		}
+		return node
+
+	case string:
+		node := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v}
+		if strings.Contains(v, "\n") {
+			node.Style = yaml.LiteralStyle
+		}
+		return node
+
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(v)}
+
+	case json.Number:
+		tag := "!!int"
+		if strings.ContainsAny(v.String(), ".eE") {
+			tag = "!!float"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: v.String()}
+	}
+
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+}
+
+// encodeYAML formats a document with two-space indentation.
+func encodeYAML(doc *yaml.Node) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return unescapeYAML(buf.Bytes()), nil
+}
+
+// yamlEscape matches the \UXXXXXXXX escapes the encoder writes for characters
+// outside the BMP (emoji icons), unless the backslash is itself escaped.
+var yamlEscape = regexp.MustCompile(`(^|[^\\])((?:\\\\)*)\\U([0-9A-F]{8})`)
+
+// unescapeYAML writes emoji back as they are.
+func unescapeYAML(data []byte) []byte {
+	return yamlEscape.ReplaceAllFunc(data, func(m []byte) []byte {
+		sub := yamlEscape.FindSubmatch(m)
+		code, err := strconv.ParseUint(string(sub[3]), 16, 32)
+		if err != nil {
+			return m
+		}
+		out := append([]byte{}, sub[1]...)
+		out = append(out, sub[2]...)
+		return append(out, string(rune(code))...)
+	})
+}
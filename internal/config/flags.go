@@ -0,0 +1,452 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+)
+
+// FlagGroup selects the groups of flags a command accepts.
+type FlagGroup int
+
+// Flag groups shared by the commands.
+const (
+	// PeriodFlags are the time period, path and author flags.
+	PeriodFlags FlagGroup = 1 << iota
+	// OutputFlags control the rendering and delivery of the report.
+	OutputFlags
+)
+
+// Command describes a command's flags and help screen.
+type Command struct {
+	Name     string   // Command name, e.g. "report"
+	Usage    string   // Synopsis, e.g. "gohome report [flags]"
+	Summary  string   // One-line description
+	Examples []string // Example invocations
+	Groups   FlagGroup
+	// Config accepts --config for commands without flag groups that use the config file
+	Config bool
+
+	// Flags registers command-specific flags, may be nil
+	Flags func(fs *flag.FlagSet)
+	// Help prints command-specific help lines (flags, subcommands), may be nil
+	Help func(w io.Writer)
+}
+
+// Origin tells where the value of a setting comes from.
+type Origin string
+
+// Origins of settings, from lowest to highest precedence.
+const (
+	OriginDefault Origin = "default"
+	OriginFile    Origin = "file"
+	OriginProfile Origin = "profile"
+	OriginEnv     Origin = "env"
+	OriginFlag    Origin = "flag"
+)
+
+// flagKeys maps the flags that override a saved setting to its config key.
+var flagKeys = map[string]string{
+	"hours": "hours", "H": "hours",
+	"days": "days", "d": "days",
+	"weeks": "weeks", "w": "weeks",
+	"months": "months", "m": "months",
+	"years": "years", "y": "years",
+	"today": "today", "since-last": "since_last",
+	"path": "path", "p": "path",
+	"author": "author", "a": "author",
+	"format": "format", "f": "format",
+	"style": "preset", "s": "preset",
+	"icon": "show_icon", "i": "show_icon",
+	"scope": "show_scope", "c": "show_scope",
+	"color": "color",
+	"copy":  "copy_to_clipboard", "cp": "copy_to_clipboard",
+	"git-backend": "git_backend",
+}
+
+// timeKeys are the period settings, overridden as a group by any time flag.
+var timeKeys = map[string]bool{"hours": true, "days": true, "weeks": true, "months": true, "years": true, "today": true, "since_last": true}
+
+// Load parses the command's flags and merges them with the config file.
+// It returns the remaining positional arguments. When the user asks for help
+// it prints the help screen and returns flag.ErrHelp. An invalid config file
+// is an error unless --ignore-config is given.
+func Load(cmd *Command, args []string) (*AppConfig, []string, error) {
+	cfg := &AppConfig{}
+
+	// A. Define the command's flags
+	fs := flag.NewFlagSet(cmd.Name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	defineFlags(fs, cfg, cmd.Groups)
+	fs.BoolVar(&cfg.IgnoreConfig, "ignore-config", false, "")
+	fs.StringVar(&cfg.Profile, "profile", "", "")
+	defineConfigFlag(fs)
+	if cmd.Flags != nil {
+		cmd.Flags(fs)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			printUsage(os.Stderr, cmd, true)
+			return nil, nil, err
+		}
+		return nil, nil, fmt.Errorf("%w (see 'gohome %s --help')", err, cmd.Name)
+	}
+
+	// Track which flags were explicitly set by user
+	userSetFlags := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		userSetFlags[f.Name] = true
+	})
+
+	if err := checkPeriodFlags(cfg); err != nil {
+		return nil, nil, err
+	}
+
+	// B. Load defaults from file (if exists), then the environment on top
+	var file *File
+	if !cfg.IgnoreConfig {
+		if err := checkExplicitConfig(cfg.SaveConfig); err != nil {
+			return nil, nil, err
+		}
+		var err error
+		if file, err = loadConfigFromFile(); err != nil {
+			return nil, nil, err
+		}
+	}
+	env, err := loadEnv(os.Environ())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// C. Select the profile, a new one can be created by --save
+	var profile *File
+	if cfg.IgnoreConfig {
+		cfg.Profile = ""
+	} else if cfg.Profile, cfg.profileChosen = profileName(cfg.Profile, file, env); cfg.Profile != "" {
+		if profile, err = loadProfile(file, cfg.Profile); err != nil && !cfg.SaveConfig {
+			return nil, nil, err
+		}
+	}
+	fileCfg, err := resolve(file, profile, env)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// D. Merge file, profile and environment config with CLI flags
+	mergeConfigs(cfg, &fileCfg, userSetFlags)
+	cfg.origins = origins(file, profile, env, userSetFlags)
+	if cmd.Groups&PeriodFlags != 0 {
+		if err := cfg.resolveSinceLast(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return cfg, fs.Args(), nil
+}
+
+// checkExplicitConfig fails when the config file given with --config or
+// $GOHOME_CONFIG does not exist, unless it is about to be created.
+func checkExplicitConfig(saving bool) error {
+	path := explicitConfigPath()
+	if path == "" || saving {
+		return nil
+	}
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("cannot read config file: %w", err)
+	}
+	return nil
+}
+
+// checkPeriodFlags rejects negative time periods given on the command line.
+func checkPeriodFlags(cfg *AppConfig) error {
+	periods := []struct {
+		name  string
+		value int
+	}{
+		{"hours", cfg.Hours},
+		{"days", cfg.Days},
+		{"weeks", cfg.Weeks},
+		{"months", cfg.Months},
+		{"years", cfg.Years},
+	}
+
+	for _, p := range periods {
+		if p.value < 0 {
+			return fmt.Errorf("--%s must not be negative, got %d", p.name, p.value)
+		}
+	}
+	return nil
+}
+
+// origins records which settings come from the file, the profile, the
+// environment and flags. A layer setting a period takes over all periods.
+func origins(file, profile, env *File, userSetFlags map[string]bool) map[string]Origin {
+	result := make(map[string]Origin)
+	record := func(keys []string, origin Origin) {
+		if slices.ContainsFunc(keys, func(key string) bool { return timeKeys[key] }) {
+			for key := range timeKeys {
+				delete(result, key)
+			}
+		}
+		for _, key := range keys {
+			result[key] = origin
+		}
+	}
+
+	if file != nil {
+		var keys []string
+		for _, key := range Keys() {
+			if file.Has(key) {
+				keys = append(keys, key)
+			}
+		}
+		record(keys, OriginFile)
+	}
+	if profile != nil {
+		record(profile.leafKeys(), OriginProfile)
+	}
+	record(env.leafKeys(), OriginEnv)
+
+	var flagged []string
+	for name := range userSetFlags {
+		if key, ok := flagKeys[name]; ok {
+			flagged = append(flagged, key)
+		}
+	}
+	record(flagged, OriginFlag)
+
+	return result
+}
+
+// Origin returns where the value of a key comes from. Nested keys inherit
+// the origin of their closest recorded parent.
+func (c *AppConfig) Origin(key string) Origin {
+	for k := key; k != ""; k = parentKey(k) {
+		if origin, ok := c.origins[k]; ok {
+			return origin
+		}
+	}
+	return OriginDefault
+}
+
+// ParseArgs parses only the command-specific flags, without loading the
+// config file. It is meant for commands that do not need the configuration.
+func ParseArgs(cmd *Command, args []string) ([]string, error) {
+	fs := flag.NewFlagSet(cmd.Name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	if cmd.Config {
+		defineConfigFlag(fs)
+	}
+	if cmd.Flags != nil {
+		cmd.Flags(fs)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			printUsage(os.Stderr, cmd, false)
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w (see 'gohome %s --help')", err, cmd.Name)
+	}
+
+	return fs.Args(), nil
+}
+
+// FlagNames returns the long flag names of a command, for shell completion.
+func FlagNames(cmd *Command) []string {
+	fs := flag.NewFlagSet(cmd.Name, flag.ContinueOnError)
+	defineFlags(fs, &AppConfig{}, cmd.Groups)
+	if cmd.Groups != 0 || cmd.Config {
+		fs.String("config", "", "")
+	}
+	if cmd.Groups != 0 {
+		fs.Bool("ignore-config", false, "")
+		fs.String("profile", "", "")
+	}
+	if cmd.Flags != nil {
+		cmd.Flags(fs)
+	}
+
+	var names []string
+	fs.VisitAll(func(f *flag.Flag) {
+		if len(f.Name) > 2 {
+			names = append(names, "--"+f.Name)
+		}
+	})
+	return names
+}
+
+// defineConfigFlag sets up --config. The value is only stored when the flag
+// is given, so a command loading the config again keeps it.
+func defineConfigFlag(fs *flag.FlagSet) {
+	fs.Func("config", "", func(path string) error {
+		configFlag = path
+		return nil
+	})
+}
+
+// defineFlags sets up the command-line flags of the selected groups.
+func defineFlags(fs *flag.FlagSet, cfg *AppConfig, groups FlagGroup) {
+	if groups&PeriodFlags != 0 {
+		definePeriodFlags(fs, cfg)
+	}
+	if groups&OutputFlags != 0 {
+		defineOutputFlags(fs, cfg)
+	}
+}
+
+// definePeriodFlags sets up the time period, path and author flags.
+func definePeriodFlags(fs *flag.FlagSet, cfg *AppConfig) {
+	fs.IntVar(&cfg.Hours, "hours", 0, "")
+	fs.IntVar(&cfg.Hours, "H", 0, "")
+
+	fs.IntVar(&cfg.Days, "days", 0, "")
+	fs.IntVar(&cfg.Days, "d", 0, "")
+
+	fs.IntVar(&cfg.Weeks, "weeks", 0, "")
+	fs.IntVar(&cfg.Weeks, "w", 0, "")
+
+	fs.IntVar(&cfg.Months, "months", 0, "")
+	fs.IntVar(&cfg.Months, "m", 0, "")
+
+	fs.IntVar(&cfg.Years, "years", 0, "")
+	fs.IntVar(&cfg.Years, "y", 0, "")
+
+	fs.BoolVar(&cfg.Today, "today", false, "")
+	fs.BoolVar(&cfg.SinceLast, "since-last", false, "")
+
+	fs.StringVar(&cfg.Path, "path", ".", "")
+	fs.StringVar(&cfg.Path, "p", ".", "")
+
+	fs.StringVar(&cfg.Author, "author", "", "")
+	fs.StringVar(&cfg.Author, "a", "", "")
+
+	fs.StringVar(&cfg.GitBackend, "git-backend", "exec", "")
+	fs.BoolVar(&cfg.NoCache, "no-cache", false, "")
+}
+
+// defineOutputFlags sets up the rendering and delivery flags.
+func defineOutputFlags(fs *flag.FlagSet, cfg *AppConfig) {
+	fs.StringVar(&cfg.OutputFmt, "format", "text", "")
+	fs.StringVar(&cfg.OutputFmt, "f", "text", "")
+
+	fs.StringVar(&cfg.Preset, "style", "normal", "")
+	fs.StringVar(&cfg.Preset, "s", "normal", "")
+
+	fs.BoolVar(&cfg.ShowIcon, "icon", false, "")
+	fs.BoolVar(&cfg.ShowIcon, "i", false, "")
+
+	fs.BoolVar(&cfg.ShowScope, "scope", false, "")
+	fs.BoolVar(&cfg.ShowScope, "c", false, "")
+
+	fs.StringVar(&cfg.Color, "color", "auto", "")
+
+	fs.BoolVar(&cfg.CopyToClipboard, "copy", false, "")
+	fs.BoolVar(&cfg.CopyToClipboard, "cp", false, "")
+
+	fs.StringVar(&cfg.OutputFile, "output", "", "")
+	fs.StringVar(&cfg.OutputFile, "o", "", "")
+
+	fs.Var(&cfg.Post, "post", "")
+	fs.BoolVar(&cfg.SendEmail, "email", false, "")
+	fs.BoolVar(&cfg.DryRun, "dry-run", false, "")
+
+	fs.Var(&cfg.DynamicTasks, "task", "")
+	fs.Var(&cfg.DynamicTasks, "t", "")
+
+	// Add flag for user to save config
+	fs.BoolVar(&cfg.SaveConfig, "save", false, "Save current arguments as default configuration")
+}
+
+// PrintUsage displays the help screen of a command.
+func PrintUsage(out io.Writer, cmd *Command) {
+	printUsage(out, cmd, cmd.Groups != 0)
+}
+
+// printUsage displays the help screen, with --ignore-config for commands
+// reading the config file.
+func printUsage(out io.Writer, cmd *Command, readsConfig bool) {
+	// Header
+	fmt.Fprintf(out, "\n%s\n\n", cmd.Summary)
+	fmt.Fprintf(out, "Config file: %s\n\n", getConfigFilePath()) // Print config file location for user
+
+	fmt.Fprintf(out, "USAGE:\n")
+	fmt.Fprintf(out, "  %s\n\n", cmd.Usage)
+
+	if len(cmd.Examples) > 0 {
+		fmt.Fprintf(out, "EXAMPLES:\n")
+		fmt.Fprintf(out, "  %s\n\n", strings.Join(cmd.Examples, "\n  "))
+	}
+
+	if cmd.Groups == 0 && cmd.Help == nil && !readsConfig && !cmd.Config {
+		return
+	}
+
+	fmt.Fprintf(out, "FLAGS:\n")
+
+	// Use tabwriter to align columns
+	// minwidth=0, tabwidth=8, padding=2, padchar=' ', flags=0
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+
+	if cmd.Groups&PeriodFlags != 0 {
+		printPeriodFlags(w)
+	}
+	if cmd.Groups&OutputFlags != 0 {
+		printOutputFlags(w)
+	}
+	if cmd.Help != nil {
+		cmd.Help(w)
+	}
+	if readsConfig || cmd.Config {
+		fmt.Fprintln(w, "       --config <file>\tUse this config file (default: $GOHOME_CONFIG, then discovered)")
+	}
+	if readsConfig {
+		fmt.Fprintln(w, "       --profile <name>\tApply a profile of the config file (default: $GOHOME_PROFILE or default_profile)")
+		fmt.Fprintln(w, "       --ignore-config\tRun with defaults, without reading the config file")
+	}
+	fmt.Fprintln(w, "   -h, --help\tShow this help")
+
+	_ = w.Flush() // Flush buffer to screen
+	fmt.Fprintf(out, "\n")
+}
+
+// printPeriodFlags prints the help lines of the period flags.
+func printPeriodFlags(w io.Writer) {
+	// Format: Flags \t Description
+	fmt.Fprintln(w, "   -H, --hours <int>\tNumber of hours to look back")
+	fmt.Fprintln(w, "   -d, --days <int>\tNumber of days to look back")
+	fmt.Fprintln(w, "   -w, --weeks <int>\tNumber of weeks to look back")
+	fmt.Fprintln(w, "   -m, --months <int>\tNumber of months to look back")
+	fmt.Fprintln(w, "   -y, --years <int>\tNumber of years to look back")
+	fmt.Fprintln(w, "       --today\tLook back since midnight today")
+	fmt.Fprintln(w, "       --since-last\tLook back since the last copied, posted or emailed report of the profile")
+	fmt.Fprintln(w, "\t")
+	fmt.Fprintln(w, "   -p, --path <string>\tRepo path to scan (default \".\")")
+	fmt.Fprintln(w, "   -a, --author <string>\tGit author (auto-detect if empty)")
+	fmt.Fprintln(w, "       --git-backend <name>\tRead repositories with: exec (git binary), native (default \"exec\")")
+	fmt.Fprintln(w, "       --no-cache\tRead every commit from git instead of the commit cache")
+	fmt.Fprintln(w, "\t")
+}
+
+// printOutputFlags prints the help lines of the output flags.
+func printOutputFlags(w io.Writer) {
+	fmt.Fprintln(w, "   -f, --format <string>\tOutput format: text, table, html, slack, slack-blocks (default \"text\")")
+	fmt.Fprintln(w, "   -s, --style <string>\tTable style, \"list\" shows all styles (default \"normal\")")
+	fmt.Fprintln(w, "   -c, --scope\tShow commit scope")
+	fmt.Fprintln(w, "   -i, --icon\tShow commit type icons")
+	fmt.Fprintln(w, "       --color <when>\tColorize output: auto, always, never (default \"auto\")")
+	fmt.Fprintln(w, "   -t, --task <string>\tAdd a task, \"type: message\" sets its type (repeatable)")
+	fmt.Fprintln(w, "\t")
+	fmt.Fprintln(w, "   -o, --output <file>\tWrite the report to a file instead of stdout")
+	fmt.Fprintln(w, "  -cp, --copy\tCopy output to system clipboard")
+	fmt.Fprintln(w, "       --post <name>\tPost the report to a configured webhook (repeatable)")
+	fmt.Fprintln(w, "       --email\tSend the report to the configured email recipients")
+	fmt.Fprintln(w, "       --dry-run\tPrint webhook payloads and emails instead of sending them")
+	fmt.Fprintln(w, "       --save\tSave current arguments as default configuration")
+	fmt.Fprintln(w, "\t")
+}
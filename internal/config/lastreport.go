@@ -0,0 +1,101 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// lastReportFile is the state file holding when the last report of each
+// profile was delivered, "" being the report without a profile.
+const lastReportFile = "last_report.json"
+
+// lastReportPath returns the location of the last report state file.
+func lastReportPath() string {
+	return filepath.Join(StateDir(), lastReportFile)
+}
+
+// readLastReports returns the last report times by profile. A missing
+// file has none.
+func readLastReports() (map[string]time.Time, error) {
+	times := make(map[string]time.Time)
+	data, err := os.ReadFile(lastReportPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return times, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &times); err != nil {
+		return nil, fmt.Errorf("%s: %w", lastReportPath(), err)
+	}
+	return times, nil
+}
+
+// LastReport returns when the last delivered report of a profile was
+// generated, or the zero time when there is none.
+func LastReport(profile string) (time.Time, error) {
+	times, err := readLastReports()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return times[profile], nil
+}
+
+// SetLastReport records that a report of a profile generated at t was
+// delivered. Older times never replace newer ones, so delivering a past
+// report again does not report its commits twice.
+func SetLastReport(profile string, t time.Time) error {
+	times, err := readLastReports()
+	if err != nil {
+		return err
+	}
+	if !t.After(times[profile]) {
+		return nil
+	}
+	times[profile] = t
+
+	data, err := json.MarshalIndent(times, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(StateDir(), 0o700); err != nil {
+		return err
+	}
+
+	// Written aside and renamed, so a concurrent run never reads half a file
+	tmp, err := os.CreateTemp(StateDir(), lastReportFile+".*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), lastReportPath())
+}
+
+// resolveSinceLast sets the start of the period of since_last. Without a
+// delivered report yet, the other period settings apply.
+func (c *AppConfig) resolveSinceLast() error {
+	if !c.SinceLast {
+		return nil
+	}
+	last, err := LastReport(c.Profile)
+	if err != nil {
+		return fmt.Errorf("cannot read the last report time: %w", err)
+	}
+	if last.IsZero() {
+		fmt.Fprintf(os.Stderr, "ℹ️ No report delivered yet, reporting since %s\n", c.GetPeriod())
+		return nil
+	}
+	c.Since = last
+	return nil
+}
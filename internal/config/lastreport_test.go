@@ -0,0 +1,77 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSetLastReport(t *testing.T) {
+	isolate(t)
+	base := time.Date(2026, time.January, 9, 9, 0, 0, 0, time.UTC)
+
+	// A missing file has no times
+	if last, err := LastReport(""); err != nil || !last.IsZero() {
+		t.Fatalf("LastReport = %v, %v; want the zero time", last, err)
+	}
+
+	steps := []struct {
+		profile string
+		at      time.Time
+		want    map[string]time.Time
+	}{
+		{"", base, map[string]time.Time{"": base, "work": {}}},
+		{"work", base.Add(time.Hour), map[string]time.Time{"": base, "work": base.Add(time.Hour)}},
+		{"", base.Add(-time.Hour), map[string]time.Time{"": base, "work": base.Add(time.Hour)}}, // Older, ignored
+		{"", base, map[string]time.Time{"": base, "work": base.Add(time.Hour)}},
+		{"", base.Add(24 * time.Hour), map[string]time.Time{"": base.Add(24 * time.Hour), "work": base.Add(time.Hour)}},
+	}
+	for i, step := range steps {
+		if err := SetLastReport(step.profile, step.at); err != nil {
+			t.Fatal(err)
+		}
+		for profile, want := range step.want {
+			if got, err := LastReport(profile); err != nil || !got.Equal(want) {
+				t.Errorf("step %d: LastReport(%q) = %v, %v; want %v", i, profile, got, err, want)
+			}
+		}
+	}
+
+	if err := os.WriteFile(lastReportPath(), []byte("{broken"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LastReport(""); err == nil || !strings.Contains(err.Error(), lastReportFile) {
+		t.Errorf("broken file: err = %v, want an error naming it", err)
+	}
+	if err := SetLastReport("", base.Add(48*time.Hour)); err == nil {
+		t.Error("a broken file was overwritten")
+	}
+}
+
+func TestResolveSinceLast(t *testing.T) {
+	isolate(t)
+	last := time.Date(2026, time.January, 9, 9, 0, 0, 0, time.UTC)
+	if err := SetLastReport("work", last); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		cfg  AppConfig
+		want time.Time
+	}{
+		{"off", AppConfig{Profile: "work", Days: 2}, time.Time{}},
+		{"profile", AppConfig{Profile: "work", Days: 2, SinceLast: true}, last},
+		{"no report yet", AppConfig{Days: 2, SinceLast: true}, time.Time{}},
+	}
+	for _, tt := range tests {
+		cfg := tt.cfg
+		if err := cfg.resolveSinceLast(); err != nil {
+			t.Fatal(err)
+		}
+		if !cfg.Since.Equal(tt.want) || cfg.Days != 2 {
+			t.Errorf("%s: since = %v, days = %d; want %v and 2", tt.name, cfg.Since, cfg.Days, tt.want)
+		}
+	}
+}
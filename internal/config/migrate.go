@@ -0,0 +1,120 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// ConfigVersion is the version of the config file format of this build.
+// Files without config_version are version 0.
+const ConfigVersion = 1
+
+// migration upgrades a config file to the next version.
+type migration struct {
+	version int    // Version the file has after the migration
+	summary string // What the migration changes
+	apply   func(f *File) error
+}
+
+// migrations upgrade config files one version at a time, in order. A change
+// to the meaning or the name of a setting comes with a new migration and a
+// bump of ConfigVersion.
+var migrations = []migration{
+	{
+		version: 1,
+		summary: "record config_version",
+		apply:   func(*File) error { return nil },
+	},
+}
+
+// Version returns the config_version of the file, 0 when it is not set.
+func (f *File) Version() (int, error) {
+	value, ok := f.Get("config_version")
+	if !ok || value == nil {
+		return 0, nil
+	}
+	n, isNumber := value.(json.Number)
+	version, err := n.Int64()
+	if !isNumber || err != nil || version < 0 {
+		return 0, fmt.Errorf("%s: config_version must be a non-negative integer", f.Path)
+	}
+	return int(version), nil
+}
+
+// upgrade applies the migrations a file needs, in memory. When they change
+// the content, the file records the current version and Save writes it with
+// a backup of the original. Otherwise Save only records the version. Files with an invalid or newer config_version are
+// left as they are, Validate reports them.
+func (f *File) upgrade() error {
+	version, err := f.Version()
+	if err != nil || version >= ConfigVersion || len(f.data) == 0 {
+		return nil
+	}
+
+	original := f.data
+	for _, m := range migrations {
+		if m.version <= version {
+			continue
+		}
+		if err := m.apply(f); err != nil {
+			return fmt.Errorf("cannot upgrade %s to config version %d (%s): %w", f.Path, m.version, m.summary, err)
+		}
+	}
+	if bytes.Equal(f.data, original) {
+		return nil // Nothing to write back
+	}
+
+	if err := f.setValue("config_version", json.Number(strconv.Itoa(ConfigVersion))); err != nil {
+		return err
+	}
+	f.original, f.from = original, version
+	return nil
+}
+
+// saveBackup copies the content the file had before its upgrade next to
+// it, once.
+func (f *File) saveBackup() error {
+	if f.original == nil {
+		return nil
+	}
+	backup := fmt.Sprintf("%s.v%d.bak", f.Path, f.from)
+	if err := os.WriteFile(backup, f.original, 0o600); err != nil {
+		return fmt.Errorf("cannot back up %s before upgrading it: %w", f.Path, err)
+	}
+	fmt.Fprintf(os.Stderr, "ℹ️ Upgraded %s to config version %d, the previous file is saved as %s\n", f.Path, ConfigVersion, backup)
+	f.original = nil
+	return nil
+}
+
+// stamp records the current version in a new file, so it is never migrated.
+// New JSON files also get "$schema" so editors complete and check them.
+func (f *File) stamp() error {
+	if !f.Has("config_version") {
+		if err := f.setValue("config_version", json.Number(strconv.Itoa(ConfigVersion))); err != nil {
+			return err
+		}
+	}
+	if f.Format != FormatJSON || f.Has("$schema") {
+		return nil
+	}
+
+	f.root.set("$schema", SchemaURL)
+	// Put the header first, then the settings in their order
+	keys := []string{"$schema", "config_version"}
+	for _, key := range f.root.keys {
+		if key != "$schema" && key != "config_version" {
+			keys = append(keys, key)
+		}
+	}
+	f.root.keys = keys
+
+	data, err := encodeJSON(f.root)
+	if err != nil {
+		return err
+	}
+	f.data = data
+	return nil
+}
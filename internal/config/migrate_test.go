@@ -0,0 +1,154 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// renameAuthor is a migration that changes the content, renaming "name" to
+// "author".
+var renameAuthor = []migration{{
+	version: 1,
+	summary: "rename name to author",
+	apply: func(f *File) error {
+		value, ok := f.Get("name")
+		if !ok {
+			return nil
+		}
+		if _, err := f.Unset("name"); err != nil {
+			return err
+		}
+		return f.setValue("author", value)
+	},
+}}
+
+// readFile returns the content of path, "" when it does not exist.
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestUpgrade(t *testing.T) {
+	tests := []struct {
+		name       string
+		migrations []migration
+		file       string // config.json by default
+		content    string
+		wantLoad   string // Error loading the file
+		wantSaved  string // Content after config set days 2, "" when it fails
+		wantBackup bool
+	}{
+		{
+			name:       "no change",
+			migrations: migrations,
+			content:    "{\n  \"author\": \"me\"\n}\n",
+			wantSaved:  "{\n  \"author\": \"me\",\n  \"days\": 2,\n  \"config_version\": 1\n}\n",
+		},
+		{
+			name:       "no change, TOML",
+			migrations: migrations,
+			file:       "config.toml",
+			content:    "author = \"me\" # Me\n\n[email]\nhost = \"smtp.example.com\"\n",
+			wantSaved:  "author = \"me\" # Me\ndays = 2\nconfig_version = 1\n\n[email]\nhost = \"smtp.example.com\"\n",
+		},
+		{
+			name:       "renamed key",
+			migrations: renameAuthor,
+			content:    "{\n  \"name\": \"me\"\n}\n",
+			wantSaved:  "{\n  \"author\": \"me\",\n  \"config_version\": 1,\n  \"days\": 2\n}\n",
+			wantBackup: true,
+		},
+		{
+			name:       "current version",
+			migrations: renameAuthor,
+			content:    "{\n  \"config_version\": 1,\n  \"name\": \"me\"\n}\n",
+			wantLoad:   "unknown key",
+			wantSaved:  "{\n  \"config_version\": 1,\n  \"name\": \"me\",\n  \"days\": 2\n}\n",
+		},
+		{
+			name:       "invalid",
+			migrations: renameAuthor,
+			content:    "{\n  \"name\": \"me\",\n  \"days\": \"two\"\n}\n",
+			wantLoad:   "days",
+			wantSaved:  "{\n  \"days\": 2,\n  \"author\": \"me\",\n  \"config_version\": 1\n}\n",
+			wantBackup: true,
+		},
+		{
+			name:       "newer version",
+			migrations: renameAuthor,
+			content:    "{\n  \"config_version\": 2\n}\n",
+			wantLoad:   "written by a newer gohome",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saved := migrations
+			migrations = tt.migrations
+			t.Cleanup(func() { migrations = saved })
+
+			home := isolate(t)
+			name := tt.file
+			if name == "" {
+				name = "config.json"
+			}
+			path := filepath.Join(home, "xdg", "gohome", name)
+			backup := path + ".v0.bak"
+			touch(t, path, tt.content)
+
+			// Loading, as every command does, never writes
+			file, err := loadConfigFromFile()
+			if tt.wantLoad == "" && err != nil {
+				t.Fatalf("load: %v", err)
+			}
+			if tt.wantLoad != "" && (err == nil || !strings.Contains(err.Error(), tt.wantLoad)) {
+				t.Errorf("load: err = %v, want %q", err, tt.wantLoad)
+			}
+			if got := readFile(t, path); got != tt.content {
+				t.Errorf("loading rewrote the file:\n%s", got)
+			}
+			if readFile(t, backup) != "" {
+				t.Error("loading wrote a backup")
+			}
+			if err == nil {
+				if value, _ := file.Get("author"); value != "me" {
+					t.Errorf("author = %v, want me", value)
+				}
+			}
+
+			// Saving writes the upgrade
+			file, err = OpenFile()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := file.Set("days", "2"); err != nil {
+				t.Fatal(err)
+			}
+			err = file.Save()
+			if tt.wantSaved == "" {
+				if err == nil {
+					t.Error("saved a file of a newer version")
+				}
+				tt.wantSaved = tt.content
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if got := readFile(t, path); got != tt.wantSaved {
+				t.Errorf("saved\n%s\nwant\n%s", got, tt.wantSaved)
+			}
+			got := readFile(t, backup)
+			if tt.wantBackup && got != tt.content {
+				t.Errorf("backup\n%s\nwant the original\n%s", got, tt.content)
+			}
+			if !tt.wantBackup && got != "" {
+				t.Errorf("unexpected backup\n%s", got)
+			}
+		})
+	}
+}
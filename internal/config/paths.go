@@ -0,0 +1,148 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// configExts are the supported config file extensions, in order of precedence.
+var configExts = []string{".json", ".yaml", ".yml", ".toml"}
+
+// configFlag holds the --config flag value.
+var configFlag string
+
+// explicitConfigPath returns the config file chosen with --config or
+// $GOHOME_CONFIG, or "" when the file is discovered.
+func explicitConfigPath() string {
+	path := configFlag
+	if path == "" {
+		path = os.Getenv("GOHOME_CONFIG")
+	}
+	if path == "" {
+		return ""
+	}
+
+	return ExpandHome(path)
+}
+
+// ExpandHome replaces a leading ~ with the home directory, for paths that
+// did not go through a shell.
+func ExpandHome(path string) string {
+	if rest, ok := strings.CutPrefix(path, "~"); ok && (rest == "" || os.IsPathSeparator(rest[0])) {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = home + rest
+		}
+	}
+	return path
+}
+
+// getConfigFilePath returns the config file path.
+func getConfigFilePath() string {
+	path, _ := findConfigFile()
+	return path
+}
+
+// findConfigFile returns the config file to use and the other config files
+// that exist and are ignored. The file is, in order:
+//  1. the --config flag or $GOHOME_CONFIG
+//  2. $XDG_CONFIG_HOME/gohome/config.{json,yaml,yml,toml}
+//  3. ~/.gohome.{json,yaml,yml,toml} (legacy location)
+//
+// When none exists, new settings go to $XDG_CONFIG_HOME/gohome/config.json.
+func findConfigFile() (string, []string) {
+	if path := explicitConfigPath(); path != "" {
+		return path, nil
+	}
+
+	var candidates []string
+	xdgDir := filepath.Join(configHome(), "gohome")
+	for _, ext := range configExts {
+		candidates = append(candidates, filepath.Join(xdgDir, "config"+ext))
+	}
+	for _, ext := range configExts {
+		candidates = append(candidates, filepath.Join(homeDir(), ".gohome"+ext))
+	}
+
+	var found []string
+	for _, path := range candidates {
+		if _, err := os.Stat(path); err == nil {
+			found = append(found, path)
+		}
+	}
+
+	if len(found) == 0 {
+		return candidates[0], nil
+	}
+	return found[0], found[1:]
+}
+
+// GetConfigPath exports the config file path for external use.
+func GetConfigPath() string {
+	return getConfigFilePath()
+}
+
+// CacheDir returns the directory for cached data that can be rebuilt,
+// $XDG_CACHE_HOME/gohome by default. It is not created.
+func CacheDir() string {
+	return filepath.Join(baseDir("XDG_CACHE_HOME", ".cache", os.UserCacheDir), "gohome")
+}
+
+// StateDir returns the directory for data that should persist between runs
+// but is not configuration, $XDG_STATE_HOME/gohome by default. It is not
+// created.
+func StateDir() string {
+	return filepath.Join(baseDir("XDG_STATE_HOME", filepath.Join(".local", "state"), os.UserCacheDir), "gohome")
+}
+
+// configHome returns $XDG_CONFIG_HOME or its default.
+func configHome() string {
+	return baseDir("XDG_CONFIG_HOME", ".config", os.UserConfigDir)
+}
+
+// baseDir returns an XDG base directory: the environment variable when it
+// holds an absolute path, the Windows known folder on Windows, and
+// ~/<fallback> elsewhere (macOS included, as most command-line tools do).
+func baseDir(env, fallback string, windowsDir func() (string, error)) string {
+	if dir := os.Getenv(env); filepath.IsAbs(dir) {
+		return dir
+	}
+	if runtime.GOOS == "windows" {
+		if dir, err := windowsDir(); err == nil {
+			return dir
+		}
+	}
+	return filepath.Join(homeDir(), fallback)
+}
+
+// homeDir returns the user's home directory.
+func homeDir() string {
+	dir, err := os.UserHomeDir()
+	if err != nil {
+		return "." // Fallback to current directory if error
+	}
+	return dir
+}
+
+// validateConfigPath ensures the config file path is usable: it must have
+// a supported extension and must not be a directory.
+func validateConfigPath(filePath string) error {
+	// Clean the path to remove any '..' or other unsafe elements
+	absPath, err := filepath.Abs(filepath.Clean(filePath))
+	if err != nil {
+		return err
+	}
+
+	ext := strings.ToLower(filepath.Ext(absPath))
+	if !slices.Contains(configExts, ext) {
+		return fmt.Errorf("unsupported config file %s (use a .json, .yaml, .yml or .toml file)", filepath.Base(absPath))
+	}
+	if info, err := os.Stat(absPath); err == nil && info.IsDir() {
+		return fmt.Errorf("%s is a directory", absPath)
+	}
+
+	return nil
+}
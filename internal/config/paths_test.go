@@ -0,0 +1,125 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// isolate points the home and XDG directories to a temporary directory,
+// without --config nor GOHOME_ variables, and returns the home directory.
+func isolate(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "xdg"))
+	t.Setenv("XDG_STATE_HOME", filepath.Join(home, "state"))
+	t.Setenv("GOHOME_CONFIG", "")
+	t.Setenv("GOHOME_PROFILE", "")
+
+	old := configFlag
+	configFlag = ""
+	t.Cleanup(func() { configFlag = old })
+	return home
+}
+
+// touch creates a file and its directories.
+func touch(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFindConfigFile(t *testing.T) {
+	tests := []struct {
+		name        string
+		files       []string // Relative to the home directory
+		xdg         string   // XDG_CONFIG_HOME, relative to the home directory unless absolute
+		env, flag   string
+		want        string
+		wantIgnored []string
+	}{
+		{name: "none", want: "xdg/gohome/config.json"},
+		{name: "legacy", files: []string{".gohome.json"}, want: ".gohome.json"},
+		{name: "legacy yaml", files: []string{".gohome.yaml"}, want: ".gohome.yaml"},
+		{
+			name:        "xdg before legacy",
+			files:       []string{".gohome.json", "xdg/gohome/config.toml"},
+			want:        "xdg/gohome/config.toml",
+			wantIgnored: []string{".gohome.json"},
+		},
+		{
+			name:        "json before yaml and toml",
+			files:       []string{"xdg/gohome/config.toml", "xdg/gohome/config.yml", "xdg/gohome/config.json"},
+			want:        "xdg/gohome/config.json",
+			wantIgnored: []string{"xdg/gohome/config.yml", "xdg/gohome/config.toml"},
+		},
+		{name: "relative XDG_CONFIG_HOME is ignored", xdg: "relative", files: []string{".config/gohome/config.yaml"}, want: ".config/gohome/config.yaml"},
+		{name: "GOHOME_CONFIG", files: []string{"xdg/gohome/config.json"}, env: "~/work.toml", want: "work.toml"},
+		{name: "--config wins", env: "~/work.toml", flag: "~/other.yaml", want: "other.yaml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := isolate(t)
+			if tt.xdg != "" {
+				t.Setenv("XDG_CONFIG_HOME", tt.xdg)
+			}
+			t.Setenv("GOHOME_CONFIG", tt.env)
+			configFlag = tt.flag
+			for _, f := range tt.files {
+				touch(t, filepath.Join(home, f), "{}")
+			}
+
+			path, ignored := findConfigFile()
+			if want := filepath.Join(home, tt.want); path != want {
+				t.Errorf("path = %s, want %s", path, want)
+			}
+			var wantIgnored []string
+			for _, f := range tt.wantIgnored {
+				wantIgnored = append(wantIgnored, filepath.Join(home, f))
+			}
+			if (len(ignored) > 0 || len(wantIgnored) > 0) && !reflect.DeepEqual(ignored, wantIgnored) {
+				t.Errorf("ignored = %v, want %v", ignored, wantIgnored)
+			}
+		})
+	}
+}
+
+func TestValidateConfigPath(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "folder.json"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		wantErr bool
+	}{
+		{"config.json", false},
+		{"config.YML", false},
+		{"config.ini", true},
+		{"folder.json", true},
+	}
+	for _, tt := range tests {
+		if err := validateConfigPath(filepath.Join(dir, tt.name)); (err != nil) != tt.wantErr {
+			t.Errorf("validateConfigPath(%s) = %v, want error %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestDataDirs(t *testing.T) {
+	home := isolate(t)
+	t.Setenv("XDG_CACHE_HOME", "")
+	if want := filepath.Join(home, ".cache", "gohome"); CacheDir() != want {
+		t.Errorf("CacheDir = %s, want %s", CacheDir(), want)
+	}
+	if want := filepath.Join(home, "state", "gohome"); StateDir() != want {
+		t.Errorf("StateDir = %s, want %s", StateDir(), want)
+	}
+}
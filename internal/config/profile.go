@@ -0,0 +1,123 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Profile holds settings that --profile overlays on the base settings.
+// Its keys are the config keys, except the base-only ones.
+type Profile map[string]any
+
+var profileType = reflect.TypeOf(Profile(nil))
+
+// baseOnlyKeys can only be set outside profiles.
+var baseOnlyKeys = []string{"$schema", "config_version", "profiles", "default_profile", "strict"}
+
+// profileName returns the profile to use: --profile, $GOHOME_PROFILE or
+// the default_profile setting, in this order. It is empty when none is set.
+// chosen reports whether the profile was asked for by --profile or
+// $GOHOME_PROFILE rather than picked by default.
+func profileName(flagValue string, file, env *File) (name string, chosen bool) {
+	if flagValue != "" {
+		return flagValue, true
+	}
+	if name := os.Getenv("GOHOME_PROFILE"); name != "" {
+		return name, true
+	}
+	for _, settings := range []*File{env, file} {
+		if settings == nil {
+			continue
+		}
+		if name, ok := settings.Get("default_profile"); ok {
+			if s, isString := name.(string); isString && s != "" {
+				return s, false
+			}
+		}
+	}
+	return "", false
+}
+
+// loadProfile returns the settings of a profile of the file.
+func loadProfile(file *File, name string) (*File, error) {
+	var value any
+	ok := false
+	if file != nil {
+		value, ok = file.Get("profiles." + name)
+	}
+	if !ok || strings.Contains(name, ".") {
+		return nil, fmt.Errorf("unknown profile %q%s", name, profileHint(file))
+	}
+
+	obj, isObject := value.(*object)
+	if !isObject {
+		return nil, fmt.Errorf("profile %q is not an object", name)
+	}
+	return &File{root: obj, pos: make(map[string]position)}, nil
+}
+
+// profileHint lists the profiles of the file for error messages.
+func profileHint(file *File) string {
+	var names []string
+	if file != nil {
+		if value, ok := file.Get("profiles"); ok {
+			if obj, isObject := value.(*object); isObject {
+				names = append(names, obj.keys...)
+			}
+		}
+	}
+	if len(names) == 0 {
+		return " (no profiles in the config file)"
+	}
+	sort.Strings(names)
+	return fmt.Sprintf(" (available: %s)", strings.Join(names, ", "))
+}
+
+// isBaseOnly reports whether a key inside a profile, e.g. "strict", can
+// only be set outside profiles.
+func isBaseOnly(key string) bool {
+	return slices.Contains(baseOnlyKeys, key)
+}
+
+// saveProfile writes the settings given as flags into the profile, so the
+// profile only holds what differs from the base settings.
+func (c *AppConfig) saveProfile(file *File) error {
+	data, err := marshal(c)
+	if err != nil {
+		return err
+	}
+	settings, err := ParseSettings(data)
+	if err != nil {
+		return err
+	}
+
+	var keys []string
+	for key, origin := range c.origins {
+		if origin == OriginFlag {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	prefix := "profiles." + c.Profile + "."
+	// A new period replaces the period of the profile
+	if slices.ContainsFunc(keys, func(key string) bool { return timeKeys[key] }) {
+		for k := range timeKeys {
+			if _, err := file.Unset(prefix + k); err != nil {
+				return err
+			}
+		}
+	}
+	for _, key := range keys {
+		value, _ := settings.Get(key)
+		if err := file.setValue(prefix+key, value); err != nil {
+			return fmt.Errorf("cannot save %s: %w", prefix+key, err)
+		}
+	}
+
+	return file.Save()
+}
@@ -0,0 +1,207 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// profileConfig is a config file with two profiles.
+const profileConfig = `{
+  "config_version": 1,
+  "author": "base",
+  "format": "text",
+  "days": 2,
+  "profiles": {
+    "work": {"format": "table", "weeks": 1, "email": {"host": "smtp.work.example.com"}},
+    "oss": {"author": "oss"}
+  }
+}
+`
+
+var reportCmd = &Command{Name: "report", Groups: PeriodFlags | OutputFlags}
+
+func TestProfileName(t *testing.T) {
+	file, err := parseFile("config.json", FormatJSON, []byte(`{"default_profile": "file"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := ParseSettings([]byte(`{"default_profile": "env"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	none, err := ParseSettings([]byte(`{}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		flag, envProfile string
+		env, file        *File
+		want             string
+		wantChosen       bool
+	}{
+		{"flag", "variable", env, file, "flag", true},
+		{"", "variable", env, file, "variable", true},
+		{"", "", env, file, "env", false},
+		{"", "", none, file, "file", false},
+		{"", "", none, nil, "", false},
+	}
+	for _, tt := range tests {
+		t.Setenv("GOHOME_PROFILE", tt.envProfile)
+		got, chosen := profileName(tt.flag, tt.file, tt.env)
+		if got != tt.want || chosen != tt.wantChosen {
+			t.Errorf("profileName(%q, GOHOME_PROFILE=%q) = %q, %v; want %q, %v", tt.flag, tt.envProfile, got, chosen, tt.want, tt.wantChosen)
+		}
+	}
+}
+
+func TestProfileOverlay(t *testing.T) {
+	home := isolate(t)
+	touch(t, filepath.Join(home, "xdg", "gohome", "config.json"), profileConfig)
+
+	cfg, _, err := Load(reportCmd, []string{"--profile", "work"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.OutputFmt != "table" || cfg.Weeks != 1 || cfg.Days != 0 || cfg.Author != "base" || cfg.Email == nil || cfg.Email.Host != "smtp.work.example.com" {
+		t.Errorf("unexpected config %+v", cfg)
+	}
+	for key, want := range map[string]Origin{"format": OriginProfile, "weeks": OriginProfile, "author": OriginFile, "email.host": OriginProfile} {
+		if got := cfg.Origin(key); got != want {
+			t.Errorf("Origin(%s) = %s, want %s", key, got, want)
+		}
+	}
+
+	// The environment goes over the profile, flags over both
+	t.Setenv("GOHOME_FORMAT", "html")
+	cfg, _, err = Load(reportCmd, []string{"--profile", "work", "-a", "flag"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.OutputFmt != "html" || cfg.Author != "flag" {
+		t.Errorf("format = %q, author = %q, want html and flag", cfg.OutputFmt, cfg.Author)
+	}
+
+	_, _, err = Load(reportCmd, []string{"--profile", "home"})
+	if err == nil || !strings.Contains(err.Error(), `unknown profile "home" (available: oss, work)`) {
+		t.Errorf("unknown profile: err = %v", err)
+	}
+}
+
+func TestSaveProfile(t *testing.T) {
+	home := isolate(t)
+	path := filepath.Join(home, "xdg", "gohome", "config.json")
+	touch(t, path, profileConfig)
+	t.Setenv("GOHOME_SHOW_ICON", "true") // Not saved, it is not a flag
+
+	cfg, _, err := Load(reportCmd, []string{"--profile", "work", "-f", "html", "-d", "3", "--save"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.SaveToFile(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  "config_version": 1,
+  "author": "base",
+  "format": "text",
+  "days": 2,
+  "profiles": {
+    "work": {
+      "format": "html",
+      "email": {
+        "host": "smtp.work.example.com"
+      },
+      "days": 3
+    },
+    "oss": {
+      "author": "oss"
+    }
+  }
+}
+`
+	if string(data) != want {
+		t.Errorf("saved file\n%s\nwant\n%s", data, want)
+	}
+
+	// A new profile is created by --save
+	cfg, _, err = Load(reportCmd, []string{"--profile", "home", "-a", "me", "--save"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.SaveToFile(); err != nil {
+		t.Fatal(err)
+	}
+	file, err := OpenFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value, _ := file.Get("profiles.home.author"); value != "me" {
+		t.Errorf("profiles.home.author = %v, want me", value)
+	}
+	if file.Has("profiles.home.show_icon") || file.Has("tasks") {
+		t.Errorf("settings other than the flags were saved:\n%s", file.data)
+	}
+}
+
+func TestSaveDefaultProfile(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		envProfile string
+		want       map[string]any // Values of the saved file
+	}{
+		{"default profile", []string{"-a", "me", "--save"}, "", map[string]any{
+			"author": "me", "format": "text", "profiles.work.author": nil, "profiles.work.format": "table",
+		}},
+		{"--profile", []string{"--profile", "work", "-a", "me", "--save"}, "", map[string]any{
+			"author": "base", "profiles.work.author": "me", "profiles.work.format": "table",
+		}},
+		{"GOHOME_PROFILE", []string{"-a", "me", "--save"}, "work", map[string]any{
+			"author": "base", "profiles.work.author": "me", "profiles.work.format": "table",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := isolate(t)
+			t.Setenv("GOHOME_PROFILE", tt.envProfile)
+			touch(t, filepath.Join(home, "xdg", "gohome", "config.json"), `{
+  "config_version": 1,
+  "author": "base",
+  "format": "text",
+  "default_profile": "work",
+  "profiles": {"work": {"format": "table"}}
+}
+`)
+
+			cfg, _, err := Load(reportCmd, tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.Profile != "work" || cfg.OutputFmt != "table" {
+				t.Fatalf("profile = %q, format = %q; want the work profile", cfg.Profile, cfg.OutputFmt)
+			}
+			if err := cfg.SaveToFile(); err != nil {
+				t.Fatal(err)
+			}
+
+			file, err := OpenFile()
+			if err != nil {
+				t.Fatal(err)
+			}
+			for key, want := range tt.want {
+				got, ok := file.Get(key)
+				if want == nil && ok || want != nil && got != want {
+					t.Errorf("%s = %v, want %v", key, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// repoConfigName is the base name of the per-repository config files. It
+// differs from the user config so a repository under the home directory
+// does not pick up ~/.gohome.json.
+const repoConfigName = ".gohome-repo"
+
+// RepoConfig holds the overrides of a repository. They are read from
+// .gohome-repo.{json,yaml,yml,toml} files in the repository and its parent
+// directories, the nearest file winning key by key.
+type RepoConfig struct {
+	// Display name, the directory name by default
+	Name string `json:"name,omitempty"`
+	// Leave the repository out of reports
+	Exclude bool `json:"exclude,omitempty"`
+
+	// Commit types and scopes renamed after parsing, e.g. {"feature": "feat"}
+	TypeAliases  map[string]string `json:"type_aliases,omitempty"`
+	ScopeAliases map[string]string `json:"scope_aliases,omitempty"`
+
+	// Regular expressions tried before Conventional Commits, with named
+	// groups type, scope, message and breaking
+	ParsePatterns []string `json:"parse_patterns,omitempty"`
+	// Commits whose subject matches one of these regular expressions are skipped
+	Ignore []string `json:"ignore,omitempty"`
+
+	// Regular expression finding ticket IDs in subjects, e.g. "[A-Z]+-[0-9]+"
+	TicketPattern string `json:"ticket_pattern,omitempty"`
+	// Link to a ticket, {id} is replaced by the ticket ID
+	TicketURL string `json:"ticket_url,omitempty"`
+}
+
+// repoChecks validates the regular expressions of a repository config.
+func repoChecks() []Check {
+	return []Check{
+		{Pattern: "parse_patterns.*", Validate: compiles},
+		{Pattern: "ignore.*", Validate: compiles},
+		{Pattern: "ticket_pattern", Validate: compiles},
+		{Pattern: "parse_patterns.*", Validate: func(value any) error {
+			pattern, _ := value.(string)
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil // Reported above
+			}
+			for _, name := range re.SubexpNames() {
+				if name == "message" {
+					return nil
+				}
+			}
+			return errors.New(`the pattern needs a (?P<message>...) group`)
+		}},
+		{Pattern: "ticket_url", Validate: func(value any) error {
+			if url, _ := value.(string); !strings.Contains(url, "{id}") {
+				return errors.New("the URL needs an {id} placeholder")
+			}
+			return nil
+		}},
+	}
+}
+
+// compiles checks that a value is a valid regular expression.
+func compiles(value any) error {
+	pattern, _ := value.(string)
+	if _, err := regexp.Compile(pattern); err != nil {
+		return fmt.Errorf("invalid regular expression: %w", err)
+	}
+	return nil
+}
+
+// LoadRepoConfig returns the overrides of the repository at repoPath. Files
+// are looked up from the repository up to the filesystem root. Invalid files
+// are reported as a *ValidationError.
+func LoadRepoConfig(repoPath string) (*RepoConfig, error) {
+	dir, err := filepath.Abs(repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Collect the files from the nearest to the farthest
+	var files []*File
+	for {
+		file, err := openRepoConfig(dir)
+		if err != nil {
+			return nil, err
+		}
+		if file != nil {
+			files = append(files, file)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	merged := newObject()
+	for i := len(files) - 1; i >= 0; i-- {
+		if err := overlay(merged, files[i]); err != nil {
+			return nil, fmt.Errorf("%s: %w", files[i].Path, err)
+		}
+	}
+
+	var cfg RepoConfig
+	if err := Decode(merged, &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// openRepoConfig reads and validates the repository config of a directory.
+// It returns nil when there is none.
+func openRepoConfig(dir string) (*File, error) {
+	for _, ext := range configExts {
+		path := filepath.Join(dir, repoConfigName+ext)
+
+		// #nosec G304 -- the name is fixed, only the directory varies
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		file, err := parseFile(path, formatOf(path), data)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read repository config: %w", err)
+		}
+		if problems := file.validateRepo(); len(problems) > 0 {
+			return nil, &ValidationError{Path: path, Problems: problems, Hint: "Fix the file or run with --ignore-config"}
+		}
+		return file, nil
+	}
+	return nil, nil
+}
+
+// validateRepo reports unknown keys, values of the wrong type and invalid
+// regular expressions of a repository config.
+func (f *File) validateRepo() []Problem {
+	return f.validate(reflect.TypeOf(RepoConfig{}), repoChecks())
+}
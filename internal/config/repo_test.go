@@ -0,0 +1,112 @@
+package config
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadRepoConfigWalk(t *testing.T) {
+	root := t.TempDir()
+	repo := filepath.Join(root, "work", "acme", "api")
+	touch(t, filepath.Join(root, "work", ".gohome-repo.toml"), `
+ignore = ["^wip"]
+ticket_pattern = "[A-Z]+-[0-9]+"
+ticket_url = "https://jira.example.com/browse/{id}"
+
+[type_aliases]
+feature = "feat"
+bugfix = "fix"
+`)
+	touch(t, filepath.Join(root, "work", "acme", ".gohome-repo.yaml"), "exclude: true\ntype_aliases:\n  bugfix: fixes\n")
+	touch(t, filepath.Join(repo, ".gohome-repo.json"), `{"name": "acme/api", "exclude": false, "ignore": ["^tmp"]}`)
+
+	tests := []struct {
+		dir  string
+		want RepoConfig
+	}{
+		{repo, RepoConfig{
+			Name:          "acme/api",
+			TypeAliases:   map[string]string{"feature": "feat", "bugfix": "fixes"},
+			Ignore:        []string{"^tmp"}, // Lists are replaced, not merged
+			TicketPattern: "[A-Z]+-[0-9]+",
+			TicketURL:     "https://jira.example.com/browse/{id}",
+		}},
+		{filepath.Join(root, "work", "acme", "web"), RepoConfig{
+			Exclude:       true,
+			TypeAliases:   map[string]string{"feature": "feat", "bugfix": "fixes"},
+			Ignore:        []string{"^wip"},
+			TicketPattern: "[A-Z]+-[0-9]+",
+			TicketURL:     "https://jira.example.com/browse/{id}",
+		}},
+		{filepath.Join(root, "home"), RepoConfig{}},
+	}
+	for _, tt := range tests {
+		got, err := LoadRepoConfig(tt.dir)
+		if err != nil {
+			t.Errorf("%s: %v", tt.dir, err)
+			continue
+		}
+		if !reflect.DeepEqual(*got, tt.want) {
+			t.Errorf("%s:\n got %+v\nwant %+v", tt.dir, *got, tt.want)
+		}
+	}
+}
+
+func TestLoadRepoConfigFirstExtension(t *testing.T) {
+	repo := t.TempDir()
+	touch(t, filepath.Join(repo, ".gohome-repo.json"), `{"name": "json"}`)
+	touch(t, filepath.Join(repo, ".gohome-repo.yaml"), "name: yaml\n")
+
+	got, err := LoadRepoConfig(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "json" {
+		t.Errorf("name = %q, want the .json file to win", got.Name)
+	}
+}
+
+func TestLoadRepoConfigErrors(t *testing.T) {
+	tests := []struct {
+		name, file, content string
+		want                []string // Problems, nil for a parse error
+	}{
+		{"unknown key", ".gohome-repo.json", `{"nmae": "api"}`, []string{`1:2 nmae: unknown key (did you mean "name"?)`}},
+		{"bad pattern", ".gohome-repo.yaml", "ignore:\n  - \"(\"\n", []string{"2:5 ignore.0: invalid regular expression: error parsing regexp: missing closing ): `(`"}},
+		{"no message group", ".gohome-repo.toml", "parse_patterns = [\"^(?P<type>\\\\w+)\"]\n", []string{"1:1 parse_patterns.0: the pattern needs a (?P<message>...) group"}},
+		{"no id placeholder", ".gohome-repo.json", `{"ticket_url": "https://jira.example.com"}`, []string{"1:2 ticket_url: the URL needs an {id} placeholder"}},
+		{"syntax", ".gohome-repo.json", `{"name": }`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent := t.TempDir()
+			repo := filepath.Join(parent, "repo")
+			touch(t, filepath.Join(parent, tt.file), tt.content)
+			touch(t, filepath.Join(repo, ".gohome-repo.json"), `{"name": "repo"}`)
+
+			_, err := LoadRepoConfig(repo)
+			if err == nil {
+				t.Fatal("expected an error from the parent file")
+			}
+			var verr *ValidationError
+			if tt.want == nil {
+				if errors.As(err, &verr) || !strings.Contains(err.Error(), "cannot read repository config") {
+					t.Errorf("err = %v, want a parse error", err)
+				}
+				return
+			}
+			if !errors.As(err, &verr) {
+				t.Fatalf("err = %v, want a *ValidationError", err)
+			}
+			if verr.Path != filepath.Join(parent, tt.file) {
+				t.Errorf("path = %s, want the parent file", verr.Path)
+			}
+			if got := problems(verr.Problems); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("problems = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
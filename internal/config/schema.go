@@ -0,0 +1,181 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/anIcedAntFA/gohome/internal/entity"
+	"github.com/anIcedAntFA/gohome/internal/git"
+	"github.com/anIcedAntFA/gohome/internal/mail"
+	"github.com/anIcedAntFA/gohome/internal/renderer"
+	"github.com/anIcedAntFA/gohome/internal/sys"
+	"github.com/anIcedAntFA/gohome/internal/tasksource"
+	"github.com/anIcedAntFA/gohome/internal/webhook"
+)
+
+// SchemaURL is where the JSON Schema of the config file is published. Set it
+// as "$schema" in a JSON config to get completion and checks in editors.
+const SchemaURL = "https://raw.githubusercontent.com/anIcedAntFA/gohome/main/docs/gohome.schema.json"
+
+// schemaDocs describes the settings in the schema, by key.
+var schemaDocs = map[string]string{
+	"$schema":        "JSON Schema of this file, lets editors complete and check settings",
+	"config_version": "Version of the file format, set by gohome and used to upgrade old files",
+
+	"hours":  "Report the commits of the last N hours",
+	"days":   "Report the commits of the last N days",
+	"weeks":  "Report the commits of the last N weeks",
+	"months": "Report the commits of the last N months",
+	"years":  "Report the commits of the last N years",
+	"today":  "Report the commits since midnight",
+
+	"since_last": "Report the commits since the last copied, posted or emailed report of the profile",
+
+	"path":     "Directory scanned for Git repositories",
+	"author":   "Only report the commits of this author (name or email)",
+	"format":   "Report format",
+	"preset":   "Table style, built-in or defined in table_styles",
+	"color":    "When to use colors",
+	"theme":    "Commit type colors overriding the default theme, e.g. {\"feat\": \"bold green\"}",
+	"tasks":    "Tasks added to every report",
+	"profiles": "Named sets of settings selected with --profile",
+	"strict":   "Unknown keys are errors unless strict is false",
+
+	"git_backend": "How repositories are read: exec runs the git binary, native reads the files without it",
+
+	"table_styles":                "Custom table styles selectable with --style, keyed by style name",
+	"table_styles.*.border":       "Border set of the table",
+	"table_styles.*.header_color": "Color of the header row, e.g. \"bold cyan\"",
+
+	"show_icon":         "Show the commit type icons",
+	"show_scope":        "Show the commit scopes",
+	"copy_to_clipboard": "Copy the report to the clipboard",
+	"history_limit":     "Reports kept in the history per profile and author (default 100, negative keeps them all)",
+	"default_profile":   "Profile used when --profile is not given",
+
+	"task_sources":             "Files the done tasks are imported from",
+	"task_sources.*.type":      "Kind of file",
+	"task_sources.*.path":      "File or directory path, ~ is expanded and relative paths start at the config file",
+	"task_sources.*.task_type": "Type of the imported tasks (default todo, meeting for ics)",
+	"task_sources.*.icon":      "Icon of the imported tasks (default ✅, 📅 for ics)",
+	"task_sources.*.match":     "Only tasks matching this regular expression are imported (case-insensitive)",
+	"task_sources.*.ignore":    "Tasks matching one of these regular expressions are skipped (case-insensitive)",
+
+	"webhooks":           "Named webhook destinations used by --post",
+	"webhooks.*.type":    "Kind of destination, sets the payload shape",
+	"webhooks.*.url":     "Webhook URL",
+	"webhooks.*.format":  "Report format, defaults per type",
+	"webhooks.*.headers": "Extra HTTP headers",
+	"webhooks.*.timeout": "Seconds per request (default 10)",
+	"webhooks.*.retries": "Retries after a failure (default 2, negative disables)",
+
+	"email":          "SMTP settings used by --email",
+	"email.host":     "SMTP server",
+	"email.port":     "Defaults to 587 for starttls, 465 for tls, 25 for none",
+	"email.security": "Connection security",
+	"email.username": "SMTP user",
+	"email.password": "SMTP password, GOHOME_EMAIL__PASSWORD keeps it out of the file",
+	"email.from":     "Sender address",
+	"email.to":       "Recipient addresses",
+	"email.subject":  "Template with {{.Author}}, {{.Period}} and {{.Date}}",
+	"email.timeout":  "Seconds (default 30)",
+
+	"tasks.*.type":    "Task type, e.g. \"meeting\"",
+	"tasks.*.message": "Task description",
+	"tasks.*.icon":    "Icon shown before the task",
+	"tasks.*.enabled": "Only enabled tasks are reported",
+
+	"tasks.*.schedule":             "Days the task happens on, every day when not set; all the rules set must match",
+	"tasks.*.schedule.weekdays":    "Days of the week",
+	"tasks.*.schedule.every_weeks": "Every N weeks, counted from the week of start",
+	"tasks.*.schedule.days":        "Days of the month, negative days count from the end (-1 is the last day)",
+	"tasks.*.schedule.start":       "First day of the task, YYYY-MM-DD",
+	"tasks.*.schedule.end":         "Last day of the task, YYYY-MM-DD",
+}
+
+// schemaEnums lists the values allowed for string settings, by key.
+func schemaEnums() map[string][]string {
+	return map[string][]string{
+		"format":                      renderer.Formats(),
+		"color":                       {sys.ColorAuto, sys.ColorAlways, sys.ColorNever},
+		"git_backend":                 git.Backends(),
+		"email.security":              {mail.SecurityStartTLS, mail.SecurityTLS, mail.SecurityNone},
+		"webhooks.*.type":             {webhook.TypeSlack, webhook.TypeDiscord, webhook.TypeTeams, webhook.TypeGeneric},
+		"webhooks.*.format":           renderer.Formats(),
+		"table_styles.*.border":       renderer.BorderSets(),
+		"tasks.*.schedule.weekdays.*": entity.WeekdayNames(),
+		"task_sources.*.type":         tasksource.Types(),
+	}
+}
+
+// Schema returns the JSON Schema of the config file. It is generated from
+// AppConfig, so it lists every setting this build knows.
+func Schema() ([]byte, error) {
+	root := schemaOf(reflect.TypeOf(AppConfig{}), "", schemaEnums())
+	root["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	root["$id"] = SchemaURL
+	root["title"] = "gohome configuration"
+
+	// A profile takes the settings of the base config, except the base-only ones
+	profile := schemaOf(reflect.TypeOf(AppConfig{}), "", schemaEnums())
+	props, _ := profile["properties"].(map[string]any)
+	for _, key := range baseOnlyKeys {
+		delete(props, key)
+	}
+	root["$defs"] = map[string]any{"profile": profile}
+
+	data, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// schemaOf returns the schema of a setting of type t at key.
+func schemaOf(t reflect.Type, key string, enums map[string][]string) map[string]any {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	s := map[string]any{}
+	if doc, ok := schemaDocs[key]; ok {
+		s["description"] = doc
+	}
+
+	switch {
+	case t == profileType:
+		s["$ref"] = "#/$defs/profile"
+	case t.Kind() == reflect.Struct:
+		props := map[string]any{}
+		for i := 0; i < t.NumField(); i++ {
+			name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+			if name == "" || name == "-" || !t.Field(i).IsExported() {
+				continue
+			}
+			props[name] = schemaOf(t.Field(i).Type, joinKey(key, name), enums)
+		}
+		s["type"] = "object"
+		s["properties"] = props
+		s["additionalProperties"] = false
+	case t.Kind() == reflect.Map:
+		s["type"] = "object"
+		s["additionalProperties"] = schemaOf(t.Elem(), joinKey(key, "*"), enums)
+	case t.Kind() == reflect.Slice:
+		s["type"] = "array"
+		s["items"] = schemaOf(t.Elem(), joinKey(key, "*"), enums)
+	case t.Kind() == reflect.Bool:
+		s["type"] = "boolean"
+	case t.Kind() == reflect.Int:
+		s["type"] = "integer"
+		if timeKeys[key] || key == "config_version" {
+			s["minimum"] = 0
+		}
+	case t.Kind() == reflect.String:
+		s["type"] = "string"
+		if values, ok := enums[key]; ok {
+			s["enum"] = values
+		}
+	}
+	return s
+}
@@ -0,0 +1,160 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/anIcedAntFA/gohome/internal/entity"
+	"github.com/anIcedAntFA/gohome/internal/git"
+	"github.com/anIcedAntFA/gohome/internal/mail"
+	"github.com/anIcedAntFA/gohome/internal/renderer"
+	"github.com/anIcedAntFA/gohome/internal/sys"
+	"github.com/anIcedAntFA/gohome/internal/tasksource"
+	"github.com/anIcedAntFA/gohome/internal/webhook"
+)
+
+// ValidationError lists the problems that make the config file unusable.
+type ValidationError struct {
+	Path     string
+	Problems []Problem
+	Hint     string // How to fix the file, a default one when empty
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "invalid config file %s:", e.Path)
+	for _, p := range e.Problems {
+		fmt.Fprintf(&b, "\n   %s", p)
+	}
+	hint := e.Hint
+	if hint == "" {
+		hint = "Fix the file (see 'gohome config validate') or run with --ignore-config"
+	}
+	b.WriteString("\n   " + hint)
+	return b.String()
+}
+
+// Strict reports whether unknown keys are errors. It is on unless the file
+// sets "strict": false.
+func (f *File) Strict() bool {
+	value, ok := f.Get("strict")
+	strict, isBool := value.(bool)
+	return !ok || !isBool || strict
+}
+
+// Errors returns the problems that make the file invalid. Unknown keys
+// only count in strict mode.
+func (f *File) Errors(problems []Problem) []Problem {
+	if f.Strict() {
+		return problems
+	}
+
+	var errs []Problem
+	for _, p := range problems {
+		if !p.Unknown {
+			errs = append(errs, p)
+		}
+	}
+	return errs
+}
+
+// builtinChecks validates the values the type check cannot catch.
+func (f *File) builtinChecks() []Check {
+	oneOf := func(what string, allowed ...string) func(any) error {
+		return func(value any) error {
+			if s, ok := value.(string); ok && !slices.Contains(allowed, s) {
+				return fmt.Errorf("unknown %s %q (use %s)", what, s, strings.Join(allowed, ", "))
+			}
+			return nil
+		}
+	}
+
+	checks := []Check{
+		{Pattern: "format", Validate: oneOf("format", renderer.Formats()...)},
+		{Pattern: "color", Validate: oneOf("color mode", sys.ColorAuto, sys.ColorAlways, sys.ColorNever)},
+		{Pattern: "git_backend", Validate: oneOf("git backend", git.Backends()...)},
+		{Pattern: "email.security", Validate: oneOf("security", mail.SecurityStartTLS, mail.SecurityTLS, mail.SecurityNone)},
+		{Pattern: "preset", Validate: func(value any) error {
+			name, _ := value.(string)
+			if _, ok := renderer.LookupStyle(name, nil); ok || f.Has("table_styles."+name) {
+				return nil
+			}
+			return fmt.Errorf("unknown style %q (see 'gohome --style list')", name)
+		}},
+		{Pattern: "theme.*", Validate: func(value any) error {
+			spec, _ := value.(string)
+			return renderer.ValidateColor(spec)
+		}},
+		{Pattern: "table_styles.*", Validate: func(value any) error {
+			var style TableStyle
+			if err := Decode(value, &style); err != nil {
+				return nil // Reported by the type check
+			}
+			return renderer.ValidateStyleSpec(renderer.StyleSpec{Border: style.Border, HeaderColor: style.HeaderColor})
+		}},
+		{Pattern: "tasks.*.schedule", Validate: func(value any) error {
+			var schedule entity.Schedule
+			if err := Decode(value, &schedule); err != nil {
+				return nil // Reported by the type check
+			}
+			return schedule.Validate()
+		}},
+		{Pattern: "task_sources.*.type", Validate: oneOf("task source type", tasksource.Types()...)},
+		{Pattern: "task_sources.*.path", Validate: func(value any) error {
+			if path, _ := value.(string); strings.TrimSpace(path) == "" {
+				return errors.New("must not be empty")
+			}
+			return nil
+		}},
+		{Pattern: "task_sources.*.match", Validate: compiles},
+		{Pattern: "task_sources.*.ignore.*", Validate: compiles},
+		{Pattern: "webhooks.*", Validate: func(value any) error {
+			var hook Webhook
+			if err := Decode(value, &hook); err != nil {
+				return nil // Reported by the type check
+			}
+			if hook.Format != "" && !slices.Contains(renderer.Formats(), hook.Format) {
+				return fmt.Errorf("unknown format %q", hook.Format)
+			}
+			return webhook.Destination{Type: hook.Type, URL: hook.URL}.Validate()
+		}},
+	}
+
+	for key := range timeKeys {
+		if key != "today" && key != "since_last" {
+			checks = append(checks, Check{Pattern: key, Validate: notNegative})
+		}
+	}
+
+	// Profiles hold the same settings
+	for _, check := range checks {
+		checks = append(checks, Check{Pattern: "profiles.*." + check.Pattern, Validate: check.Validate})
+	}
+	checks = append(checks, Check{Pattern: "default_profile", Validate: func(value any) error {
+		name, _ := value.(string)
+		if f.Has("profiles." + name) {
+			return nil
+		}
+		return fmt.Errorf("unknown profile %q%s", name, profileHint(f))
+	}})
+	checks = append(checks, Check{Pattern: "config_version", Validate: func(value any) error {
+		n, _ := value.(json.Number)
+		if version, err := n.Int64(); err == nil && version > ConfigVersion {
+			return fmt.Errorf("written by a newer gohome, this one supports up to version %d", ConfigVersion)
+		}
+		return notNegative(value)
+	}})
+
+	return checks
+}
+
+// notNegative rejects negative numbers.
+func notNegative(value any) error {
+	if n, ok := value.(json.Number); ok && strings.HasPrefix(n.String(), "-") {
+		return errors.New("must not be negative")
+	}
+	return nil
+}
@@ -1,13 +1,28 @@
 // Package entity defines core data structures used throughout the application.
 package entity
 
+import (
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
 // Commit represents a parsed git log entry.
 type Commit struct {
-	Raw     string
-	Type    string
-	Scope   string
-	Message string
-	Icon    string
+	Hash    string    `json:"hash,omitempty"`
+	Date    time.Time `json:"date"`
+	Raw     string    `json:"raw"`
+	Type    string    `json:"type"`
+	Scope   string    `json:"scope"`
+	Message string    `json:"message"`
+	Icon    string    `json:"icon"`
+
+	// Breaking is set for "type!:" subjects and the "breaking" group of custom patterns
+	Breaking bool `json:"breaking,omitempty"`
+
+	// Tickets are the ticket IDs found in the subject, e.g. "PROJ-123"
+	Tickets []string `json:"tickets,omitempty"`
 }
 
 // Task represents a manual or recurring task.
@@ -16,4 +31,100 @@ type Task struct {
 	Message string `json:"message"`
 	Icon    string `json:"icon"`
 	Enabled bool   `json:"enabled"`
+
+	// Days the task happens on, every day when nil
+	Schedule *Schedule `json:"schedule,omitempty"`
+}
+
+// DefaultTaskType is the type of tasks given without one.
+const DefaultTaskType = "misc"
+
+// taskIcons are the icons of the common task types.
+var taskIcons = map[string]string{
+	"meeting":  "📅",
+	"collab":   "👥",
+	"review":   "👀",
+	"testing":  "🧪",
+	"ops":      "🚀",
+	"admin":    "📮",
+	"docs":     "📝",
+	"learning": "📚",
+}
+
+// taskShorthand matches tasks written as "type: message".
+var taskShorthand = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9_-]*):\s+(\S.*)$`)
+
+// TaskIcon returns the icon of a task type, 📌 for other types.
+func TaskIcon(taskType string) string {
+	if icon, ok := taskIcons[taskType]; ok {
+		return icon
+	}
+	return "📌"
+}
+
+// ParseTask reads an enabled task written as "message" or "type: message",
+// e.g. "meeting: Sprint planning". Tasks without a type are misc tasks.
+func ParseTask(s string) Task {
+	taskType, message := DefaultTaskType, strings.TrimSpace(s)
+	if m := taskShorthand.FindStringSubmatch(message); m != nil {
+		taskType, message = strings.ToLower(m[1]), strings.TrimSpace(m[2])
+	}
+	return Task{Type: taskType, Message: message, Icon: TaskIcon(taskType), Enabled: true}
+}
+
+// RepoReport groups the commits found in a single repository.
+type RepoReport struct {
+	Name    string   `json:"name"`
+	Path    string   `json:"path"`
+	URL     string   `json:"url,omitempty"` // Web URL of the repository, empty when unknown
+	Commits []Commit `json:"commits"`
+
+	// TicketURL links ticket IDs, {id} is replaced by the ID
+	TicketURL string `json:"ticket_url,omitempty"`
+}
+
+// TicketLink returns the URL of a ticket, or an empty string when the
+// repository has no ticket URL.
+func (r *RepoReport) TicketLink(id string) string {
+	if r.TicketURL == "" {
+		return ""
+	}
+	return strings.ReplaceAll(r.TicketURL, "{id}", url.PathEscape(id))
+}
+
+// CommitURL returns the web URL of a commit, or an empty string when the
+// repository URL is unknown.
+func (r *RepoReport) CommitURL(hash string) string {
+	if r.URL == "" || hash == "" {
+		return ""
+	}
+
+	// Bitbucket uses "commits", GitHub/GitLab/Gitea use "commit"
+	if strings.Contains(r.URL, "bitbucket.org") {
+		return r.URL + "/commits/" + hash
+	}
+	return r.URL + "/commit/" + hash
+}
+
+// Report is the complete result of one gohome run.
+type Report struct {
+	Author      string       `json:"author"`
+	Period      string       `json:"period"`
+	GeneratedAt time.Time    `json:"generated_at"`
+	Repos       []RepoReport `json:"repos"`
+	Tasks       []Task       `json:"tasks"`
+}
+
+// IsEmpty reports whether the report has neither commits nor tasks.
+func (r *Report) IsEmpty() bool {
+	return len(r.Repos) == 0 && len(r.Tasks) == 0
+}
+
+// CommitCount returns the total number of commits across all repositories.
+func (r *Report) CommitCount() int {
+	total := 0
+	for _, repo := range r.Repos {
+		total += len(repo.Commits)
+	}
+	return total
 }
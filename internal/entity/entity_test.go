@@ -0,0 +1,29 @@
+package entity
+
+import "testing"
+
+func TestParseTask(t *testing.T) {
+	tests := []struct {
+		in                string
+		taskType, message string
+		icon              string
+	}{
+		{"meeting: Sprint planning", "meeting", "Sprint planning", "📅"},
+		{"  Review:   Check open pull requests  ", "review", "Check open pull requests", "👀"},
+		{"on-call: Pager duty", "on-call", "Pager duty", "📌"},
+		{"Plain task", "misc", "Plain task", TaskIcon("misc")},
+		{"note:no space after the colon", "misc", "note:no space after the colon", TaskIcon("misc")},
+		{"https://example.com/runbook", "misc", "https://example.com/runbook", TaskIcon("misc")},
+		{"Read https://example.com: notes", "misc", "Read https://example.com: notes", TaskIcon("misc")},
+		{"docs: see https://example.com", "docs", "see https://example.com", TaskIcon("docs")},
+		{"2fa: Rotate keys", "misc", "2fa: Rotate keys", TaskIcon("misc")},
+		{"meeting:   ", "misc", "meeting:", TaskIcon("misc")},
+	}
+	for _, tt := range tests {
+		got := ParseTask(tt.in)
+		want := Task{Type: tt.taskType, Message: tt.message, Icon: tt.icon, Enabled: true}
+		if got.Type != want.Type || got.Message != want.Message || got.Icon != want.Icon || !got.Enabled || got.Schedule != nil {
+			t.Errorf("ParseTask(%q) = %+v, want %+v", tt.in, got, want)
+		}
+	}
+}
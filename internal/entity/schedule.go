@@ -0,0 +1,196 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DateLayout is the format of the dates of schedules.
+const DateLayout = "2006-01-02"
+
+// Schedule restricts a task to some days. Every rule that is set must
+// match; a schedule without rules matches every day.
+type Schedule struct {
+	// Days of the week, e.g. ["mon", "thu"]
+	Weekdays []string `json:"weekdays,omitempty"`
+	// Every N weeks, counted from the week of Start. Without weekdays the
+	// task happens on the weekday of Start.
+	EveryWeeks int `json:"every_weeks,omitempty"`
+	// Days of the month, negative days count from the end (-1 is the last day)
+	Days []int `json:"days,omitempty"`
+	// First and last day of the task, YYYY-MM-DD
+	Start string `json:"start,omitempty"`
+	End   string `json:"end,omitempty"`
+}
+
+// weekdays maps the weekday names of schedules to their day.
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// WeekdayNames returns the weekday names of schedules, from Monday.
+func WeekdayNames() []string {
+	return []string{"mon", "tue", "wed", "thu", "fri", "sat", "sun"}
+}
+
+// weekdayName returns the schedule name of a weekday, e.g. "mon".
+func weekdayName(day time.Weekday) string {
+	return strings.ToLower(day.String()[:3])
+}
+
+// Validate reports the first invalid rule of the schedule.
+func (s *Schedule) Validate() error {
+	for _, name := range s.Weekdays {
+		if _, ok := weekdays[name]; !ok {
+			return fmt.Errorf("unknown weekday %q (use %s)", name, strings.Join(WeekdayNames(), ", "))
+		}
+	}
+	for _, day := range s.Days {
+		if day == 0 || day < -31 || day > 31 {
+			return fmt.Errorf("day of the month %d must be between 1 and 31, or -31 and -1 from the end", day)
+		}
+	}
+	if s.EveryWeeks < 0 {
+		return errors.New("every_weeks must not be negative")
+	}
+	if s.EveryWeeks > 1 && s.Start == "" {
+		return errors.New("every_weeks needs a start date to count the weeks from")
+	}
+
+	var start, end time.Time
+	var err error
+	if s.Start != "" {
+		if start, err = time.Parse(DateLayout, s.Start); err != nil {
+			return fmt.Errorf("start %q is not a YYYY-MM-DD date", s.Start)
+		}
+	}
+	if s.End != "" {
+		if end, err = time.Parse(DateLayout, s.End); err != nil {
+			return fmt.Errorf("end %q is not a YYYY-MM-DD date", s.End)
+		}
+	}
+	if s.Start != "" && s.End != "" && end.Before(start) {
+		return fmt.Errorf("end %s is before start %s", s.End, s.Start)
+	}
+	return nil
+}
+
+// On reports whether the task happens on the day. Invalid rules never match.
+func (s *Schedule) On(day time.Time) bool {
+	date := civilDate(day)
+
+	var start time.Time
+	if s.Start != "" {
+		var err error
+		if start, err = time.Parse(DateLayout, s.Start); err != nil || date.Before(start) {
+			return false
+		}
+	}
+	if s.End != "" {
+		end, err := time.Parse(DateLayout, s.End)
+		if err != nil || date.After(end) {
+			return false
+		}
+	}
+
+	names := s.Weekdays
+	if len(names) == 0 && s.EveryWeeks > 1 {
+		names = []string{weekdayName(start.Weekday())}
+	}
+	if len(names) > 0 && !slices.Contains(names, weekdayName(date.Weekday())) {
+		return false
+	}
+
+	if len(s.Days) > 0 {
+		last := date.AddDate(0, 1, -date.Day()).Day()
+		if !slices.ContainsFunc(s.Days, func(d int) bool {
+			return d == date.Day() || (d < 0 && last+d+1 == date.Day())
+		}) {
+			return false
+		}
+	}
+
+	if s.EveryWeeks > 1 {
+		weeks := int(startOfWeek(date).Sub(startOfWeek(start)).Hours()/24) / 7
+		if weeks%s.EveryWeeks != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// String describes the schedule, e.g. "mon, thu every 2 weeks from 2026-01-05".
+func (s *Schedule) String() string {
+	var parts []string
+	if len(s.Weekdays) > 0 {
+		parts = append(parts, strings.Join(s.Weekdays, ", "))
+	}
+	if s.EveryWeeks > 1 {
+		parts = append(parts, fmt.Sprintf("every %d weeks", s.EveryWeeks))
+	}
+	if len(s.Days) > 0 {
+		days := make([]string, len(s.Days))
+		for i, d := range s.Days {
+			days[i] = fmt.Sprint(d)
+		}
+		parts = append(parts, "on day "+strings.Join(days, ", "))
+	}
+	if s.Start != "" {
+		parts = append(parts, "from "+s.Start)
+	}
+	if s.End != "" {
+		parts = append(parts, "until "+s.End)
+	}
+	if len(parts) == 0 {
+		return "every day"
+	}
+	return strings.Join(parts, " ")
+}
+
+// Occurrences returns on how many of the days the task happens. A task
+// without a schedule happens once per report.
+func (t Task) Occurrences(days []time.Time) int {
+	if t.Schedule == nil {
+		return 1
+	}
+	n := 0
+	for _, day := range days {
+		if t.Schedule.On(day) {
+			n++
+		}
+	}
+	return n
+}
+
+// ReportDays returns the days a report period covers: the days starting
+// within it, or the day of to for periods shorter than a day.
+func ReportDays(from, to time.Time) []time.Time {
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	if day.Before(from) {
+		day = day.AddDate(0, 0, 1)
+	}
+
+	var days []time.Time
+	for ; !day.After(to); day = day.AddDate(0, 0, 1) {
+		days = append(days, day)
+	}
+	if len(days) == 0 {
+		days = append(days, time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, to.Location()))
+	}
+	return days
+}
+
+// civilDate returns the calendar date of t at midnight UTC, so days can be
+// counted without daylight saving shifts.
+func civilDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// startOfWeek returns the Monday of the week of a civil date.
+func startOfWeek(date time.Time) time.Time {
+	return date.AddDate(0, 0, -((int(date.Weekday()) + 6) % 7))
+}
@@ -0,0 +1,85 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func date(s string) time.Time {
+	t, err := time.ParseInLocation(DateLayout, s, time.Local)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestScheduleOn(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule Schedule
+		day      string
+		want     bool
+	}{
+		{"weekday matches", Schedule{Weekdays: []string{"mon", "thu"}}, "2026-10-15", true},
+		{"weekday differs", Schedule{Weekdays: []string{"mon", "thu"}}, "2026-10-16", false},
+		{"every other week, on week", Schedule{EveryWeeks: 2, Start: "2026-10-05"}, "2026-10-19", true},
+		{"every other week, off week", Schedule{EveryWeeks: 2, Start: "2026-10-05"}, "2026-10-12", false},
+		{"every other week, other weekday", Schedule{EveryWeeks: 2, Start: "2026-10-05"}, "2026-10-20", false},
+		{"day of the month", Schedule{Days: []int{1, 15}}, "2026-10-15", true},
+		{"last day of the month", Schedule{Days: []int{-1}}, "2026-02-28", true},
+		{"not the last day", Schedule{Days: []int{-1}}, "2026-03-30", false},
+		{"before start", Schedule{Start: "2026-10-10"}, "2026-10-09", false},
+		{"last day of the range", Schedule{Start: "2026-10-01", End: "2026-10-31"}, "2026-10-31", true},
+		{"after end", Schedule{End: "2026-10-31"}, "2026-11-01", false},
+	}
+
+	for _, tt := range tests {
+		if err := tt.schedule.Validate(); err != nil {
+			t.Fatalf("%s: invalid schedule: %v", tt.name, err)
+		}
+		if got := tt.schedule.On(date(tt.day)); got != tt.want {
+			t.Errorf("%s: On(%s) = %v, want %v", tt.name, tt.day, got, tt.want)
+		}
+	}
+}
+
+func TestOccurrencesOverAWeek(t *testing.T) {
+	// Wednesday to Wednesday: Thursday to the next Wednesday are reported
+	now := date("2026-10-14").Add(10 * time.Hour)
+	days := ReportDays(now.AddDate(0, 0, -7), now)
+	if len(days) != 7 {
+		t.Fatalf("got %d days, want 7", len(days))
+	}
+
+	standup := Task{Schedule: &Schedule{Weekdays: []string{"mon", "tue", "wed", "thu", "fri"}}}
+	if n := standup.Occurrences(days); n != 5 {
+		t.Errorf("standup happens %d times, want 5", n)
+	}
+	if n := (Task{}).Occurrences(days); n != 1 {
+		t.Errorf("unscheduled task happens %d times, want 1", n)
+	}
+}
+
+func TestReportDaysShorterThanADay(t *testing.T) {
+	now := date("2026-10-14").Add(10 * time.Hour)
+	days := ReportDays(now.Add(-2*time.Hour), now)
+	if len(days) != 1 || !days[0].Equal(date("2026-10-14")) {
+		t.Errorf("got %v, want the day of the report", days)
+	}
+}
+
+func TestScheduleValidate(t *testing.T) {
+	invalid := []Schedule{
+		{Weekdays: []string{"monday"}},
+		{Days: []int{0}},
+		{Days: []int{32}},
+		{EveryWeeks: 2},
+		{Start: "10/05/2026"},
+		{Start: "2026-10-05", End: "2026-10-01"},
+	}
+	for _, s := range invalid {
+		if err := s.Validate(); err == nil {
+			t.Errorf("%+v: expected an error", s)
+		}
+	}
+}
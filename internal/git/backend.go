@@ -0,0 +1,68 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Backend names.
+const (
+	BackendExec   = "exec"
+	BackendNative = "native"
+)
+
+// Backend reads the user, history and remotes of repositories. Client runs
+// the git binary, Native reads the repository files itself.
+type Backend interface {
+	// GetUser returns user.name from the git config, or an empty string.
+	GetUser(ctx context.Context) string
+	// GetLogs returns the commits of the author within the period, newest first.
+	GetLogs(ctx context.Context, repoPath, author, period string) ([]LogEntry, error)
+	// GetLogsRange is GetLogs within a revision range such as "a1b2c3..HEAD".
+	GetLogsRange(ctx context.Context, repoPath, author, period, revRange string) ([]LogEntry, error)
+	// GetRemoteURL returns the URL of the "origin" remote, or an empty string.
+	GetRemoteURL(ctx context.Context, repoPath string) string
+	// Head returns the commit hash HEAD points to.
+	Head(ctx context.Context, repoPath string) (string, error)
+	// IsAncestor reports whether the commit ancestor is reachable from commit.
+	IsAncestor(ctx context.Context, repoPath, ancestor, commit string) bool
+	// Status counts the tracked files with changes to commit.
+	Status(ctx context.Context, repoPath string) (Status, error)
+	// Branches returns the checked out branch, empty when HEAD is detached,
+	// and the local branches sorted by name.
+	Branches(ctx context.Context, repoPath string) (current string, local []string, err error)
+}
+
+// Status counts the files of a working tree with changes to commit.
+// Untracked files and submodules are left out.
+type Status struct {
+	Staged   int // Files whose index entry differs from HEAD
+	Unstaged int // Files whose working copy differs from the index
+}
+
+// Clean reports whether there is nothing to commit.
+func (s Status) Clean() bool {
+	return s.Staged == 0 && s.Unstaged == 0
+}
+
+var (
+	_ Backend = (*Client)(nil)
+	_ Backend = (*Native)(nil)
+)
+
+// Backends returns the names of the available backends.
+func Backends() []string {
+	return []string{BackendExec, BackendNative}
+}
+
+// NewBackend returns the backend with the given name, exec by default.
+func NewBackend(name string) (Backend, error) {
+	switch name {
+	case "", BackendExec:
+		return NewClient(), nil
+	case BackendNative:
+		return NewNative(), nil
+	}
+	return nil, fmt.Errorf("unknown git backend %q (use %s)", name, strings.Join(Backends(), ", "))
+}
@@ -1,4 +1,5 @@
-// Package git provides wrapper functions for executing git commands.
+// Package git reads the history of git repositories, either by running git
+// or by reading the repository files.
 package git
 
 import (
@@ -6,9 +7,21 @@ import (
 	"os/exec"
 	"regexp"
 	"strings"
+	"time"
 )
 
-// Client handles git command executions.
+// fieldSep separates fields in the custom git log format.
+const fieldSep = "\x1f"
+
+// LogEntry is a single commit returned by git log.
+type LogEntry struct {
+	Hash      string
+	Date      time.Time // Author date
+	Committed time.Time // Committer date, the one --since filters on
+	Subject   string
+}
+
+// Client is the exec backend: it handles git command executions.
 type Client struct{}
 
 // NewClient creates a new git client.
@@ -33,19 +46,30 @@ func sanitizeInput(input string) string {
 	return re.ReplaceAllString(input, "")
 }
 
-// GetLogs returns raw commit messages as a slice of strings.
-func (c *Client) GetLogs(ctx context.Context, repoPath, author, period string) ([]string, error) {
+// GetLogs returns the commits of the author within the period, newest first.
+func (c *Client) GetLogs(ctx context.Context, repoPath, author, period string) ([]LogEntry, error) {
+	return c.GetLogsRange(ctx, repoPath, author, period, "")
+}
+
+// GetLogsRange returns the commits of the author within the period and the
+// revision range, e.g. "a1b2c3..HEAD", newest first. An empty range is HEAD.
+func (c *Client) GetLogsRange(ctx context.Context, repoPath, author, period, revRange string) ([]LogEntry, error) {
 	// Sanitize inputs to prevent command injection
 	safeAuthor := sanitizeInput(author)
 	safePeriod := sanitizeInput(period)
 
-	// #nosec G204 -- inputs are sanitized above
-	cmd := exec.CommandContext(ctx, "git", "log",
-		"--author="+safeAuthor,
-		"--since="+safePeriod,
-		"--pretty=format:%s",
+	args := []string{"log",
+		"--author=" + safeAuthor,
+		"--since=" + safePeriod,
+		"--pretty=format:%H" + fieldSep + "%aI" + fieldSep + "%cI" + fieldSep + "%s",
 		"--no-merges", // Exclude merge commits
-	)
+	}
+	if safeRange := sanitizeInput(revRange); safeRange != "" && !strings.HasPrefix(safeRange, "-") {
+		args = append(args, safeRange, "--")
+	}
+
+	// #nosec G204 -- inputs are sanitized above
+	cmd := exec.CommandContext(ctx, "git", args...)
 	cmd.Dir = repoPath
 	output, err := cmd.Output()
 	if err != nil {
@@ -54,8 +78,107 @@ func (c *Client) GetLogs(ctx context.Context, repoPath, author, period string) (
 
 	strOutput := strings.TrimSpace(string(output))
 	if strOutput == "" {
-		return []string{}, nil
+		return []LogEntry{}, nil
+	}
+
+	lines := strings.Split(strOutput, "\n")
+	entries := make([]LogEntry, 0, len(lines))
+	for _, line := range lines {
+		entries = append(entries, parseLogLine(line))
+	}
+
+	return entries, nil
+}
+
+// parseLogLine splits a formatted log line into its fields.
+func parseLogLine(line string) LogEntry {
+	parts := strings.SplitN(line, fieldSep, 4)
+	if len(parts) != 4 {
+		return LogEntry{Subject: line}
+	}
+
+	date, _ := time.Parse(time.RFC3339, parts[1])
+	committed, _ := time.Parse(time.RFC3339, parts[2])
+	return LogEntry{Hash: parts[0], Date: date, Committed: committed, Subject: parts[3]}
+}
+
+// Head returns the commit hash HEAD points to. It fails for repositories
+// without commits.
+func (c *Client) Head(ctx context.Context, repoPath string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "rev-parse", "--verify", "--quiet", "HEAD")
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// IsAncestor reports whether the commit ancestor is reachable from commit.
+func (c *Client) IsAncestor(ctx context.Context, repoPath, ancestor, commit string) bool {
+	safeAncestor, safeCommit := sanitizeInput(ancestor), sanitizeInput(commit)
+	if safeAncestor == "" || safeCommit == "" || strings.HasPrefix(safeAncestor, "-") || strings.HasPrefix(safeCommit, "-") {
+		return false
+	}
+
+	// #nosec G204 -- inputs are sanitized above
+	cmd := exec.CommandContext(ctx, "git", "merge-base", "--is-ancestor", safeAncestor, safeCommit)
+	cmd.Dir = repoPath
+	return cmd.Run() == nil
+}
+
+// GetRemoteURL returns the URL of the "origin" remote, or an empty string.
+func (c *Client) GetRemoteURL(ctx context.Context, repoPath string) string {
+	cmd := exec.CommandContext(ctx, "git", "remote", "get-url", "origin")
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
 	}
+	return strings.TrimSpace(string(output))
+}
 
-	return strings.Split(strOutput, "\n"), nil
+// Status counts the tracked files with changes to commit.
+func (c *Client) Status(ctx context.Context, repoPath string) (Status, error) {
+	cmd := exec.CommandContext(ctx, "git", "status", "--porcelain", "--no-renames", "--untracked-files=no", "--ignore-submodules=all")
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return Status{}, err
+	}
+
+	var status Status
+	for _, line := range strings.Split(string(output), "\n") {
+		if len(line) < 2 {
+			continue
+		}
+		// "XY path": X is the index against HEAD, Y the working copy against the index
+		if line[0] != ' ' {
+			status.Staged++
+		}
+		if line[1] != ' ' {
+			status.Unstaged++
+		}
+	}
+	return status, nil
+}
+
+// Branches returns the checked out branch, empty when HEAD is detached,
+// and the local branches sorted by name.
+func (c *Client) Branches(ctx context.Context, repoPath string) (string, []string, error) {
+	cmd := exec.CommandContext(ctx, "git", "for-each-ref", "--format=%(refname:short)", "refs/heads")
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return "", nil, err
+	}
+	local := strings.Fields(string(output))
+
+	cmd = exec.CommandContext(ctx, "git", "symbolic-ref", "--quiet", "--short", "HEAD")
+	cmd.Dir = repoPath
+	current, err := cmd.Output()
+	if err != nil {
+		return "", local, nil // Detached HEAD
+	}
+	return strings.TrimSpace(string(current)), local, nil
 }
@@ -0,0 +1,153 @@
+package git
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// gitConfig holds the values of git config files, by "section.key" or
+// "section.subsection.key". Sections and keys are lowercased, subsections
+// are case-sensitive as in git. Later values override earlier ones.
+type gitConfig map[string]string
+
+// readConfigFiles reads config files in order, skipping missing ones.
+// Includes are not followed.
+func readConfigFiles(paths ...string) gitConfig {
+	cfg := gitConfig{}
+	for _, path := range paths {
+		if path != "" {
+			_ = cfg.read(path)
+		}
+	}
+	return cfg
+}
+
+// read adds the values of a config file.
+func (c gitConfig) read(path string) error {
+	// #nosec G304 -- git config files of the user or the repository
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	section := ""
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+
+		if line[0] == '[' {
+			end := strings.LastIndexByte(line, ']')
+			if end < 0 {
+				continue
+			}
+			section = configSection(line[1:end])
+			line = strings.TrimSpace(line[end+1:])
+			if line == "" || line[0] == '#' || line[0] == ';' {
+				continue
+			}
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			value = "true" // A key alone is a true boolean
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		if section != "" && key != "" {
+			c[section+"."+key] = configValue(value)
+		}
+	}
+	return scanner.Err()
+}
+
+// configSection normalizes a section header: `remote "origin"` becomes
+// "remote.origin", the legacy `branch.main` form is kept as is.
+func configSection(header string) string {
+	name, sub, ok := strings.Cut(strings.TrimSpace(header), " ")
+	if !ok {
+		return strings.ToLower(name)
+	}
+	sub = strings.TrimSpace(sub)
+	sub = strings.TrimSuffix(strings.TrimPrefix(sub, `"`), `"`)
+	sub = strings.NewReplacer(`\"`, `"`, `\\`, `\`).Replace(sub)
+	return strings.ToLower(name) + "." + sub
+}
+
+// configValue unquotes a value and drops its trailing comment.
+func configValue(raw string) string {
+	var b strings.Builder
+	quoted := false
+	raw = strings.TrimSpace(raw)
+	for i := 0; i < len(raw); i++ {
+		ch := raw[i]
+		switch {
+		case ch == '"':
+			quoted = !quoted
+		case ch == '\\' && i+1 < len(raw):
+			i++
+			switch raw[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			default:
+				b.WriteByte(raw[i])
+			}
+		case (ch == '#' || ch == ';') && !quoted:
+			return strings.TrimSpace(b.String())
+		default:
+			b.WriteByte(ch)
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// userConfigFiles returns the system and global config files in the order
+// git reads them.
+func userConfigFiles() []string {
+	var files []string
+	if os.Getenv("GIT_CONFIG_NOSYSTEM") == "" {
+		files = append(files, "/etc/gitconfig")
+	}
+	if global := os.Getenv("GIT_CONFIG_GLOBAL"); global != "" {
+		return append(files, global)
+	}
+
+	xdg := os.Getenv("XDG_CONFIG_HOME")
+	home, _ := os.UserHomeDir()
+	if xdg == "" && home != "" {
+		xdg = filepath.Join(home, ".config")
+	}
+	if xdg != "" {
+		files = append(files, filepath.Join(xdg, "git", "config"))
+	}
+	if home != "" {
+		files = append(files, filepath.Join(home, ".gitconfig"))
+	}
+	return files
+}
+
+// findGitDir looks for the repository containing dir, like git does from
+// the working directory.
+func findGitDir(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		if gitDir, err := resolveGitDir(dir); err == nil {
+			return gitDir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", errors.New("not a git repository")
+		}
+		dir = parent
+	}
+}
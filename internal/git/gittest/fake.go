@@ -0,0 +1,241 @@
+package gittest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anIcedAntFA/gohome/internal/git"
+)
+
+var _ git.Backend = (*Fake)(nil)
+
+// Fake is an in-memory git.Backend. Its repositories are keyed by path,
+// and it records the calls made to it.
+type Fake struct {
+	User  string
+	Repos map[string]*FakeRepo
+
+	mu    sync.Mutex
+	calls []string
+}
+
+// FakeRepo is the history and state of a fake repository.
+type FakeRepo struct {
+	Commits  map[string]*FakeCommit // By hash
+	Head     string                 // Hash of HEAD, empty before the first commit
+	Branch   string                 // Checked out branch, empty when detached
+	Branches []string
+	Remote   string // URL of origin
+	Status   git.Status
+}
+
+// FakeCommit is a commit of a fake repository.
+type FakeCommit struct {
+	Hash      string
+	Parents   []string
+	Author    string // "Name <email>", matched by the author filter
+	Date      time.Time
+	Committed time.Time
+	Subject   string
+}
+
+// NewFake returns a fake without repositories.
+func NewFake() *Fake {
+	return &Fake{Repos: map[string]*FakeRepo{}}
+}
+
+// Repo returns the repository at path, creating an empty one on main.
+func (f *Fake) Repo(path string) *FakeRepo {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Repos == nil {
+		f.Repos = map[string]*FakeRepo{}
+	}
+	r, ok := f.Repos[path]
+	if !ok {
+		r = &FakeRepo{Commits: map[string]*FakeCommit{}, Branch: "main", Branches: []string{"main"}}
+		f.Repos[path] = r
+	}
+	return r
+}
+
+// Commit adds a commit on top of HEAD, committed when it was authored, and
+// moves HEAD to it. The hash is made up from the number of commits.
+func (r *FakeRepo) Commit(author, subject string, at time.Time) string {
+	hash := fmt.Sprintf("%040x", len(r.Commits)+1)
+	c := &FakeCommit{Hash: hash, Author: author + " <" + author + "@example.com>", Date: at, Committed: at, Subject: subject}
+	if r.Head != "" {
+		c.Parents = []string{r.Head}
+	}
+	r.Commits[hash] = c
+	r.Head = hash
+	return hash
+}
+
+// Calls returns the calls made so far, e.g. "GetLogsRange /work/api a..b".
+func (f *Fake) Calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.calls...)
+}
+
+// record notes a call and returns the repository it is about.
+func (f *Fake) record(method, repoPath string, args ...string) (*FakeRepo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, strings.TrimSpace(method+" "+repoPath+" "+strings.Join(args, " ")))
+	r, ok := f.Repos[repoPath]
+	if !ok {
+		return nil, fmt.Errorf("%s: not a git repository", repoPath)
+	}
+	return r, nil
+}
+
+// GetUser returns User.
+func (f *Fake) GetUser(_ context.Context) string {
+	return f.User
+}
+
+// GetLogs returns the commits of the author within the period, newest first.
+func (f *Fake) GetLogs(ctx context.Context, repoPath, author, period string) ([]git.LogEntry, error) {
+	return f.GetLogsRange(ctx, repoPath, author, period, "")
+}
+
+// GetLogsRange returns the commits of the author within the period and the
+// revision range, newest first. Periods are "@<unix time>" as with git, and
+// revisions are hashes or HEAD.
+func (f *Fake) GetLogsRange(_ context.Context, repoPath, author, period, revRange string) ([]git.LogEntry, error) {
+	r, err := f.record("GetLogsRange", repoPath, revRange)
+	if err != nil {
+		return nil, err
+	}
+	secs, ok := strings.CutPrefix(period, "@")
+	n, err := strconv.ParseInt(secs, 10, 64)
+	if !ok || err != nil {
+		return nil, fmt.Errorf("unsupported period %q", period)
+	}
+	since := time.Unix(n, 0)
+	authorRe, err := regexp.Compile(author)
+	if err != nil {
+		return nil, err
+	}
+
+	from, to, isRange := strings.Cut(revRange, "..")
+	if !isRange {
+		from, to = "", revRange
+	}
+	include, err := r.reachable(to)
+	if err != nil {
+		return nil, err
+	}
+	if isRange {
+		exclude, err := r.reachable(from)
+		if err != nil {
+			return nil, err
+		}
+		for hash := range exclude {
+			delete(include, hash)
+		}
+	}
+
+	commits := make([]*FakeCommit, 0, len(include))
+	for hash := range include {
+		c := r.Commits[hash]
+		if len(c.Parents) < 2 && !c.Committed.Before(since) && authorRe.MatchString(c.Author) {
+			commits = append(commits, c)
+		}
+	}
+	sort.Slice(commits, func(i, j int) bool {
+		if !commits[i].Committed.Equal(commits[j].Committed) {
+			return commits[i].Committed.After(commits[j].Committed)
+		}
+		return commits[i].Hash > commits[j].Hash
+	})
+
+	entries := make([]git.LogEntry, 0, len(commits))
+	for _, c := range commits {
+		entries = append(entries, git.LogEntry{Hash: c.Hash, Date: c.Date, Committed: c.Committed, Subject: c.Subject})
+	}
+	return entries, nil
+}
+
+// reachable returns the commits reachable from a revision.
+func (r *FakeRepo) reachable(rev string) (map[string]bool, error) {
+	if rev == "" || rev == "HEAD" {
+		rev = r.Head
+	}
+	if _, ok := r.Commits[rev]; !ok {
+		return nil, fmt.Errorf("unknown revision %q", rev)
+	}
+
+	seen := map[string]bool{}
+	stack := []string{rev}
+	for len(stack) > 0 {
+		hash := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if seen[hash] {
+			continue
+		}
+		seen[hash] = true
+		if c, ok := r.Commits[hash]; ok {
+			stack = append(stack, c.Parents...)
+		}
+	}
+	return seen, nil
+}
+
+// GetRemoteURL returns the Remote of the repository.
+func (f *Fake) GetRemoteURL(_ context.Context, repoPath string) string {
+	r, err := f.record("GetRemoteURL", repoPath)
+	if err != nil {
+		return ""
+	}
+	return r.Remote
+}
+
+// Head returns the Head of the repository.
+func (f *Fake) Head(_ context.Context, repoPath string) (string, error) {
+	r, err := f.record("Head", repoPath)
+	if err != nil {
+		return "", err
+	}
+	if r.Head == "" {
+		return "", errors.New("no commits yet")
+	}
+	return r.Head, nil
+}
+
+// IsAncestor reports whether the commit ancestor is reachable from commit.
+func (f *Fake) IsAncestor(_ context.Context, repoPath, ancestor, commit string) bool {
+	r, err := f.record("IsAncestor", repoPath, ancestor, commit)
+	if err != nil {
+		return false
+	}
+	reachable, err := r.reachable(commit)
+	return err == nil && reachable[ancestor]
+}
+
+// Status returns the Status of the repository.
+func (f *Fake) Status(_ context.Context, repoPath string) (git.Status, error) {
+	r, err := f.record("Status", repoPath)
+	if err != nil {
+		return git.Status{}, err
+	}
+	return r.Status, nil
+}
+
+// Branches returns the Branch and Branches of the repository.
+func (f *Fake) Branches(_ context.Context, repoPath string) (string, []string, error) {
+	r, err := f.record("Branches", repoPath)
+	if err != nil {
+		return "", nil, err
+	}
+	return r.Branch, r.Branches, nil
+}
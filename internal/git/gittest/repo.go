@@ -0,0 +1,97 @@
+// Package gittest helps testing code that reads git repositories: Repo
+// builds real repositories with scripted commits, Fake is an in-memory
+// git.Backend.
+package gittest
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Start is the date of the first commit of a Repo, unless changed.
+var Start = time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+
+// Repo is a temporary repository built with the git binary. Commits are
+// made an hour apart from Start by default, so their hashes do not change
+// between runs.
+type Repo struct {
+	t   testing.TB
+	Dir string
+	// Date of the last commit, the next one is Step later
+	Date time.Time
+	Step time.Duration
+}
+
+// NewRepo creates an empty repository on the main branch in dir, or in a
+// temporary directory when dir is empty. Tests are skipped without git.
+func NewRepo(t testing.TB, dir string) *Repo {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	if dir == "" {
+		dir = filepath.Join(t.TempDir(), "repo")
+	}
+
+	r := &Repo{t: t, Dir: dir, Date: Start.Add(-time.Hour), Step: time.Hour}
+	r.Git("init", "-q", "-b", "main", dir)
+	r.Git("config", "user.name", "tester")
+	r.Git("config", "user.email", "tester@example.com")
+	return r
+}
+
+// Git runs git in the repository and returns its trimmed output. The
+// user's and system's git config are ignored.
+func (r *Repo) Git(args ...string) string {
+	r.t.Helper()
+	cmd := exec.Command("git", args...)
+	if _, err := os.Stat(r.Dir); err == nil {
+		cmd.Dir = r.Dir
+	}
+	stamp := fmt.Sprintf("%d +0200", r.Date.Unix())
+	cmd.Env = append(os.Environ(),
+		"HOME="+r.Dir, "XDG_CONFIG_HOME="+r.Dir, "GIT_CONFIG_NOSYSTEM=1",
+		"GIT_AUTHOR_DATE="+stamp, "GIT_COMMITTER_DATE="+stamp)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		r.t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// WriteFile writes a file of the working tree, creating its directories.
+func (r *Repo) WriteFile(name, content string) {
+	r.t.Helper()
+	path := filepath.Join(r.Dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		r.t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		r.t.Fatal(err)
+	}
+}
+
+// Commit commits a new file as author, Step after the previous commit, and
+// returns the commit hash. The author's email is <author>@example.com.
+func (r *Repo) Commit(author, message string) string {
+	r.t.Helper()
+	r.Date = r.Date.Add(r.Step)
+	name := fmt.Sprintf("%d.txt", r.Date.Unix()) // One file per commit, merges never conflict
+	r.WriteFile(name, message+"\n")
+	r.Git("add", name)
+	r.Git("commit", "-q", "--author", author+" <"+author+"@example.com>", "-m", message)
+	return r.Git("rev-parse", "HEAD")
+}
+
+// Merge merges a branch with a merge commit, Step after the previous commit.
+func (r *Repo) Merge(branch string) string {
+	r.t.Helper()
+	r.Date = r.Date.Add(r.Step)
+	r.Git("merge", "-q", "--no-ff", "-m", "Merge branch "+branch, branch)
+	return r.Git("rev-parse", "HEAD")
+}
@@ -0,0 +1,395 @@
+package git
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Native is the native backend: it reads commits, refs and config straight
+// from the repository files, without running git. It handles SHA-1
+// repositories with loose and packed objects, worktrees, alternates and
+// shallow clones.
+type Native struct {
+	mu    sync.Mutex
+	repos map[string]*repository
+}
+
+// NewNative creates a native backend. Repositories stay open until Close.
+func NewNative() *Native {
+	return &Native{repos: map[string]*repository{}}
+}
+
+// Close releases the open repositories.
+func (n *Native) Close() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for path, r := range n.repos {
+		r.objects.close()
+		delete(n.repos, path)
+	}
+	return nil
+}
+
+// open returns the repository of a working tree, opening it once.
+func (n *Native) open(repoPath string) (*repository, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if r, ok := n.repos[repoPath]; ok {
+		return r, nil
+	}
+	r, err := openRepository(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	n.repos[repoPath] = r
+	return r, nil
+}
+
+// GetUser returns user.name from the git config of the working directory,
+// or an empty string.
+func (n *Native) GetUser(_ context.Context) string {
+	files := userConfigFiles()
+	if wd, err := os.Getwd(); err == nil {
+		if gitDir, err := findGitDir(wd); err == nil {
+			files = append(files, filepath.Join(gitDir, "config"))
+		}
+	}
+	return readConfigFiles(files...)["user.name"]
+}
+
+// GetLogs returns the commits of the author within the period, newest first.
+func (n *Native) GetLogs(ctx context.Context, repoPath, author, period string) ([]LogEntry, error) {
+	return n.GetLogsRange(ctx, repoPath, author, period, "")
+}
+
+// GetLogsRange returns the commits of the author within the period and the
+// revision range, e.g. "a1b2c3..HEAD", newest first. An empty range is HEAD.
+// The period is a "@<unix time>" start, or empty for the whole history.
+func (n *Native) GetLogsRange(ctx context.Context, repoPath, author, period, revRange string) ([]LogEntry, error) {
+	since, err := parsePeriod(sanitizeInput(period))
+	if err != nil {
+		return nil, err
+	}
+	// Like git, --author is a regular expression on "Name <email>"
+	authorRe, err := regexp.Compile(sanitizeInput(author))
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := n.open(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	include, exclude, err := r.parseRange(sanitizeInput(revRange))
+	if err != nil {
+		return nil, err
+	}
+
+	commits, err := r.walk(ctx, include, exclude, since)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []LogEntry{}
+	for _, c := range commits {
+		if len(c.parents) > 1 || !authorRe.MatchString(c.author) {
+			continue
+		}
+		entries = append(entries, LogEntry{Hash: c.hash.String(), Date: c.authored, Committed: c.committed, Subject: c.subject})
+	}
+	return entries, nil
+}
+
+// Head returns the commit hash HEAD points to. It fails for repositories
+// without commits.
+func (n *Native) Head(_ context.Context, repoPath string) (string, error) {
+	r, err := n.open(repoPath)
+	if err != nil {
+		return "", err
+	}
+	h, err := r.resolve("HEAD")
+	if err != nil {
+		return "", err
+	}
+	return h.String(), nil
+}
+
+// IsAncestor reports whether the commit ancestor is reachable from commit.
+func (n *Native) IsAncestor(ctx context.Context, repoPath, ancestor, commit string) bool {
+	r, err := n.open(repoPath)
+	if err != nil {
+		return false
+	}
+	a, err := r.resolve(sanitizeInput(ancestor))
+	if err != nil {
+		return false
+	}
+	c, err := r.resolve(sanitizeInput(commit))
+	if err != nil {
+		return false
+	}
+	ok, err := r.isAncestor(ctx, a, c)
+	return err == nil && ok
+}
+
+// GetRemoteURL returns the URL of the "origin" remote, or an empty string.
+func (n *Native) GetRemoteURL(_ context.Context, repoPath string) string {
+	r, err := n.open(repoPath)
+	if err != nil {
+		return ""
+	}
+	return r.config()["remote.origin.url"]
+}
+
+// parsePeriod reads the start of a period as the git backend passes it.
+func parsePeriod(period string) (time.Time, error) {
+	if period == "" {
+		return time.Time{}, nil
+	}
+	if secs, ok := strings.CutPrefix(period, "@"); ok {
+		if n, err := strconv.ParseInt(secs, 10, 64); err == nil {
+			return time.Unix(n, 0), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("the native git backend needs a @<unix time> period, got %q", period)
+}
+
+// parseRange splits "a..b", "b" or "" (HEAD) into the commits to list and
+// the ones whose history to leave out.
+func (r *repository) parseRange(revRange string) (include, exclude []hash, err error) {
+	from, to, isRange := strings.Cut(revRange, "..")
+	if !isRange {
+		from, to = "", revRange
+	}
+	if to == "" {
+		to = "HEAD"
+	}
+	if strings.HasPrefix(to, "-") || strings.HasPrefix(from, "-") {
+		return nil, nil, fmt.Errorf("invalid revision range %q", revRange)
+	}
+
+	h, err := r.resolve(to)
+	if err != nil {
+		return nil, nil, err
+	}
+	include = []hash{h}
+	if isRange {
+		if from == "" {
+			from = "HEAD"
+		}
+		if h, err = r.resolve(from); err != nil {
+			return nil, nil, err
+		}
+		exclude = []hash{h}
+	}
+	return include, exclude, nil
+}
+
+// walkNode is a commit met while walking the history.
+type walkNode struct {
+	commit        *commitObject
+	uninteresting bool // Reachable from an excluded commit
+	expanded      bool // Parents were queued
+	queued        bool
+	seq           int // Queue order among commits of the same date
+}
+
+// walkQueue orders nodes newest first, then in the order they were met.
+type walkQueue []*walkNode
+
+func (q walkQueue) Len() int { return len(q) }
+func (q walkQueue) Less(i, j int) bool {
+	if !q[i].commit.committed.Equal(q[j].commit.committed) {
+		return q[i].commit.committed.After(q[j].commit.committed)
+	}
+	return q[i].seq < q[j].seq
+}
+func (q walkQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *walkQueue) Push(x any)   { *q = append(*q, x.(*walkNode)) }
+func (q *walkQueue) Pop() any {
+	old := *q
+	node := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return node
+}
+
+// walker lists the commits reachable from some commits but not from
+// others, by committer date like git log.
+type walker struct {
+	r           *repository
+	nodes       map[hash]*walkNode
+	queue       walkQueue
+	interesting int // Interesting nodes in the queue
+	seq         int
+}
+
+// walk returns the commits reachable from include but not from exclude
+// and committed since the given time, newest first. As in git log, the
+// history behind a commit older than since is not read.
+func (r *repository) walk(ctx context.Context, include, exclude []hash, since time.Time) ([]*commitObject, error) {
+	w := &walker{r: r, nodes: map[hash]*walkNode{}}
+	for _, h := range exclude {
+		if err := w.add(h, true); err != nil {
+			return nil, err
+		}
+	}
+	for _, h := range include {
+		if err := w.add(h, false); err != nil {
+			return nil, err
+		}
+	}
+
+	var listed []*walkNode
+	for w.interesting > 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		node := heap.Pop(&w.queue).(*walkNode)
+		node.queued = false
+		if !node.uninteresting {
+			w.interesting--
+			if node.commit.committed.Before(since) {
+				continue
+			}
+			listed = append(listed, node)
+		}
+		if err := w.expand(node); err != nil {
+			return nil, err
+		}
+	}
+
+	commits := make([]*commitObject, 0, len(listed))
+	for _, node := range listed {
+		if !node.uninteresting {
+			commits = append(commits, node.commit)
+		}
+	}
+	return commits, nil
+}
+
+// add queues a commit, or marks it uninteresting when already met.
+func (w *walker) add(h hash, uninteresting bool) error {
+	if node, ok := w.nodes[h]; ok {
+		if uninteresting {
+			w.markUninteresting(node)
+		}
+		return nil
+	}
+
+	c, err := w.r.commit(h)
+	if err != nil {
+		return err
+	}
+	node := &walkNode{commit: c, uninteresting: uninteresting, queued: true, seq: w.seq}
+	w.seq++
+	w.nodes[h] = node
+	if !uninteresting {
+		w.interesting++
+	}
+	heap.Push(&w.queue, node)
+	return nil
+}
+
+// expand queues the parents of a node, passing its flag on.
+func (w *walker) expand(node *walkNode) error {
+	node.expanded = true
+	for _, p := range node.commit.parents {
+		if err := w.add(p, node.uninteresting); err != nil {
+			if errors.Is(err, errObjectNotFound) && node.uninteresting {
+				continue // Excluded history only limits the walk
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// markUninteresting flags a node and the ancestors already met, when an
+// excluded commit turns out to reach them.
+func (w *walker) markUninteresting(node *walkNode) {
+	stack := []*walkNode{node}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if n.uninteresting {
+			continue
+		}
+		n.uninteresting = true
+		if n.queued {
+			w.interesting--
+		}
+		if !n.expanded {
+			continue
+		}
+		for _, p := range n.commit.parents {
+			if parent, ok := w.nodes[p]; ok {
+				stack = append(stack, parent)
+			}
+		}
+	}
+}
+
+// isAncestor reports whether a is reachable from c. Commits older than a
+// are not followed: their history cannot lead to it.
+func (r *repository) isAncestor(ctx context.Context, a, c hash) (bool, error) {
+	target, err := r.commit(a)
+	if err != nil {
+		return false, err
+	}
+
+	seen := map[hash]bool{c: true}
+	stack := []hash{c}
+	for len(stack) > 0 {
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+		h := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if h == a {
+			return true, nil
+		}
+
+		commit, err := r.commit(h)
+		if err != nil {
+			return false, err
+		}
+		if commit.committed.Before(target.committed) {
+			continue
+		}
+		for _, p := range commit.parents {
+			if !seen[p] {
+				seen[p] = true
+				stack = append(stack, p)
+			}
+		}
+	}
+	return false, nil
+}
+
+// Status counts the tracked files with changes to commit.
+func (n *Native) Status(ctx context.Context, repoPath string) (Status, error) {
+	r, err := n.open(repoPath)
+	if err != nil {
+		return Status{}, err
+	}
+	return r.status(ctx, repoPath)
+}
+
+// Branches returns the checked out branch, empty when HEAD is detached,
+// and the local branches sorted by name.
+func (n *Native) Branches(_ context.Context, repoPath string) (string, []string, error) {
+	r, err := n.open(repoPath)
+	if err != nil {
+		return "", nil, err
+	}
+	return r.branches()
+}
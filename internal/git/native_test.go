@@ -0,0 +1,290 @@
+package git_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anIcedAntFA/gohome/internal/git"
+	"github.com/anIcedAntFA/gohome/internal/git/gittest"
+)
+
+// epoch is a period covering the whole history. git only takes @<secs>
+// with more than 8 digits for a time.
+const epoch = "@946684800" // 2000-01-01
+
+// body is shared by the commits of history, so git gc stores them as deltas
+// of each other.
+var body = strings.Repeat("Shared commit body line for delta compression.\n", 40)
+
+// history builds branches, a merge and a tag.
+func history(r *gittest.Repo) (first, middle string) {
+	r.Git("remote", "add", "origin", "git@github.com:acme/api.git")
+	first = r.Commit("tester", "feat: first\n\n"+body)
+	r.Commit("someone", "docs: other author\n\n"+body)
+	middle = r.Commit("tester", "fix: middle\nwith a wrapped subject\n\n"+body)
+	r.Git("tag", "-a", "v1", "-m", "release")
+
+	r.Git("checkout", "-q", "-b", "topic")
+	r.Commit("tester", "feat: on topic\n\n"+body)
+	r.Git("checkout", "-q", "main")
+	r.Commit("tester", "chore: on main\n\n"+body)
+	r.Merge("topic")
+	r.Commit("tester", "feat: after merge\n\n"+body)
+	return first, middle
+}
+
+// compare checks the native backend answers like git for a repository.
+func compare(t *testing.T, dir, first, middle string) {
+	t.Helper()
+	ctx := context.Background()
+	client, native := git.NewClient(), git.NewNative()
+	defer func() { _ = native.Close() }()
+
+	head, err := client.Head(ctx, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := native.Head(ctx, dir); err != nil || got != head {
+		t.Errorf("Head = %q, %v; want %q", got, err, head)
+	}
+
+	since := fmt.Sprintf("@%d", gittest.Start.Add(3*time.Hour).Unix())
+	queries := []struct{ name, author, period, revRange string }{
+		{"all", "", epoch, ""},
+		{"author", "tester", epoch, ""},
+		{"email", "someone@example", epoch, ""},
+		{"since", "tester", since, ""},
+		{"range", "", epoch, first + ".." + head},
+		{"range and since", "tester", since, middle + "..HEAD"},
+		{"branch", "", epoch, "topic"},
+		{"tag range", "", epoch, "v1..main"},
+	}
+	for _, q := range queries {
+		want, err := client.GetLogsRange(ctx, dir, q.author, q.period, q.revRange)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := native.GetLogsRange(ctx, dir, q.author, q.period, q.revRange)
+		if err != nil {
+			t.Errorf("%s: %v", q.name, err)
+			continue
+		}
+		if !sameLogs(got, want) {
+			t.Errorf("%s:\n got %v\nwant %v", q.name, got, want)
+		}
+	}
+
+	for _, pair := range [][2]string{{first, head}, {middle, head}, {head, first}, {"v1", "topic"}, {"topic", "v1"}} {
+		want := client.IsAncestor(ctx, dir, pair[0], pair[1])
+		if got := native.IsAncestor(ctx, dir, pair[0], pair[1]); got != want {
+			t.Errorf("IsAncestor(%.7s, %.7s) = %v, want %v", pair[0], pair[1], got, want)
+		}
+	}
+
+	if got, want := native.GetRemoteURL(ctx, dir), client.GetRemoteURL(ctx, dir); got != want {
+		t.Errorf("GetRemoteURL = %q, want %q", got, want)
+	}
+	compareState(t, dir)
+}
+
+// compareState checks the native status and branches match git's.
+func compareState(t *testing.T, dir string) {
+	t.Helper()
+	ctx := context.Background()
+	native := git.NewNative()
+	defer func() { _ = native.Close() }()
+
+	want, err := git.NewClient().Status(ctx, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := native.Status(ctx, dir); err != nil || got != want {
+		t.Errorf("Status = %+v, %v; want %+v", got, err, want)
+	}
+
+	wantCurrent, wantLocal, err := git.NewClient().Branches(ctx, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	current, local, err := native.Branches(ctx, dir)
+	if err != nil || current != wantCurrent || !reflect.DeepEqual(local, wantLocal) {
+		t.Errorf("Branches = %q, %v, %v; want %q, %v", current, local, err, wantCurrent, wantLocal)
+	}
+}
+
+// sameLogs compares entries, dates as instants.
+func sameLogs(got, want []git.LogEntry) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i].Hash != want[i].Hash || got[i].Subject != want[i].Subject ||
+			!got[i].Date.Equal(want[i].Date) || !got[i].Committed.Equal(want[i].Committed) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestNativeMatchesExec(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(r *gittest.Repo) string // Returns the directory to read
+	}{
+		{"loose objects", func(r *gittest.Repo) string { return r.Dir }},
+		{"packed", func(r *gittest.Repo) string {
+			r.Git("gc", "-q")
+			return r.Dir
+		}},
+		{"packed with deltas", func(r *gittest.Repo) string {
+			r.Git("gc", "-q", "--aggressive")
+			return r.Dir
+		}},
+		{"detached head", func(r *gittest.Repo) string {
+			r.Git("checkout", "-q", "--detach", "HEAD~1")
+			return r.Dir
+		}},
+		{"worktree", func(r *gittest.Repo) string {
+			dir := filepath.Join(t.TempDir(), "worktree")
+			r.Git("worktree", "add", "-q", dir, "topic")
+			return dir
+		}},
+		{"alternates", func(r *gittest.Repo) string {
+			r.Git("gc", "-q")
+			dir := filepath.Join(t.TempDir(), "shared")
+			r.Git("clone", "-q", "--shared", r.Dir, dir)
+			r.Git("-C", dir, "remote", "set-url", "origin", "https://github.com/acme/api.git")
+			r.Git("-C", dir, "branch", "-q", "topic", "origin/topic")
+			return dir
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gittest.NewRepo(t, "")
+			first, middle := history(r)
+			dir := tt.setup(r)
+			compare(t, dir, first, middle)
+		})
+	}
+}
+
+func TestNativeShallowClone(t *testing.T) {
+	r := gittest.NewRepo(t, "")
+	history(r)
+	dir := filepath.Join(t.TempDir(), "shallow")
+	r.Git("clone", "-q", "--depth", "2", "file://"+r.Dir, dir)
+
+	ctx := context.Background()
+	want, err := git.NewClient().GetLogs(ctx, dir, "", epoch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	native := git.NewNative()
+	defer func() { _ = native.Close() }()
+	got, err := native.GetLogs(ctx, dir, "", epoch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sameLogs(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestNativeStatus(t *testing.T) {
+	r := gittest.NewRepo(t, "")
+	r.WriteFile("a.txt", "a\n")
+	r.WriteFile("dir/b.txt", "b\n")
+	r.WriteFile("c.sh", "#!/bin/sh\n")
+	r.WriteFile("d.txt", "d\n")
+	compareState(t, r.Dir) // Nothing committed yet, nothing staged
+
+	r.Git("add", ".")
+	compareState(t, r.Dir) // Everything staged
+	r.Commit("tester", "feat: files")
+	compareState(t, r.Dir) // Clean
+
+	r.WriteFile("a.txt", "changed\n") // Unstaged
+	r.WriteFile("dir/b.txt", "staged\n")
+	r.Git("add", "dir/b.txt")
+	r.WriteFile("dir/b.txt", "staged then changed\n") // Both
+	r.Git("rm", "-q", "--cached", "d.txt")            // Deleted from the index
+	r.WriteFile("new.txt", "untracked\n")             // Not counted
+	if err := os.Chmod(filepath.Join(r.Dir, "c.sh"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	compareState(t, r.Dir)
+
+	// Same size: only the content tells
+	r.Git("add", "-A")
+	r.Commit("tester", "feat: all")
+	r.WriteFile("a.txt", "CHANGED\n")
+	compareState(t, r.Dir)
+
+	// Index version 4 compresses the paths
+	r.Git("update-index", "--index-version", "4")
+	compareState(t, r.Dir)
+}
+
+func TestNativeBranches(t *testing.T) {
+	r := gittest.NewRepo(t, "")
+	compareState(t, r.Dir) // Unborn main branch
+
+	r.Commit("tester", "feat: one")
+	r.Git("branch", "feature/nested")
+	r.Git("branch", "zeta")
+	r.Git("pack-refs", "--all")
+	r.Git("branch", "alpha")
+	compareState(t, r.Dir) // Loose and packed branches
+
+	r.Git("checkout", "-q", "feature/nested")
+	compareState(t, r.Dir)
+}
+
+func TestNativeGetUser(t *testing.T) {
+	gittest.NewRepo(t, "")
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	if err := os.WriteFile(filepath.Join(home, ".gitconfig"), []byte("[user]\n\tname = \"Global User\" ; comment\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	if got, want := git.NewNative().GetUser(ctx), git.NewClient().GetUser(ctx); got != want || got == "" {
+		t.Errorf("GetUser = %q, want %q", got, want)
+	}
+}
+
+func TestNativeErrors(t *testing.T) {
+	ctx := context.Background()
+	native := git.NewNative()
+	defer func() { _ = native.Close() }()
+	if _, err := native.GetLogs(ctx, t.TempDir(), "", epoch); err == nil {
+		t.Error("expected an error outside a repository")
+	}
+
+	r := gittest.NewRepo(t, "")
+	r.Commit("tester", "feat: one")
+	if _, err := native.GetLogs(ctx, r.Dir, "", "2 weeks ago"); err == nil {
+		t.Error("expected an error for a period that is not a @<unix time>")
+	}
+	if _, err := native.GetLogsRange(ctx, r.Dir, "", epoch, "missing..HEAD"); err == nil {
+		t.Error("expected an error for an unknown revision")
+	}
+	if native.IsAncestor(ctx, r.Dir, "missing", "HEAD") {
+		t.Error("an unknown revision is no ancestor")
+	}
+
+	empty := gittest.NewRepo(t, "")
+	if _, err := native.Head(ctx, empty.Dir); err == nil {
+		t.Error("expected an error for a repository without commits")
+	}
+}
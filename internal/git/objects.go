@@ -0,0 +1,462 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// hashLen is the length of SHA-1 object names in bytes.
+const hashLen = 20
+
+// hash is the name of a git object.
+type hash [hashLen]byte
+
+// parseHash reads a full hexadecimal object name.
+func parseHash(s string) (hash, error) {
+	var h hash
+	if len(s) != 2*hashLen {
+		return h, fmt.Errorf("invalid object name %q", s)
+	}
+	if _, err := hex.Decode(h[:], []byte(s)); err != nil {
+		return h, fmt.Errorf("invalid object name %q", s)
+	}
+	return h, nil
+}
+
+func (h hash) String() string {
+	return hex.EncodeToString(h[:])
+}
+
+// objectType is the type of a git object, numbered as in packs.
+type objectType int
+
+// Object types.
+const (
+	objectCommit   objectType = 1
+	objectTree     objectType = 2
+	objectBlob     objectType = 3
+	objectTag      objectType = 4
+	objectOfsDelta objectType = 6
+	objectRefDelta objectType = 7
+)
+
+var objectTypeNames = map[string]objectType{
+	"commit": objectCommit, "tree": objectTree, "blob": objectBlob, "tag": objectTag,
+}
+
+// errObjectNotFound is returned for objects missing from the repository,
+// e.g. beyond the history of a shallow clone.
+var errObjectNotFound = errors.New("object not found")
+
+// objectStore reads the objects of a repository, loose or packed, including
+// the ones of alternate object directories.
+type objectStore struct {
+	dirs  []string
+	packs []*pack
+}
+
+// openObjects opens the object directory and its alternates.
+func openObjects(dir string) (*objectStore, error) {
+	s := &objectStore{}
+	if err := s.addDir(dir, 0); err != nil {
+		s.close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// addDir adds an object directory with its packs and alternates.
+func (s *objectStore) addDir(dir string, depth int) error {
+	if depth > 5 {
+		return errors.New("too many nested alternates")
+	}
+	s.dirs = append(s.dirs, dir)
+
+	indexes, err := filepath.Glob(filepath.Join(dir, "pack", "pack-*.idx"))
+	if err != nil {
+		return err
+	}
+	for _, idx := range indexes {
+		p, err := openPack(idx)
+		if err != nil {
+			return fmt.Errorf("%s: %w", idx, err)
+		}
+		s.packs = append(s.packs, p)
+	}
+
+	// #nosec G304 -- alternates belong to the repository being read
+	data, err := os.ReadFile(filepath.Join(dir, "info", "alternates"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !filepath.IsAbs(line) {
+			line = filepath.Join(dir, line)
+		}
+		if err := s.addDir(line, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// close releases the pack files.
+func (s *objectStore) close() {
+	for _, p := range s.packs {
+		_ = p.file.Close()
+	}
+	s.packs = nil
+}
+
+// read returns the type and content of an object.
+func (s *objectStore) read(h hash) (objectType, []byte, error) {
+	for _, p := range s.packs {
+		if off, ok := p.find(h); ok {
+			return p.readAt(s, off, 0)
+		}
+	}
+	for _, dir := range s.dirs {
+		name := h.String()
+		t, data, err := readLoose(filepath.Join(dir, name[:2], name[2:]))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return 0, nil, fmt.Errorf("object %s: %w", name, err)
+		}
+		return t, data, nil
+	}
+	return 0, nil, fmt.Errorf("%w: %s", errObjectNotFound, h)
+}
+
+// readLoose reads a loose object: "<type> <size>\x00<content>", deflated.
+func readLoose(path string) (objectType, []byte, error) {
+	// #nosec G304 -- objects belong to the repository being read
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	z, err := zlib.NewReader(bufio.NewReader(f))
+	if err != nil {
+		return 0, nil, err
+	}
+	defer func() { _ = z.Close() }()
+	data, err := io.ReadAll(z)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	header, content, ok := bytes.Cut(data, []byte{0})
+	if !ok {
+		return 0, nil, errors.New("invalid loose object header")
+	}
+	name, size, _ := strings.Cut(string(header), " ")
+	t, ok := objectTypeNames[name]
+	if n, err := strconv.Atoi(size); !ok || err != nil || n != len(content) {
+		return 0, nil, errors.New("invalid loose object header")
+	}
+	return t, content, nil
+}
+
+// pack is a packfile with its version 2 index.
+type pack struct {
+	file    *os.File
+	fanout  [256]uint32
+	names   []byte // Sorted object names
+	offsets []byte // 4-byte offsets, or indexes into large with the high bit
+	large   []byte // 8-byte offsets
+
+	mu    sync.Mutex
+	bases map[int64]cachedObject // Delta bases already inflated
+}
+
+// cachedObject is an inflated pack entry.
+type cachedObject struct {
+	t    objectType
+	data []byte
+}
+
+// maxCachedBases bounds the delta base cache of a pack.
+const maxCachedBases = 256
+
+// openPack reads the index of a pack and opens the pack.
+func openPack(idxPath string) (*pack, error) {
+	// #nosec G304 -- packs belong to the repository being read
+	idx, err := os.ReadFile(idxPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(idx) < 8+256*4 || !bytes.Equal(idx[:4], []byte{0xff, 't', 'O', 'c'}) || binary.BigEndian.Uint32(idx[4:8]) != 2 {
+		return nil, errors.New("unsupported pack index version")
+	}
+
+	p := &pack{bases: make(map[int64]cachedObject)}
+	for i := range p.fanout {
+		p.fanout[i] = binary.BigEndian.Uint32(idx[8+4*i:])
+	}
+	n := int(p.fanout[255])
+	rest := idx[8+256*4:]
+	if len(rest) < n*(hashLen+4+4) {
+		return nil, errors.New("truncated pack index")
+	}
+	p.names = rest[:n*hashLen]
+	rest = rest[n*hashLen+n*4:] // CRC32s are not checked
+	p.offsets = rest[:n*4]
+	p.large = rest[n*4:]
+
+	// #nosec G304 -- packs belong to the repository being read
+	if p.file, err = os.Open(strings.TrimSuffix(idxPath, ".idx") + ".pack"); err != nil {
+		return nil, err
+	}
+	header := make([]byte, 8)
+	if _, err := p.file.ReadAt(header, 0); err != nil || !bytes.Equal(header[:4], []byte("PACK")) {
+		_ = p.file.Close()
+		return nil, errors.New("invalid pack header")
+	}
+	return p, nil
+}
+
+// find returns the offset of an object in the pack.
+func (p *pack) find(h hash) (int64, bool) {
+	lo := 0
+	if h[0] > 0 {
+		lo = int(p.fanout[h[0]-1])
+	}
+	hi := int(p.fanout[h[0]])
+	i := lo + sort.Search(hi-lo, func(i int) bool {
+		return bytes.Compare(p.names[(lo+i)*hashLen:(lo+i+1)*hashLen], h[:]) >= 0
+	})
+	if i >= hi || !bytes.Equal(p.names[i*hashLen:(i+1)*hashLen], h[:]) {
+		return 0, false
+	}
+
+	off := binary.BigEndian.Uint32(p.offsets[i*4:])
+	if off&0x80000000 == 0 {
+		return int64(off), true
+	}
+	j := int(off & 0x7fffffff)
+	if len(p.large) < (j+1)*8 {
+		return 0, false
+	}
+	return int64(binary.BigEndian.Uint64(p.large[j*8:])), true // #nosec G115 -- pack offsets fit in int64
+}
+
+// readAt returns the object at an offset of the pack, resolving deltas.
+func (p *pack) readAt(s *objectStore, off int64, depth int) (objectType, []byte, error) {
+	if depth > 10000 {
+		return 0, nil, errors.New("delta chain too long")
+	}
+	p.mu.Lock()
+	cached, ok := p.bases[off]
+	p.mu.Unlock()
+	if ok {
+		return cached.t, cached.data, nil
+	}
+
+	r := bufio.NewReader(io.NewSectionReader(p.file, off, 1<<62))
+	t, size, err := readEntryHeader(r)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var base cachedObject
+	switch t {
+	case objectCommit, objectTree, objectBlob, objectTag:
+		data, err := inflate(r, size)
+		return t, data, err
+	case objectOfsDelta:
+		rel, err := readOffset(r)
+		if err != nil {
+			return 0, nil, err
+		}
+		if rel <= 0 || rel > off {
+			return 0, nil, errors.New("invalid delta base offset")
+		}
+		if base.t, base.data, err = p.readAt(s, off-rel, depth+1); err != nil {
+			return 0, nil, err
+		}
+		p.cache(off-rel, base)
+	case objectRefDelta:
+		var h hash
+		if _, err := io.ReadFull(r, h[:]); err != nil {
+			return 0, nil, err
+		}
+		if base.t, base.data, err = s.read(h); err != nil {
+			return 0, nil, err
+		}
+	default:
+		return 0, nil, fmt.Errorf("unknown pack object type %d", t)
+	}
+
+	delta, err := inflate(r, size)
+	if err != nil {
+		return 0, nil, err
+	}
+	data, err := applyDelta(base.data, delta)
+	return base.t, data, err
+}
+
+// cache keeps an inflated delta base, forgetting all of them when full.
+func (p *pack) cache(off int64, obj cachedObject) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.bases) >= maxCachedBases {
+		p.bases = make(map[int64]cachedObject)
+	}
+	p.bases[off] = obj
+}
+
+// readEntryHeader reads the type and inflated size of a pack entry.
+func readEntryHeader(r io.ByteReader) (objectType, int64, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return 0, 0, err
+	}
+	t := objectType((b >> 4) & 7)
+	size := int64(b & 0x0f)
+	for shift := 4; b&0x80 != 0; shift += 7 {
+		if b, err = r.ReadByte(); err != nil {
+			return 0, 0, err
+		}
+		if shift > 56 {
+			return 0, 0, errors.New("invalid pack entry size")
+		}
+		size |= int64(b&0x7f) << shift
+	}
+	return t, size, nil
+}
+
+// readOffset reads the base offset of an offset delta, relative to the
+// delta entry.
+func readOffset(r io.ByteReader) (int64, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	off := int64(b & 0x7f)
+	for b&0x80 != 0 {
+		if b, err = r.ReadByte(); err != nil {
+			return 0, err
+		}
+		if off > 1<<55 {
+			return 0, errors.New("invalid delta base offset")
+		}
+		off = (off+1)<<7 | int64(b&0x7f)
+	}
+	return off, nil
+}
+
+// inflate reads size bytes of deflated data. The buffer grows with the
+// data actually inflated, so a corrupt size cannot allocate more.
+func inflate(r io.Reader, size int64) ([]byte, error) {
+	z, err := zlib.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = z.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(z, size))
+	if err != nil {
+		return nil, fmt.Errorf("inflating pack entry: %w", err)
+	}
+	if int64(len(data)) != size {
+		return nil, fmt.Errorf("inflating pack entry: %w", io.ErrUnexpectedEOF)
+	}
+	return data, nil
+}
+
+// applyDelta rebuilds an object from its base and a delta: the sizes of
+// the base and the result, then instructions copying ranges of the base
+// or inserting new bytes.
+func applyDelta(base, delta []byte) ([]byte, error) {
+	errInvalid := errors.New("invalid delta")
+	r := bytes.NewReader(delta)
+	srcSize, err := binary.ReadUvarint(r)
+	if err != nil || srcSize != uint64(len(base)) {
+		return nil, errInvalid
+	}
+	dstSize, err := binary.ReadUvarint(r)
+	if err != nil || dstSize > 1<<32 {
+		return nil, errInvalid
+	}
+
+	// Trust the result size only as far as the base and the delta can
+	// fill it, and never let the result grow past it
+	out := make([]byte, 0, min(dstSize, uint64(len(base)+len(delta))))
+	for {
+		op, err := r.ReadByte()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		switch {
+		case op&0x80 != 0:
+			// Copy: offset and size bytes are present when their bit is set
+			var off, size uint64
+			for i := 0; i < 4; i++ {
+				if op&(1<<i) != 0 {
+					b, err := r.ReadByte()
+					if err != nil {
+						return nil, errInvalid
+					}
+					off |= uint64(b) << (8 * i)
+				}
+			}
+			for i := 0; i < 3; i++ {
+				if op&(0x10<<i) != 0 {
+					b, err := r.ReadByte()
+					if err != nil {
+						return nil, errInvalid
+					}
+					size |= uint64(b) << (8 * i)
+				}
+			}
+			if size == 0 {
+				size = 0x10000
+			}
+			if off+size > uint64(len(base)) {
+				return nil, errInvalid
+			}
+			out = append(out, base[off:off+size]...)
+		case op != 0:
+			// Insert the next op bytes
+			n := int(op)
+			if r.Len() < n {
+				return nil, errInvalid
+			}
+			chunk := make([]byte, n)
+			_, _ = r.Read(chunk)
+			out = append(out, chunk...)
+		default:
+			return nil, errInvalid
+		}
+		if uint64(len(out)) > dstSize {
+			return nil, errInvalid
+		}
+	}
+
+	if uint64(len(out)) != dstSize {
+		return nil, errInvalid
+	}
+	return out, nil
+}
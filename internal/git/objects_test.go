@@ -0,0 +1,76 @@
+package git
+
+import (
+	"bytes"
+	"compress/zlib"
+	"testing"
+)
+
+// deflate compresses data like a pack entry.
+func deflate(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	z := zlib.NewWriter(&buf)
+	if _, err := z.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := z.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestInflate(t *testing.T) {
+	entry := deflate(t, []byte("tree 4b825dc\n"))
+
+	tests := []struct {
+		name    string
+		size    int64
+		wantErr bool
+	}{
+		{"exact size", 13, false},
+		{"size beyond the data", 13 + 1<<40, true},
+		{"size short of the data", 4, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := inflate(bytes.NewReader(entry), tt.size)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("inflate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && int64(len(data)) != tt.size {
+				t.Errorf("inflate() = %d bytes, want %d", len(data), tt.size)
+			}
+		})
+	}
+}
+
+func TestApplyDelta(t *testing.T) {
+	base := []byte("hello, world")
+
+	tests := []struct {
+		name    string
+		delta   []byte
+		want    string
+		wantErr bool
+	}{
+		// Sizes 12 and 11, copy 5 bytes from 0, insert "!" then copy 5 from 7
+		{"copy and insert", []byte{12, 11, 0x90, 5, 1, '!', 0x91, 7, 5}, "hello!world", false},
+		{"wrong base size", []byte{11, 5, 0x90, 5}, "", true},
+		// Result of 4 GiB announced, nothing allocated for it
+		{"result larger than the delta", []byte{12, 0x80, 0x80, 0x80, 0x80, 0x10, 0x90, 5}, "", true},
+		{"result past its size", []byte{12, 3, 0x90, 5}, "", true},
+		{"copy out of the base", []byte{12, 5, 0x91, 10, 5}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := applyDelta(base, tt.delta)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("applyDelta() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if string(got) != tt.want {
+				t.Errorf("applyDelta() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
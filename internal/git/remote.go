@@ -0,0 +1,41 @@
+package git
+
+import (
+	"net/url"
+	"strings"
+)
+
+// WebURL converts a git remote URL into a browsable https URL.
+// Both SSH ("git@host:owner/repo.git") and HTTP(S) remotes are supported.
+// It returns an empty string when the remote cannot be converted.
+func WebURL(remote string) string {
+	remote = strings.TrimSpace(remote)
+	if remote == "" {
+		return ""
+	}
+
+	var host, path string
+
+	switch {
+	case strings.HasPrefix(remote, "http://"), strings.HasPrefix(remote, "https://"), strings.HasPrefix(remote, "ssh://"):
+		u, err := url.Parse(remote)
+		if err != nil || u.Host == "" {
+			return ""
+		}
+		host = u.Hostname()
+		path = u.Path
+	case strings.Contains(remote, "@") && strings.Contains(remote, ":"):
+		// scp-like syntax: git@github.com:owner/repo.git
+		rest := remote[strings.Index(remote, "@")+1:]
+		host, path, _ = strings.Cut(rest, ":")
+	default:
+		return ""
+	}
+
+	path = strings.Trim(strings.TrimSuffix(path, ".git"), "/")
+	if host == "" || path == "" {
+		return ""
+	}
+
+	return "https://" + host + "/" + path
+}
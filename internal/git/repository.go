@@ -0,0 +1,322 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// repository is a repository opened by the native backend.
+type repository struct {
+	gitDir    string // .git, or the worktree's directory in it
+	commonDir string // Shared by all worktrees: refs, objects and config
+	objects   *objectStore
+	shallow   map[hash]bool
+
+	mu      sync.Mutex
+	commits map[hash]*commitObject
+}
+
+// commitObject is the part of a commit the backend uses.
+type commitObject struct {
+	hash      hash
+	parents   []hash
+	author    string // "Name <email>", as --author matches it
+	authored  time.Time
+	committed time.Time
+	subject   string
+}
+
+// resolveGitDir returns the git directory of a working tree: .git itself,
+// or the directory a .git file points to, as in worktrees and submodules.
+// A bare repository is its own git directory.
+func resolveGitDir(dir string) (string, error) {
+	dotGit := filepath.Join(dir, ".git")
+	info, err := os.Stat(dotGit)
+	switch {
+	case err == nil && info.IsDir():
+		return dotGit, nil
+	case err == nil:
+		// #nosec G304 -- the .git file of the repository being read
+		data, err := os.ReadFile(dotGit)
+		if err != nil {
+			return "", err
+		}
+		target, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+		if !ok {
+			return "", fmt.Errorf("%s: invalid gitdir file", dotGit)
+		}
+		target = strings.TrimSpace(target)
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(dir, target)
+		}
+		return target, nil
+	}
+
+	if isFile(filepath.Join(dir, "HEAD")) && isDir(filepath.Join(dir, "objects")) {
+		return dir, nil
+	}
+	return "", fmt.Errorf("%s: not a git repository", dir)
+}
+
+// openRepository opens the repository of a working tree.
+func openRepository(dir string) (*repository, error) {
+	gitDir, err := resolveGitDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	commonDir := gitDir
+	// #nosec G304 -- the commondir file of the repository being read
+	if data, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		commonDir = strings.TrimSpace(string(data))
+		if !filepath.IsAbs(commonDir) {
+			commonDir = filepath.Join(gitDir, commonDir)
+		}
+	}
+
+	cfg := readConfigFiles(filepath.Join(commonDir, "config"))
+	if format := cfg["extensions.objectformat"]; format != "" && !strings.EqualFold(format, "sha1") {
+		return nil, fmt.Errorf("%s: %s repositories are not supported by the native backend", dir, format)
+	}
+
+	objects, err := openObjects(filepath.Join(commonDir, "objects"))
+	if err != nil {
+		return nil, err
+	}
+	r := &repository{
+		gitDir:    gitDir,
+		commonDir: commonDir,
+		objects:   objects,
+		shallow:   map[hash]bool{},
+		commits:   map[hash]*commitObject{},
+	}
+
+	// #nosec G304 -- the shallow file of the repository being read
+	if data, err := os.ReadFile(filepath.Join(commonDir, "shallow")); err == nil {
+		for _, line := range strings.Fields(string(data)) {
+			if h, err := parseHash(line); err == nil {
+				r.shallow[h] = true
+			}
+		}
+	}
+	return r, nil
+}
+
+// config reads the repository config file.
+func (r *repository) config() gitConfig {
+	return readConfigFiles(filepath.Join(r.commonDir, "config"))
+}
+
+// resolve returns the commit a revision names: a full hash, HEAD, or a
+// branch, tag or remote branch name. Annotated tags are peeled.
+func (r *repository) resolve(rev string) (hash, error) {
+	h, err := parseHash(rev)
+	if err != nil {
+		if h, err = r.resolveName(rev); err != nil {
+			return h, err
+		}
+	}
+
+	for i := 0; i < 10; i++ {
+		t, data, err := r.objects.read(h)
+		if err != nil {
+			return h, err
+		}
+		switch t {
+		case objectCommit:
+			return h, nil
+		case objectTag:
+			target, _, _ := bytes.Cut(data, []byte("\n"))
+			name, ok := bytes.CutPrefix(target, []byte("object "))
+			if !ok {
+				return h, fmt.Errorf("invalid tag %s", h)
+			}
+			if h, err = parseHash(string(name)); err != nil {
+				return h, err
+			}
+		default:
+			return h, fmt.Errorf("%s is not a commit", rev)
+		}
+	}
+	return h, fmt.Errorf("%s: too many nested tags", rev)
+}
+
+// resolveName looks a name up like git does for revisions.
+func (r *repository) resolveName(name string) (hash, error) {
+	if name == "HEAD" {
+		return r.resolveRef("HEAD", 0)
+	}
+	for _, ref := range []string{name, "refs/" + name, "refs/tags/" + name, "refs/heads/" + name, "refs/remotes/" + name, "refs/remotes/" + name + "/HEAD"} {
+		if h, err := r.resolveRef(ref, 0); err == nil {
+			return h, nil
+		}
+	}
+	return hash{}, fmt.Errorf("unknown revision %q", name)
+}
+
+// resolveRef reads a ref, following symbolic refs.
+func (r *repository) resolveRef(ref string, depth int) (hash, error) {
+	if depth > 5 {
+		return hash{}, fmt.Errorf("%s: too many symbolic refs", ref)
+	}
+
+	// HEAD and other pseudo refs belong to the worktree
+	dir := r.commonDir
+	if !strings.HasPrefix(ref, "refs/") {
+		dir = r.gitDir
+	}
+	// #nosec G304 -- refs of the repository being read
+	data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(ref)))
+	if err != nil {
+		return r.packedRef(ref)
+	}
+	value := strings.TrimSpace(string(data))
+	if target, ok := strings.CutPrefix(value, "ref:"); ok {
+		return r.resolveRef(strings.TrimSpace(target), depth+1)
+	}
+	return parseHash(value)
+}
+
+// packedRef looks a ref up in packed-refs.
+func (r *repository) packedRef(ref string) (hash, error) {
+	// #nosec G304 -- refs of the repository being read
+	f, err := os.Open(filepath.Join(r.commonDir, "packed-refs"))
+	if err != nil {
+		return hash{}, fmt.Errorf("unknown ref %q", ref)
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || line[0] == '#' || line[0] == '^' { // Comments and peeled tags
+			continue
+		}
+		value, name, ok := strings.Cut(line, " ")
+		if ok && name == ref {
+			return parseHash(value)
+		}
+	}
+	return hash{}, fmt.Errorf("unknown ref %q", ref)
+}
+
+// commit reads a commit, once per repository.
+func (r *repository) commit(h hash) (*commitObject, error) {
+	r.mu.Lock()
+	c, ok := r.commits[h]
+	r.mu.Unlock()
+	if ok {
+		return c, nil
+	}
+
+	t, data, err := r.objects.read(h)
+	if err != nil {
+		return nil, err
+	}
+	if t != objectCommit {
+		return nil, fmt.Errorf("%s is not a commit", h)
+	}
+	if c, err = parseCommit(h, data); err != nil {
+		return nil, err
+	}
+	if r.shallow[h] {
+		c.parents = nil // Grafted: the history of shallow clones stops here
+	}
+
+	r.mu.Lock()
+	r.commits[h] = c
+	r.mu.Unlock()
+	return c, nil
+}
+
+// parseCommit reads the headers and the subject of a commit.
+func parseCommit(h hash, data []byte) (*commitObject, error) {
+	c := &commitObject{hash: h}
+	header, message, _ := bytes.Cut(data, []byte("\n\n"))
+
+	for _, line := range strings.Split(string(header), "\n") {
+		key, value, _ := strings.Cut(line, " ")
+		var err error
+		switch key {
+		case "parent":
+			var p hash
+			if p, err = parseHash(value); err == nil {
+				c.parents = append(c.parents, p)
+			}
+		case "author":
+			c.author, c.authored, err = parseSignature(value)
+		case "committer":
+			_, c.committed, err = parseSignature(value)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("commit %s: %w", h, err)
+		}
+	}
+
+	c.subject = subject(string(message))
+	return c, nil
+}
+
+// parseSignature splits "Name <email> 1700000000 +0200".
+func parseSignature(value string) (string, time.Time, error) {
+	end := strings.LastIndexByte(value, '>')
+	if end < 0 {
+		return "", time.Time{}, errors.New("invalid signature")
+	}
+	who := value[:end+1]
+
+	fields := strings.Fields(value[end+1:])
+	if len(fields) < 1 {
+		return who, time.Time{}, nil
+	}
+	secs, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return "", time.Time{}, errors.New("invalid signature date")
+	}
+	when := time.Unix(secs, 0)
+	if len(fields) > 1 && len(fields[1]) == 5 {
+		if offset, err := strconv.Atoi(fields[1][1:]); err == nil {
+			seconds := (offset/100*60 + offset%100) * 60
+			if fields[1][0] == '-' {
+				seconds = -seconds
+			}
+			when = when.In(time.FixedZone("", seconds))
+		}
+	}
+	return who, when, nil
+}
+
+// subject returns the first paragraph of a message on one line, like
+// git log's %s.
+func subject(message string) string {
+	var lines []string
+	for _, line := range strings.Split(message, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if strings.TrimSpace(line) == "" {
+			if len(lines) > 0 {
+				break
+			}
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, " ")
+}
+
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,338 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha1" // #nosec G505 -- object names of SHA-1 repositories
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// File modes of index entries and tree entries.
+const (
+	modeTypeMask = 0o170000
+	modeSymlink  = 0o120000
+	modeGitlink  = 0o160000
+	modeDir      = 0o040000
+)
+
+// Flags of index entries.
+const (
+	flagAssumeValid  = 0x8000
+	flagExtended     = 0x4000
+	flagSkipWorktree = 0x4000 // In the extended flags
+	flagIntentToAdd  = 0x2000 // In the extended flags
+)
+
+// indexEntry is a file of the index.
+type indexEntry struct {
+	path     string
+	hash     hash
+	mode     uint32
+	size     uint32
+	mtime    time.Time
+	stage    int
+	flags    uint16
+	extended uint16
+}
+
+// readIndex reads the entries of an index file, versions 2 to 4.
+func readIndex(path string) ([]indexEntry, time.Time, error) {
+	// #nosec G304 -- the index of the repository being read
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, time.Time{}, nil // Nothing staged yet
+	}
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	errInvalid := fmt.Errorf("%s: invalid index", path)
+	if len(data) < 12 || !bytes.Equal(data[:4], []byte("DIRC")) {
+		return nil, time.Time{}, errInvalid
+	}
+	version := binary.BigEndian.Uint32(data[4:8])
+	if version < 2 || version > 4 {
+		return nil, time.Time{}, fmt.Errorf("%s: unsupported index version %d", path, version)
+	}
+	count := int(binary.BigEndian.Uint32(data[8:12]))
+
+	entries := make([]indexEntry, 0, count)
+	pos, name := 12, ""
+	for i := 0; i < count; i++ {
+		start := pos
+		if len(data) < pos+62 {
+			return nil, time.Time{}, errInvalid
+		}
+		e := indexEntry{
+			mtime: time.Unix(int64(binary.BigEndian.Uint32(data[pos+8:])), int64(binary.BigEndian.Uint32(data[pos+12:]))),
+			mode:  binary.BigEndian.Uint32(data[pos+24:]),
+			size:  binary.BigEndian.Uint32(data[pos+36:]),
+			flags: binary.BigEndian.Uint16(data[pos+60:]),
+		}
+		copy(e.hash[:], data[pos+40:pos+60])
+		e.stage = int(e.flags>>12) & 3
+		pos += 62
+		if version >= 3 && e.flags&flagExtended != 0 {
+			if len(data) < pos+2 {
+				return nil, time.Time{}, errInvalid
+			}
+			e.extended = binary.BigEndian.Uint16(data[pos:])
+			pos += 2
+		}
+
+		if version == 4 {
+			// The name drops the end of the previous one and adds a suffix
+			r := bytes.NewReader(data[pos:])
+			strip, err := readOffset(r)
+			if err != nil || strip > int64(len(name)) {
+				return nil, time.Time{}, errInvalid
+			}
+			pos += len(data[pos:]) - r.Len()
+			end := bytes.IndexByte(data[pos:], 0)
+			if end < 0 {
+				return nil, time.Time{}, errInvalid
+			}
+			name = name[:len(name)-int(strip)] + string(data[pos:pos+end])
+			pos += end + 1
+		} else {
+			end := bytes.IndexByte(data[pos:], 0)
+			if end < 0 {
+				return nil, time.Time{}, errInvalid
+			}
+			name = string(data[pos : pos+end])
+			// Entries are padded with NULs to a multiple of 8 bytes
+			pos = start + (pos+end-start+8)&^7
+		}
+		e.path = name
+		entries = append(entries, e)
+	}
+	return entries, info.ModTime(), nil
+}
+
+// tree lists the files of a tree and its subtrees by path.
+func (r *repository) tree(h hash, prefix string, files map[string]indexEntry) error {
+	t, data, err := r.objects.read(h)
+	if err != nil {
+		return err
+	}
+	if t != objectTree {
+		return fmt.Errorf("%s is not a tree", h)
+	}
+
+	for len(data) > 0 {
+		// "<octal mode> <name>\x00<object name>"
+		space := bytes.IndexByte(data, ' ')
+		nul := bytes.IndexByte(data, 0)
+		if space < 0 || nul < space || len(data) < nul+1+hashLen {
+			return fmt.Errorf("invalid tree %s", h)
+		}
+		mode, err := strconv.ParseUint(string(data[:space]), 8, 32)
+		if err != nil {
+			return fmt.Errorf("invalid tree %s", h)
+		}
+		e := indexEntry{path: prefix + string(data[space+1:nul]), mode: uint32(mode)}
+		copy(e.hash[:], data[nul+1:])
+		data = data[nul+1+hashLen:]
+
+		if e.mode&modeTypeMask == modeDir {
+			if err := r.tree(e.hash, e.path+"/", files); err != nil {
+				return err
+			}
+			continue
+		}
+		files[e.path] = e
+	}
+	return nil
+}
+
+// headTree lists the files of the HEAD commit, none before the first one.
+func (r *repository) headTree() (map[string]indexEntry, error) {
+	files := map[string]indexEntry{}
+	head, err := r.resolve("HEAD")
+	if err != nil {
+		return files, nil // No commits yet
+	}
+	t, data, err := r.objects.read(head)
+	if err != nil {
+		return nil, err
+	}
+	if t != objectCommit {
+		return nil, fmt.Errorf("%s is not a commit", head)
+	}
+	line, _, _ := bytes.Cut(data, []byte("\n"))
+	name, ok := bytes.CutPrefix(line, []byte("tree "))
+	if !ok {
+		return nil, fmt.Errorf("commit %s has no tree", head)
+	}
+	tree, err := parseHash(string(name))
+	if err != nil {
+		return nil, err
+	}
+	return files, r.tree(tree, "", files)
+}
+
+// status compares the index with HEAD and the working copy with the index.
+// Clean and smudge filters are not applied to the working copy.
+func (r *repository) status(ctx context.Context, workTree string) (Status, error) {
+	entries, indexTime, err := readIndex(filepath.Join(r.gitDir, "index"))
+	if err != nil {
+		return Status{}, err
+	}
+	head, err := r.headTree()
+	if err != nil {
+		return Status{}, err
+	}
+	fileMode := r.config()["core.filemode"] != "false"
+
+	staged, unstaged := map[string]bool{}, map[string]bool{}
+	indexed := map[string]bool{}
+	for _, e := range entries {
+		if err := ctx.Err(); err != nil {
+			return Status{}, err
+		}
+		indexed[e.path] = true
+		if e.mode&modeTypeMask == modeGitlink {
+			continue
+		}
+		if e.stage != 0 {
+			staged[e.path], unstaged[e.path] = true, true // Unmerged
+			continue
+		}
+		if e.extended&flagIntentToAdd != 0 {
+			unstaged[e.path] = true
+			continue
+		}
+
+		if old, ok := head[e.path]; !ok || old.hash != e.hash || old.mode != e.mode {
+			staged[e.path] = true
+		}
+		if e.flags&flagAssumeValid != 0 || e.extended&flagSkipWorktree != 0 {
+			continue
+		}
+		changed, err := worktreeChanged(filepath.Join(workTree, filepath.FromSlash(e.path)), e, indexTime, fileMode)
+		if err != nil {
+			return Status{}, err
+		}
+		if changed {
+			unstaged[e.path] = true
+		}
+	}
+
+	for path, e := range head {
+		if !indexed[path] && e.mode&modeTypeMask != modeGitlink {
+			staged[path] = true // Deleted from the index
+		}
+	}
+	return Status{Staged: len(staged), Unstaged: len(unstaged)}, nil
+}
+
+// worktreeChanged reports whether a file differs from its index entry. Like
+// git, files whose size and modification time match are not read, unless
+// they changed after the index was written.
+func worktreeChanged(path string, e indexEntry, indexTime time.Time, fileMode bool) (bool, error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return true, nil // Deleted, or replaced by a file up its path
+	}
+
+	isLink := info.Mode()&fs.ModeSymlink != 0
+	switch {
+	case (e.mode&modeTypeMask == modeSymlink) != isLink, !isLink && !info.Mode().IsRegular():
+		return true, nil
+	case fileMode && !isLink && (info.Mode().Perm()&0o100 != 0) != (e.mode&0o100 != 0):
+		return true, nil
+	}
+
+	racy := !info.ModTime().Before(indexTime)
+	if uint32(info.Size()) == e.size && info.ModTime().Equal(e.mtime) && !racy { // #nosec G115 -- the index keeps 32-bit sizes
+		return false, nil
+	}
+
+	var content []byte
+	if isLink {
+		target, err := os.Readlink(path)
+		if err != nil {
+			return false, err
+		}
+		content = []byte(target)
+	} else {
+		// #nosec G304 -- a file of the working tree being read
+		if content, err = os.ReadFile(path); err != nil {
+			return false, err
+		}
+	}
+	return blobHash(content) != e.hash, nil
+}
+
+// blobHash returns the object name of a blob.
+func blobHash(content []byte) hash {
+	var h hash
+	sum := sha1.New() // #nosec G401 -- object names of SHA-1 repositories
+	fmt.Fprintf(sum, "blob %d\x00", len(content))
+	_, _ = sum.Write(content)
+	copy(h[:], sum.Sum(nil))
+	return h
+}
+
+// branches returns the checked out branch and the local branches.
+func (r *repository) branches() (string, []string, error) {
+	names := map[string]bool{}
+	heads := filepath.Join(r.commonDir, "refs", "heads")
+	err := filepath.WalkDir(heads, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(heads, path)
+		if err == nil {
+			names[filepath.ToSlash(rel)] = true
+		}
+		return err
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return "", nil, err
+	}
+
+	// #nosec G304 -- refs of the repository being read
+	if f, err := os.Open(filepath.Join(r.commonDir, "packed-refs")); err == nil {
+		defer func() { _ = f.Close() }()
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			_, ref, _ := strings.Cut(scanner.Text(), " ")
+			if name, ok := strings.CutPrefix(ref, "refs/heads/"); ok {
+				names[name] = true
+			}
+		}
+	}
+
+	local := make([]string, 0, len(names))
+	for name := range names {
+		local = append(local, name)
+	}
+	sort.Strings(local)
+
+	// #nosec G304 -- HEAD of the repository being read
+	head, err := os.ReadFile(filepath.Join(r.gitDir, "HEAD"))
+	if err != nil {
+		return "", nil, err
+	}
+	ref, ok := strings.CutPrefix(strings.TrimSpace(string(head)), "ref:")
+	if !ok {
+		return "", local, nil // Detached
+	}
+	current, _ := strings.CutPrefix(strings.TrimSpace(ref), "refs/heads/")
+	return current, local, nil
+}
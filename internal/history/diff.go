@@ -0,0 +1,128 @@
+package history
+
+import (
+	"sort"
+
+	"github.com/anIcedAntFA/gohome/internal/entity"
+)
+
+// Count is a number of commits in the old and the new report.
+type Count struct {
+	Name     string
+	Old, New int
+}
+
+// Change is a commit found in only one of the reports.
+type Change struct {
+	Repo   string
+	Commit entity.Commit
+}
+
+// Diff compares two reports.
+type Diff struct {
+	Types []Count // Commits per type
+	Repos []Count // Commits per repository
+	Total Count   // All commits
+	Tasks Count
+
+	// Commits and tasks found in only one of the reports
+	Added, Removed           []Change
+	AddedTasks, RemovedTasks []entity.Task
+}
+
+// Compare returns the differences between an old and a new report.
+// Commits are matched by hash, tasks by message.
+func Compare(before, after *entity.Report) Diff {
+	d := Diff{
+		Total: Count{Name: "total", Old: before.CommitCount(), New: after.CommitCount()},
+		Tasks: Count{Name: "tasks", Old: len(before.Tasks), New: len(after.Tasks)},
+	}
+
+	types, repos := make(map[string]*Count), make(map[string]*Count)
+	count := func(counts map[string]*Count, name string) *Count {
+		if counts[name] == nil {
+			counts[name] = &Count{Name: name}
+		}
+		return counts[name]
+	}
+	for _, repo := range before.Repos {
+		count(repos, repo.Name).Old += len(repo.Commits)
+		for _, c := range repo.Commits {
+			count(types, c.Type).Old++
+		}
+	}
+	for _, repo := range after.Repos {
+		count(repos, repo.Name).New += len(repo.Commits)
+		for _, c := range repo.Commits {
+			count(types, c.Type).New++
+		}
+	}
+	d.Types, d.Repos = sortedCounts(types), sortedCounts(repos)
+
+	d.Added, d.Removed = changes(after, before), changes(before, after)
+	d.AddedTasks, d.RemovedTasks = taskChanges(after, before), taskChanges(before, after)
+	return d
+}
+
+// sortedCounts returns the counts, the largest in the new report first.
+func sortedCounts(counts map[string]*Count) []Count {
+	list := make([]Count, 0, len(counts))
+	for _, c := range counts {
+		list = append(list, *c)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].New != list[j].New {
+			return list[i].New > list[j].New
+		}
+		if list[i].Old != list[j].Old {
+			return list[i].Old > list[j].Old
+		}
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// commitKey identifies a commit across reports. Reports saved without
+// hashes fall back to the subject.
+func commitKey(repo string, c entity.Commit) string {
+	if c.Hash != "" {
+		return c.Hash
+	}
+	return repo + "\x00" + c.Raw
+}
+
+// changes returns the commits of a that are not in b.
+func changes(a, b *entity.Report) []Change {
+	inB := make(map[string]bool)
+	for _, repo := range b.Repos {
+		for _, c := range repo.Commits {
+			inB[commitKey(repo.Name, c)] = true
+		}
+	}
+
+	var list []Change
+	for _, repo := range a.Repos {
+		for _, c := range repo.Commits {
+			if !inB[commitKey(repo.Name, c)] {
+				list = append(list, Change{Repo: repo.Name, Commit: c})
+			}
+		}
+	}
+	return list
+}
+
+// taskChanges returns the tasks of a that are not in b.
+func taskChanges(a, b *entity.Report) []entity.Task {
+	inB := make(map[string]bool)
+	for _, t := range b.Tasks {
+		inB[t.Message] = true
+	}
+
+	var list []entity.Task
+	for _, t := range a.Tasks {
+		if !inB[t.Message] {
+			list = append(list, t)
+		}
+	}
+	return list
+}
@@ -0,0 +1,181 @@
+// Package history keeps the generated reports, so they can be shown again
+// and compared without scanning the repositories.
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anIcedAntFA/gohome/internal/entity"
+)
+
+// DirName is the name of the history in the state directory.
+const DirName = "history"
+
+// DefaultLimit is the number of reports kept per profile and author when
+// the config does not set history_limit.
+const DefaultLimit = 100
+
+// idLayout formats the generation time of a report into its ID.
+const idLayout = "20060102-150405"
+
+// Record is a saved report, one JSON file per report.
+type Record struct {
+	ID      string        `json:"id"`
+	Profile string        `json:"profile,omitempty"`
+	From    time.Time     `json:"from"` // Start of the report period
+	Report  entity.Report `json:"report"`
+}
+
+// Save writes a record to the history directory and sets its ID, made of
+// the generation time of the report. Records are never overwritten, so
+// concurrent runs each keep their report.
+func Save(dir string, rec *Record) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, ".record-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	base := rec.Report.GeneratedAt.Format(idLayout)
+	for n := 1; ; n++ {
+		rec.ID = base
+		if n > 1 {
+			rec.ID = fmt.Sprintf("%s-%d", base, n)
+		}
+		if n > 100 {
+			_ = tmp.Close()
+			return fmt.Errorf("too many reports saved at %s", base)
+		}
+
+		// The ID is part of the content, so the file is written for each try
+		if err := writeRecord(tmp, rec); err != nil {
+			_ = tmp.Close()
+			return err
+		}
+		err := os.Link(tmp.Name(), filepath.Join(dir, rec.ID+".json"))
+		if errors.Is(err, os.ErrExist) {
+			continue
+		}
+		_ = tmp.Close()
+		return err
+	}
+}
+
+// writeRecord replaces the content of f with the record.
+func writeRecord(f *os.File, rec *Record) error {
+	data, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := f.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := f.WriteAt(append(data, '\n'), 0); err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+// Prune deletes the records for which match is true, oldest first, until
+// limit of them are left. Other records are left alone.
+func Prune(dir string, limit int, match func(Record) bool) error {
+	records, err := List(dir)
+	if err != nil {
+		return err
+	}
+
+	var matching []Record
+	for _, rec := range records {
+		if match(rec) {
+			matching = append(matching, rec)
+		}
+	}
+	for len(matching) > limit {
+		if err := os.Remove(filepath.Join(dir, matching[0].ID+".json")); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		matching = matching[1:]
+	}
+	return nil
+}
+
+// List returns the saved records, oldest first. A missing history has no
+// records.
+func List(dir string) ([]Record, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]Record, 0, len(paths))
+	for _, path := range paths {
+		// #nosec G304 -- the history lives in the user's state directory
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var rec Record
+		if err := json.Unmarshal(data, &rec); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		records = append(records, rec)
+	}
+
+	// Reports of the same second are in the order of their ID suffix
+	sort.Slice(records, func(i, j int) bool {
+		a, b := records[i], records[j]
+		if !a.Report.GeneratedAt.Equal(b.Report.GeneratedAt) {
+			return a.Report.GeneratedAt.Before(b.Report.GeneratedAt)
+		}
+		if len(a.ID) != len(b.ID) {
+			return len(a.ID) < len(b.ID)
+		}
+		return a.ID < b.ID
+	})
+	return records, nil
+}
+
+// Find returns the record a reference points to: a number counting back
+// from the latest record (1 is the latest), an ID, or the beginning of a
+// single ID, e.g. a date "20260109".
+func Find(records []Record, ref string) (Record, error) {
+	if n, err := strconv.Atoi(ref); err == nil && len(ref) < len("20060102") {
+		if n < 1 || n > len(records) {
+			return Record{}, fmt.Errorf("no report #%d, the history has %d", n, len(records))
+		}
+		return records[len(records)-n], nil
+	}
+
+	var matches []Record
+	for _, rec := range records {
+		if rec.ID == ref {
+			return rec, nil
+		}
+		if strings.HasPrefix(rec.ID, ref) {
+			matches = append(matches, rec)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return Record{}, fmt.Errorf("no report %q in the history", ref)
+	case 1:
+		return matches[0], nil
+	}
+	ids := make([]string, 0, len(matches))
+	for _, rec := range matches {
+		ids = append(ids, rec.ID)
+	}
+	return Record{}, fmt.Errorf("%q matches several reports: %s", ref, strings.Join(ids, ", "))
+}
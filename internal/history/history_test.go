@@ -0,0 +1,158 @@
+package history
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anIcedAntFA/gohome/internal/entity"
+)
+
+func report(at string, repos ...entity.RepoReport) entity.Report {
+	t, err := time.ParseInLocation("2006-01-02 15:04", at, time.Local)
+	if err != nil {
+		panic(err)
+	}
+	return entity.Report{Author: "tester", Period: "1 week ago", GeneratedAt: t, Repos: repos}
+}
+
+func TestSaveAndList(t *testing.T) {
+	dir := t.TempDir()
+	for _, r := range []entity.Report{report("2026-10-16 17:00"), report("2026-10-09 17:00"), report("2026-10-16 17:00")} {
+		rec := Record{Report: r}
+		if err := Save(dir, &rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	records, err := List(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, rec := range records {
+		ids = append(ids, rec.ID)
+	}
+	// Reports generated in the same second get their own ID
+	want := "20261009-170000 20261016-170000 20261016-170000-2"
+	if got := strings.Join(ids, " "); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	dir := t.TempDir()
+	save := func(at, profile, author string) {
+		t.Helper()
+		r := report(at)
+		r.Author = author
+		rec := Record{Profile: profile, Report: r}
+		if err := Save(dir, &rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	save("2026-10-01 17:00", "", "tester")
+	save("2026-10-02 17:00", "", "tester")
+	save("2026-10-02 17:00", "", "tester")
+	save("2026-10-03 17:00", "", "tester")
+	save("2026-10-01 09:00", "", "other")
+	save("2026-10-01 10:00", "work", "tester")
+
+	mine := func(r Record) bool { return r.Profile == "" && r.Report.Author == "tester" }
+	if err := Prune(dir, 2, mine); err != nil {
+		t.Fatal(err)
+	}
+	records, err := List(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, rec := range records {
+		ids = append(ids, rec.ID)
+	}
+	want := "20261001-090000 20261001-100000 20261002-170000-2 20261003-170000"
+	if got := strings.Join(ids, " "); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	if err := Prune(t.TempDir()+"/history", 1, mine); err != nil {
+		t.Errorf("missing history: %v", err)
+	}
+}
+
+func TestListMissingHistory(t *testing.T) {
+	records, err := List(t.TempDir() + "/history")
+	if err != nil || len(records) != 0 {
+		t.Errorf("got %v, %v, want no records", records, err)
+	}
+}
+
+func TestFind(t *testing.T) {
+	records := []Record{{ID: "20261002-170000"}, {ID: "20261009-090000"}, {ID: "20261009-170000"}}
+
+	tests := []struct {
+		ref, want string
+	}{
+		{"1", "20261009-170000"},
+		{"3", "20261002-170000"},
+		{"20261002", "20261002-170000"},
+		{"20261009-09", "20261009-090000"},
+		{"20261009-170000", "20261009-170000"},
+	}
+	for _, tt := range tests {
+		rec, err := Find(records, tt.ref)
+		if err != nil || rec.ID != tt.want {
+			t.Errorf("Find(%q) = %q, %v, want %q", tt.ref, rec.ID, err, tt.want)
+		}
+	}
+
+	for _, ref := range []string{"0", "4", "20261009", "20251231"} {
+		if _, err := Find(records, ref); err == nil {
+			t.Errorf("Find(%q): expected an error", ref)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	old := report("2026-10-09 17:00",
+		entity.RepoReport{Name: "api", Commits: []entity.Commit{
+			{Hash: "a1", Type: "feat", Raw: "feat: add login"},
+			{Hash: "a2", Type: "fix", Raw: "fix: crash"},
+		}})
+	old.Tasks = []entity.Task{{Type: "meeting", Message: "Planning"}}
+	after := report("2026-10-16 17:00",
+		entity.RepoReport{Name: "api", Commits: []entity.Commit{
+			{Hash: "a2", Type: "fix", Raw: "fix: crash"},
+			{Hash: "a3", Type: "feat", Raw: "feat: add logout"},
+		}},
+		entity.RepoReport{Name: "web", Commits: []entity.Commit{
+			{Hash: "w1", Type: "feat", Raw: "feat: dark mode"},
+		}})
+
+	d := Compare(&old, &after)
+
+	if d.Total != (Count{Name: "total", Old: 2, New: 3}) {
+		t.Errorf("total = %+v", d.Total)
+	}
+	if d.Tasks != (Count{Name: "tasks", Old: 1, New: 0}) {
+		t.Errorf("tasks = %+v", d.Tasks)
+	}
+	wantTypes := []Count{{"feat", 1, 2}, {"fix", 1, 1}}
+	if len(d.Types) != 2 || d.Types[0] != wantTypes[0] || d.Types[1] != wantTypes[1] {
+		t.Errorf("types = %+v, want %+v", d.Types, wantTypes)
+	}
+	wantRepos := []Count{{"api", 2, 2}, {"web", 0, 1}}
+	if len(d.Repos) != 2 || d.Repos[0] != wantRepos[0] || d.Repos[1] != wantRepos[1] {
+		t.Errorf("repos = %+v, want %+v", d.Repos, wantRepos)
+	}
+
+	if len(d.Added) != 2 || d.Added[0].Commit.Hash != "a3" || d.Added[1].Repo != "web" {
+		t.Errorf("added = %+v", d.Added)
+	}
+	if len(d.Removed) != 1 || d.Removed[0].Commit.Hash != "a1" {
+		t.Errorf("removed = %+v", d.Removed)
+	}
+	if len(d.RemovedTasks) != 1 || len(d.AddedTasks) != 0 {
+		t.Errorf("tasks added %+v, removed %+v", d.AddedTasks, d.RemovedTasks)
+	}
+}
@@ -0,0 +1,94 @@
+// Package journal stores the dated notes added with 'gohome log', the work
+// that leaves no trace in git.
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/anIcedAntFA/gohome/internal/entity"
+)
+
+// FileName is the name of the journal in the state directory.
+const FileName = "journal.jsonl"
+
+// Entry is a dated note, one JSON object per line of the journal. The
+// journal is shared by the profiles, each note belongs to the profile it was
+// written with.
+type Entry struct {
+	Time    time.Time `json:"time"`
+	Profile string    `json:"profile,omitempty"`
+	Type    string    `json:"type"`
+	Message string    `json:"message"`
+	Icon    string    `json:"icon,omitempty"`
+}
+
+// Task returns the entry as a report task.
+func (e Entry) Task() entity.Task {
+	return entity.Task{Type: e.Type, Message: e.Message, Icon: e.Icon, Enabled: true}
+}
+
+// Append adds an entry at the end of the journal, creating the file and
+// its directory as needed.
+func Append(path string, e Entry) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	// #nosec G304 -- the journal lives in the user's state directory
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Read returns the entries of a profile dated between from and to, oldest
+// first. A missing journal has no entries.
+func Read(path, profile string, from, to time.Time) ([]Entry, error) {
+	// #nosec G304 -- the journal lives in the user's state directory
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		if e.Profile == profile && !e.Time.Before(from) && !e.Time.After(to) {
+			entries = append(entries, e)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// Entries added with --at can be out of order
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Time.Before(entries[j].Time) })
+	return entries, nil
+}
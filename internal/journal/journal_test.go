@@ -0,0 +1,81 @@
+package journal
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var day = time.Date(2026, time.January, 9, 0, 0, 0, 0, time.UTC)
+
+func TestAppendRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", FileName)
+	entries := []Entry{
+		{Time: day.Add(16 * time.Hour), Type: "review", Message: "Reviewed the API design", Icon: "👀"},
+		{Time: day.Add(9 * time.Hour), Type: "meeting", Message: "Standup"}, // Added with --at, out of order
+		{Time: day.Add(10 * time.Hour), Profile: "work", Type: "misc", Message: "Work note"},
+		{Time: day.Add(-time.Second), Type: "misc", Message: "The day before"},
+		{Time: day.Add(24 * time.Hour), Type: "misc", Message: "The next day"},
+	}
+	for _, e := range entries {
+		if err := Append(path, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		profile  string
+		from, to time.Time
+		want     []string
+	}{
+		{"day", "", day, day.Add(24*time.Hour - time.Second), []string{"Standup", "Reviewed the API design"}},
+		{"bounds included", "", day.Add(-time.Second), day.Add(24 * time.Hour), []string{"The day before", "Standup", "Reviewed the API design", "The next day"}},
+		{"start of a note", "", day.Add(16 * time.Hour), day.Add(16 * time.Hour), []string{"Reviewed the API design"}},
+		{"empty period", "", day.Add(11 * time.Hour), day.Add(12 * time.Hour), nil},
+		{"profile", "work", day, day.Add(24 * time.Hour), []string{"Work note"}},
+		{"unknown profile", "home", day, day.Add(24 * time.Hour), nil},
+	}
+	for _, tt := range tests {
+		got, err := Read(path, tt.profile, tt.from, tt.to)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var messages []string
+		for _, e := range got {
+			messages = append(messages, e.Message)
+		}
+		if !reflect.DeepEqual(messages, tt.want) {
+			t.Errorf("%s: got %q, want %q", tt.name, messages, tt.want)
+		}
+	}
+
+	// Entries come back as they were written
+	got, err := Read(path, "", day.Add(16*time.Hour), day.Add(16*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0] != entries[0] {
+		t.Errorf("got %+v, want %+v", got, entries[0])
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	dir := t.TempDir()
+	if got, err := Read(filepath.Join(dir, FileName), "", day, day.Add(time.Hour)); got != nil || err != nil {
+		t.Errorf("missing journal: got %v, %v; want no entries", got, err)
+	}
+
+	path := filepath.Join(dir, "broken.jsonl")
+	content := `{"time": "2026-01-09T09:00:00Z", "type": "misc", "message": "ok"}` + "\n\n{broken\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	_, err := Read(path, "", day, day.Add(time.Hour))
+	if err == nil || !strings.HasPrefix(err.Error(), path+":3: ") {
+		t.Errorf("err = %v, want the line of the broken entry", err)
+	}
+}
@@ -0,0 +1,276 @@
+// Package mail sends reports by email over SMTP as multipart plain-text and HTML messages.
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Connection security modes.
+const (
+	SecurityStartTLS = "starttls" // Plain connection upgraded with STARTTLS (port 587)
+	SecurityTLS      = "tls"      // Implicit TLS (port 465)
+	SecurityNone     = "none"     // Unencrypted, only for local relays
+)
+
+// DefaultSubject is used when no subject template is configured.
+const DefaultSubject = "Work report · {{.Author}} · since {{.Period}}"
+
+// Server holds SMTP connection settings.
+type Server struct {
+	Host     string
+	Port     int
+	Security string
+	Username string
+	Password string
+	Timeout  time.Duration
+
+	// TLSConfig overrides the TLS settings, mainly for tests
+	TLSConfig *tls.Config
+}
+
+// Message is an email with alternative plain-text and HTML bodies.
+type Message struct {
+	From    string
+	To      []string
+	Subject string
+	Text    string
+	HTML    string
+	Date    time.Time
+}
+
+// SubjectData is the data available to subject templates.
+type SubjectData struct {
+	Author string
+	Period string
+	Date   string
+}
+
+// Subject renders a subject template such as "Report {{.Date}} ({{.Period}})".
+func Subject(tmpl string, data SubjectData) (string, error) {
+	if tmpl == "" {
+		tmpl = DefaultSubject
+	}
+
+	t, err := template.New("subject").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("invalid subject template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("invalid subject template: %w", err)
+	}
+
+	// Header values must stay on one line
+	return strings.Join(strings.Fields(buf.String()), " "), nil
+}
+
+// Validate checks that the message can be sent.
+func (m *Message) Validate() error {
+	if m.From == "" {
+		return errors.New("sender address (from) is required")
+	}
+	if len(m.To) == 0 {
+		return errors.New("at least one recipient (to) is required")
+	}
+	for _, addr := range append([]string{m.From}, m.To...) {
+		if strings.ContainsAny(addr, "\r\n") {
+			return fmt.Errorf("invalid address %q", addr)
+		}
+	}
+	return nil
+}
+
+// Bytes encodes the message as a MIME multipart/alternative document.
+func (m *Message) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+
+	date := m.Date
+	if date.IsZero() {
+		date = time.Now()
+	}
+
+	body := multipart.NewWriter(&buf)
+
+	headers := [][2]string{
+		{"From", m.From},
+		{"To", strings.Join(m.To, ", ")},
+		{"Subject", mime.QEncoding.Encode("utf-8", m.Subject)},
+		{"Date", date.Format(time.RFC1123Z)},
+		{"Message-ID", messageID(m.From)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", "multipart/alternative; boundary=" + body.Boundary()},
+	}
+	for _, h := range headers {
+		fmt.Fprintf(&buf, "%s: %s\r\n", h[0], h[1])
+	}
+	buf.WriteString("\r\n")
+
+	// Plain text first: clients pick the last alternative they support
+	if err := writePart(body, "text/plain; charset=utf-8", m.Text); err != nil {
+		return nil, err
+	}
+	if m.HTML != "" {
+		if err := writePart(body, "text/html; charset=utf-8", m.HTML); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := body.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writePart adds a quoted-printable encoded part.
+func writePart(w *multipart.Writer, contentType, content string) error {
+	part, err := w.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return err
+	}
+
+	qp := quotedprintable.NewWriter(part)
+	if _, err := qp.Write([]byte(content)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+// messageID generates a unique Message-ID in the sender's domain.
+func messageID(from string) string {
+	domain := "gohome.local"
+	if at := strings.LastIndex(from, "@"); at >= 0 {
+		domain = strings.Trim(from[at+1:], "> ")
+	}
+
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+	return "<" + hex.EncodeToString(b) + "@" + domain + ">"
+}
+
+// Send delivers the message through the SMTP server.
+func Send(ctx context.Context, srv Server, msg *Message) error {
+	if err := msg.Validate(); err != nil {
+		return err
+	}
+	data, err := msg.Bytes()
+	if err != nil {
+		return err
+	}
+
+	client, err := dial(ctx, srv)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = client.Close()
+	}()
+
+	if srv.Username != "" {
+		auth := smtp.PlainAuth("", srv.Username, srv.Password, srv.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+	}
+
+	if err := client.Mail(envelopeAddress(msg.From)); err != nil {
+		return err
+	}
+	for _, to := range msg.To {
+		if err := client.Rcpt(envelopeAddress(to)); err != nil {
+			return fmt.Errorf("recipient %s rejected: %w", to, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+// dial connects to the server and negotiates the configured security.
+func dial(ctx context.Context, srv Server) (*smtp.Client, error) {
+	timeout := srv.Timeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+	addr := net.JoinHostPort(srv.Host, strconv.Itoa(srv.Port))
+
+	tlsConfig := srv.TLSConfig
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{ServerName: srv.Host, MinVersion: tls.VersionTLS12}
+	}
+
+	dialer := &net.Dialer{Timeout: timeout}
+	var conn net.Conn
+	var err error
+
+	switch srv.Security {
+	case SecurityTLS:
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	case SecurityStartTLS, SecurityNone:
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	default:
+		return nil, fmt.Errorf("unknown security mode %q (use starttls, tls or none)", srv.Security)
+	}
+	if err != nil {
+		return nil, err
+	}
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+
+	client, err := smtp.NewClient(conn, srv.Host)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	if srv.Security == SecurityStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			_ = client.Close()
+			return nil, errors.New("server does not support STARTTLS")
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			_ = client.Close()
+			return nil, err
+		}
+	}
+
+	return client, nil
+}
+
+// envelopeAddress extracts the bare address from "Name <addr>".
+func envelopeAddress(addr string) string {
+	if start := strings.LastIndex(addr, "<"); start >= 0 {
+		if end := strings.LastIndex(addr, ">"); end > start {
+			return addr[start+1 : end]
+		}
+	}
+	return strings.TrimSpace(addr)
+}
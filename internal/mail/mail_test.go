@@ -0,0 +1,199 @@
+package mail
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sink is a minimal SMTP server recording the last delivered message.
+type sink struct {
+	ln       net.Listener
+	messages chan received
+}
+
+type received struct {
+	from string
+	to   []string
+	auth string
+	data string
+}
+
+func newSink(t *testing.T) *sink {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &sink{ln: ln, messages: make(chan received, 1)}
+	go s.serve()
+	t.Cleanup(func() { _ = ln.Close() })
+	return s
+}
+
+func (s *sink) port() int {
+	return s.ln.Addr().(*net.TCPAddr).Port
+}
+
+func (s *sink) serve() {
+	conn, err := s.ln.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = io.WriteString(conn, line+"\r\n") }
+	var msg received
+
+	reply("220 sink ready")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.TrimSpace(line)
+		upper := strings.ToUpper(cmd)
+
+		switch {
+		case strings.HasPrefix(upper, "EHLO"):
+			reply("250-sink")
+			reply("250 AUTH PLAIN")
+		case strings.HasPrefix(upper, "AUTH PLAIN"):
+			msg.auth = strings.TrimSpace(cmd[len("AUTH PLAIN"):])
+			reply("235 ok")
+		case strings.HasPrefix(upper, "MAIL FROM:"):
+			msg.from = strings.Trim(cmd[len("MAIL FROM:"):], "<> ")
+			reply("250 ok")
+		case strings.HasPrefix(upper, "RCPT TO:"):
+			msg.to = append(msg.to, strings.Trim(cmd[len("RCPT TO:"):], "<> "))
+			reply("250 ok")
+		case upper == "DATA":
+			reply("354 go ahead")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(l)
+			}
+			msg.data = data.String()
+			reply("250 queued")
+		case upper == "QUIT":
+			reply("221 bye")
+			s.messages <- msg
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+func TestSendMultipartMessage(t *testing.T) {
+	srv := newSink(t)
+
+	subject, err := Subject(DefaultSubject, SubjectData{Author: "Khoi", Period: "1 week ago"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := &Message{
+		From:    "gohome <bot@example.com>",
+		To:      []string{"boss@example.com", "team@example.com"},
+		Subject: subject,
+		Text:    "- feat: add login",
+		HTML:    "<ul><li>feat: add login</li></ul>",
+	}
+	server := Server{
+		Host:     "127.0.0.1",
+		Port:     srv.port(),
+		Security: SecurityNone,
+		Username: "bot",
+		Password: "secret",
+		Timeout:  5 * time.Second,
+	}
+
+	if err := Send(context.Background(), server, msg); err != nil {
+		t.Fatalf("send failed: %v", err)
+	}
+
+	var got received
+	select {
+	case got = <-srv.messages:
+	case <-time.After(5 * time.Second):
+		t.Fatal("sink received nothing")
+	}
+
+	if got.from != "bot@example.com" {
+		t.Errorf("unexpected envelope sender %q", got.from)
+	}
+	if strings.Join(got.to, ",") != "boss@example.com,team@example.com" {
+		t.Errorf("unexpected recipients %v", got.to)
+	}
+	if got.auth == "" {
+		t.Error("expected AUTH PLAIN credentials")
+	}
+
+	parsed, err := mail.ReadMessage(strings.NewReader(got.data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, _ := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	if decoded != "Work report · Khoi · since 1 week ago" {
+		t.Errorf("unexpected subject %q", decoded)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("unexpected content type %q", parsed.Header.Get("Content-Type"))
+	}
+
+	reader := multipart.NewReader(parsed.Body, params["boundary"])
+	var types []string
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		types = append(types, strings.Split(part.Header.Get("Content-Type"), ";")[0])
+	}
+	if strings.Join(types, ",") != "text/plain,text/html" {
+		t.Errorf("unexpected parts %v", types)
+	}
+}
+
+func TestSubjectTemplate(t *testing.T) {
+	got, err := Subject("Report {{.Date}}\n({{.Period}})", SubjectData{Period: "1 day ago", Date: "2026-01-10"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "Report 2026-01-10 (1 day ago)" {
+		t.Errorf("unexpected subject %q", got)
+	}
+
+	if _, err := Subject("{{.Unknown}}", SubjectData{}); err == nil {
+		t.Error("expected error for unknown field")
+	}
+}
+
+func TestSendRejectsInvalidMessage(t *testing.T) {
+	err := Send(context.Background(), Server{Host: "127.0.0.1", Port: 1}, &Message{From: "a@b.c"})
+	if err == nil || !strings.Contains(err.Error(), "recipient") {
+		t.Fatalf("expected recipient error, got %v", err)
+	}
+}
@@ -2,23 +2,101 @@
 package parser
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"regexp"
+	"slices"
+	"sort"
 	"strings"
 
 	"github.com/anIcedAntFA/gohome/internal/entity"
 )
 
-// Regex to parse Conventional Commits.
-var commitRegex = regexp.MustCompile(`(?i)^.*?([a-zA-Z0-9_-]+)(?:\(([^)]+)\))?:\s*(.+)$`)
+// version changes whenever Parse gives different results for the same
+// subject, so commits parsed by older versions are parsed again.
+const version = 1
+
+// Regex to parse Conventional Commits, including the "!" breaking change marker.
+var commitRegex = regexp.MustCompile(`(?i)^.*?([a-zA-Z0-9_-]+)(?:\(([^)]+)\))?(!)?:\s*(.+)$`)
+
+// Rules customize the parsing of a repository's commits.
+type Rules struct {
+	// Patterns are tried before Conventional Commits. Their named groups
+	// "type", "scope", "message" and "breaking" (any non-empty match) fill
+	// the commit.
+	Patterns []*regexp.Regexp
+	// Ignore lists the subjects of commits to leave out of reports
+	Ignore []*regexp.Regexp
+
+	// TypeAliases and ScopeAliases rename types and scopes after parsing
+	TypeAliases  map[string]string
+	ScopeAliases map[string]string
+
+	// Tickets finds ticket IDs in subjects
+	Tickets *regexp.Regexp
+}
+
+// Fingerprint identifies the rules and the parser version: commits parsed
+// with the same fingerprint are parsed the same way.
+func (r Rules) Fingerprint() string {
+	h := sha256.New()
+	fmt.Fprintf(h, "v%d\n", version)
+	for _, re := range r.Patterns {
+		fmt.Fprintf(h, "pattern %q\n", re.String())
+	}
+	for _, re := range r.Ignore {
+		fmt.Fprintf(h, "ignore %q\n", re.String())
+	}
+	for _, aliases := range []map[string]string{r.TypeAliases, r.ScopeAliases} {
+		keys := make([]string, 0, len(aliases))
+		for k := range aliases {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(h, "alias %q %q\n", k, aliases[k])
+		}
+		fmt.Fprintln(h, "--")
+	}
+	if r.Tickets != nil {
+		fmt.Fprintf(h, "tickets %q\n", r.Tickets.String())
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
 
 // Service handles parsing logic.
-type Service struct{}
+type Service struct {
+	rules Rules
+}
 
 // NewService creates a new parser service instance.
 func NewService() *Service {
 	return &Service{}
 }
 
+// WithRules returns a copy of the service using the given rules.
+func (s *Service) WithRules(rules Rules) *Service {
+	c := *s
+	c.rules = rules
+	return &c
+}
+
+// Fingerprint identifies the rules of the service, see Rules.Fingerprint.
+func (s *Service) Fingerprint() string {
+	return s.rules.Fingerprint()
+}
+
+// Ignored reports whether a commit subject matches one of the Ignore rules.
+func (s *Service) Ignored(rawLine string) bool {
+	for _, re := range s.rules.Ignore {
+		if re.MatchString(rawLine) {
+			return true
+		}
+	}
+	return false
+}
+
 // Parse converts a raw log line into a Commit entity.
 func (s *Service) Parse(rawLine string) entity.Commit {
 	emoji := s.extractEmoji(rawLine)
@@ -32,11 +110,15 @@ func (s *Service) Parse(rawLine string) entity.Commit {
 		Icon: emoji,
 	}
 
-	if len(matches) == 4 {
+	switch {
+	case s.parseCustom(rawLine, &commit):
+		// Filled by a pattern of the rules
+	case len(matches) == 5:
 		commit.Type = matches[1]
 		commit.Scope = matches[2]
-		commit.Message = matches[3]
-	} else {
+		commit.Breaking = matches[3] == "!"
+		commit.Message = matches[4]
+	default:
 		commit.Type = "misc"
 		commit.Scope = "-"
 		commit.Message = rawLine
@@ -46,9 +128,59 @@ func (s *Service) Parse(rawLine string) entity.Commit {
 		commit.Scope = "-"
 	}
 
+	s.applyRules(&commit)
 	return commit
 }
 
+// parseCustom fills the commit from the first matching pattern of the rules.
+func (s *Service) parseCustom(rawLine string, commit *entity.Commit) bool {
+	for _, re := range s.rules.Patterns {
+		matches := re.FindStringSubmatch(rawLine)
+		if matches == nil {
+			continue
+		}
+
+		commit.Type, commit.Scope, commit.Message = "misc", "-", rawLine
+		for i, name := range re.SubexpNames() {
+			value := strings.TrimSpace(matches[i])
+			if value == "" {
+				continue
+			}
+			switch name {
+			case "type":
+				commit.Type = value
+			case "scope":
+				commit.Scope = value
+			case "message":
+				commit.Message = value
+			case "breaking":
+				commit.Breaking = true
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// applyRules renames the type and scope and extracts the ticket IDs.
+func (s *Service) applyRules(commit *entity.Commit) {
+	if alias, ok := s.rules.TypeAliases[commit.Type]; ok {
+		commit.Type = alias
+	}
+	if alias, ok := s.rules.ScopeAliases[commit.Scope]; ok {
+		commit.Scope = alias
+	}
+
+	if s.rules.Tickets == nil {
+		return
+	}
+	for _, id := range s.rules.Tickets.FindAllString(commit.Raw, -1) {
+		if !slices.Contains(commit.Tickets, id) {
+			commit.Tickets = append(commit.Tickets, id)
+		}
+	}
+}
+
 func (s *Service) extractEmoji(input string) string {
 	var emoji strings.Builder
 
@@ -0,0 +1,153 @@
+package parser
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+// rules returns rules using every setting.
+func rules() Rules {
+	return Rules{
+		Patterns:     []*regexp.Regexp{regexp.MustCompile(`^(?P<type>\w+): (?P<message>.+)$`)},
+		Ignore:       []*regexp.Regexp{regexp.MustCompile(`^wip`)},
+		TypeAliases:  map[string]string{"feature": "feat", "bugfix": "fix", "docs": "doc"},
+		ScopeAliases: map[string]string{"authn": "auth"},
+		Tickets:      regexp.MustCompile(`[A-Z]+-[0-9]+`),
+	}
+}
+
+func TestFingerprintChanges(t *testing.T) {
+	base := rules().Fingerprint()
+	if got := rules().Fingerprint(); got != base {
+		t.Fatalf("same rules, fingerprints %s and %s", got, base)
+	}
+
+	tests := []struct {
+		name   string
+		change func(r *Rules)
+	}{
+		{"pattern", func(r *Rules) { r.Patterns = []*regexp.Regexp{regexp.MustCompile(`^(?P<message>.+)$`)} }},
+		{"extra pattern", func(r *Rules) { r.Patterns = append(r.Patterns, regexp.MustCompile(`^x`)) }},
+		{"no pattern", func(r *Rules) { r.Patterns = nil }},
+		{"ignore", func(r *Rules) { r.Ignore = []*regexp.Regexp{regexp.MustCompile(`^tmp`)} }},
+		{"pattern moved to ignore", func(r *Rules) { r.Patterns, r.Ignore = r.Ignore, r.Patterns }},
+		{"type alias target", func(r *Rules) { r.TypeAliases["feature"] = "feat!" }},
+		{"type alias removed", func(r *Rules) { delete(r.TypeAliases, "docs") }},
+		{"scope alias", func(r *Rules) { r.ScopeAliases["authz"] = "auth" }},
+		{"alias moved to scopes", func(r *Rules) {
+			r.TypeAliases, r.ScopeAliases = map[string]string{"feature": "feat", "bugfix": "fix", "docs": "doc", "authn": "auth"}, nil
+		}},
+		{"ticket pattern", func(r *Rules) { r.Tickets = regexp.MustCompile(`#[0-9]+`) }},
+		{"no ticket pattern", func(r *Rules) { r.Tickets = nil }},
+	}
+	seen := map[string]string{base: "base"}
+	for _, tt := range tests {
+		r := rules()
+		tt.change(&r)
+		got := r.Fingerprint()
+		if other, ok := seen[got]; ok {
+			t.Errorf("%s: fingerprint %s, same as %s", tt.name, got, other)
+		}
+		seen[got] = tt.name
+	}
+}
+
+func TestFingerprintMapOrder(t *testing.T) {
+	want := rules().Fingerprint()
+	for i := 0; i < 20; i++ {
+		// Maps built in another order iterate in another order
+		r := rules()
+		r.TypeAliases = map[string]string{}
+		for _, k := range []string{"docs", "bugfix", "feature"} {
+			r.TypeAliases[k] = rules().TypeAliases[k]
+		}
+		if got := r.Fingerprint(); got != want {
+			t.Fatalf("fingerprint %s, want %s", got, want)
+		}
+	}
+
+	// Empty and nil aliases parse the same
+	a, b := rules(), rules()
+	a.ScopeAliases, b.ScopeAliases = nil, map[string]string{}
+	if a.Fingerprint() != b.Fingerprint() {
+		t.Error("nil and empty aliases have different fingerprints")
+	}
+	if NewService().WithRules(rules()).Fingerprint() != want {
+		t.Error("the service fingerprint differs from its rules'")
+	}
+}
+
+func TestParse(t *testing.T) {
+	custom := Rules{
+		Patterns: []*regexp.Regexp{
+			regexp.MustCompile(`^\[(?P<type>\w+)(?:/(?P<scope>[\w-]+))?\](?P<breaking>!)? (?P<message>.+)$`),
+			regexp.MustCompile(`^(?P<type>\w+) - (?P<message>.*)$`),
+			regexp.MustCompile(`^\[(?P<message>.+)\]$`),
+		},
+		TypeAliases:  map[string]string{"feature": "feat", "bugfix": "fix", "misc": "chore"},
+		ScopeAliases: map[string]string{"authn": "auth", "-": "general"},
+		Tickets:      regexp.MustCompile(`[A-Z]+-[0-9]+`),
+	}
+
+	tests := []struct {
+		name  string
+		rules Rules
+		line  string
+		want  [4]string // Type, scope, message, breaking
+		tkts  []string
+	}{
+		{"conventional", Rules{}, "feat(auth): add login", [4]string{"feat", "auth", "add login", ""}, nil},
+		{"conventional breaking", Rules{}, "feat!: drop v1", [4]string{"feat", "-", "drop v1", "!"}, nil},
+		{"not conventional", Rules{}, "Merge branch main", [4]string{"misc", "-", "Merge branch main", ""}, nil},
+
+		{"first pattern", custom, "[feature/authn]! new login ABC-1", [4]string{"feat", "auth", "new login ABC-1", "!"}, []string{"ABC-1"}},
+		{"first pattern wins", custom, "[fix] crash", [4]string{"fix", "general", "crash", ""}, nil},
+		{"second pattern", custom, "bugfix - null check", [4]string{"fix", "general", "null check", ""}, nil},
+		{"no type group", custom, "[just words]", [4]string{"chore", "general", "just words", ""}, nil},
+		{"empty message group", custom, "docs - ", [4]string{"docs", "general", "docs - ", ""}, nil},
+		{"conventional after patterns", custom, "feature(authn): sso", [4]string{"feat", "auth", "sso", ""}, nil},
+		{"misc alias", custom, "random work", [4]string{"chore", "general", "random work", ""}, nil},
+		{"tickets kept once, in order", custom, "fix: JIRA-2 and ABC-1, again JIRA-2", [4]string{"fix", "general", "JIRA-2 and ABC-1, again JIRA-2", ""}, []string{"JIRA-2", "ABC-1"}},
+	}
+	for _, tt := range tests {
+		c := NewService().WithRules(tt.rules).Parse(tt.line)
+		breaking := ""
+		if c.Breaking {
+			breaking = "!"
+		}
+		if got := [4]string{c.Type, c.Scope, c.Message, breaking}; got != tt.want {
+			t.Errorf("%s: Parse(%q) = %q, want %q", tt.name, tt.line, got, tt.want)
+		}
+		if !reflect.DeepEqual(c.Tickets, tt.tkts) {
+			t.Errorf("%s: tickets = %q, want %q", tt.name, c.Tickets, tt.tkts)
+		}
+		if c.Raw != tt.line {
+			t.Errorf("%s: raw = %q", tt.name, c.Raw)
+		}
+	}
+}
+
+func TestIgnored(t *testing.T) {
+	s := NewService().WithRules(Rules{Ignore: []*regexp.Regexp{
+		regexp.MustCompile(`^wip`),
+		regexp.MustCompile(`(?i)\[skip report\]`),
+	}})
+	tests := []struct {
+		line string
+		want bool
+	}{
+		{"wip: experiments", true},
+		{"fix: typo [Skip Report]", true},
+		{"feat: not wip", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := s.Ignored(tt.line); got != tt.want {
+			t.Errorf("Ignored(%q) = %v, want %v", tt.line, got, tt.want)
+		}
+	}
+	if NewService().Ignored("wip: anything") {
+		t.Error("a service without rules ignores commits")
+	}
+}
@@ -0,0 +1,103 @@
+package renderer
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ansiReset ends any active SGR attribute.
+const ansiReset = "\x1b[0m"
+
+// ansiColors maps color names to SGR parameters.
+var ansiColors = map[string]string{
+	"black":     "30",
+	"red":       "31",
+	"green":     "32",
+	"yellow":    "33",
+	"blue":      "34",
+	"magenta":   "35",
+	"cyan":      "36",
+	"white":     "37",
+	"gray":      "90",
+	"bold":      "1",
+	"dim":       "2",
+	"italic":    "3",
+	"underline": "4",
+}
+
+// ansiPattern matches SGR escape sequences.
+var ansiPattern = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// defaultTheme maps commit types to colors. The "breaking" key applies to
+// breaking changes regardless of their type.
+var defaultTheme = map[string]string{
+	"feat":     "green",
+	"fix":      "red",
+	"breaking": "bold magenta",
+	"docs":     "blue",
+	"style":    "magenta",
+	"refactor": "cyan",
+	"perf":     "yellow",
+	"test":     "yellow",
+	"build":    "gray",
+	"ci":       "gray",
+	"chore":    "gray",
+	"revert":   "red",
+}
+
+// Colors returns the supported color names, sorted.
+func Colors() []string {
+	names := make([]string, 0, len(ansiColors))
+	for name := range ansiColors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DefaultTheme returns a copy of the built-in commit type colors.
+func DefaultTheme() map[string]string {
+	theme := make(map[string]string, len(defaultTheme))
+	for k, v := range defaultTheme {
+		theme[k] = v
+	}
+	return theme
+}
+
+// ValidateColor checks a color specification such as "bold magenta".
+func ValidateColor(spec string) error {
+	for _, name := range strings.Fields(spec) {
+		if _, ok := ansiColors[strings.ToLower(name)]; !ok {
+			return fmt.Errorf("unknown color %q (available: %s)", name, strings.Join(Colors(), ", "))
+		}
+	}
+	return nil
+}
+
+// colorize wraps text in the SGR sequence of a color specification made of
+// one or more space-separated names, e.g. "bold magenta".
+// Unknown color names are ignored.
+func colorize(text, spec string) string {
+	if text == "" {
+		return text
+	}
+
+	var codes []string
+	for _, name := range strings.Fields(spec) {
+		if code, ok := ansiColors[strings.ToLower(name)]; ok {
+			codes = append(codes, code)
+		}
+	}
+	if len(codes) == 0 {
+		return text
+	}
+
+	return "\x1b[" + strings.Join(codes, ";") + "m" + text + ansiReset
+}
+
+// StripANSI removes SGR escape sequences from s.
+func StripANSI(s string) string {
+	return ansiPattern.ReplaceAllString(s, "")
+}
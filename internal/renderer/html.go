@@ -0,0 +1,222 @@
+package renderer
+
+import (
+	"html/template"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/anIcedAntFA/gohome/internal/entity"
+)
+
+// htmlReport is the view model consumed by htmlTemplate.
+type htmlReport struct {
+	Title       string
+	Author      string
+	Period      string
+	GeneratedAt string
+	ShowIcon    bool
+	ShowScope   bool
+	Repos       []htmlRepo
+	Tasks       []entity.Task
+	Summary     htmlSummary
+}
+
+type htmlRepo struct {
+	Name    string
+	URL     string
+	Commits []htmlCommit
+}
+
+type htmlCommit struct {
+	Icon      string
+	Type      string
+	Scope     string
+	Message   string
+	ShortHash string
+	URL       string
+	Breaking  bool
+	Tickets   []htmlTicket
+}
+
+// htmlTicket is a linked ticket ID.
+type htmlTicket struct {
+	ID  string
+	URL string
+}
+
+type htmlSummary struct {
+	Repos   int
+	Commits int
+	Tasks   int
+	Types   []typeCount
+}
+
+// typeCount is the number of commits of one type.
+type typeCount struct {
+	Type  string
+	Count int
+}
+
+// renderHTML writes the report as a self-contained HTML document.
+// All styles are inlined so the file works offline and can be attached to emails.
+func (p *Printer) renderHTML(w io.Writer, r *entity.Report) error {
+	view := htmlReport{
+		Title:       "Work Report",
+		Author:      r.Author,
+		Period:      r.Period,
+		GeneratedAt: r.GeneratedAt.Format(time.RFC1123),
+		ShowIcon:    p.cfg.ShowIcon,
+		ShowScope:   p.cfg.ShowScope,
+		Tasks:       r.Tasks,
+		Summary: htmlSummary{
+			Repos:   len(r.Repos),
+			Commits: r.CommitCount(),
+			Tasks:   len(r.Tasks),
+			Types:   countTypes(r),
+		},
+	}
+
+	for i := range r.Repos {
+		repo := &r.Repos[i]
+		hr := htmlRepo{Name: repo.Name, URL: repo.URL}
+		for _, c := range repo.Commits {
+			hc := htmlCommit{
+				Icon:      c.Icon,
+				Type:      c.Type,
+				Scope:     visibleScope(c.Scope),
+				Message:   c.Message,
+				ShortHash: shortHash(c.Hash),
+				URL:       repo.CommitURL(c.Hash),
+				Breaking:  c.Breaking,
+			}
+			for _, id := range c.Tickets {
+				if url := repo.TicketLink(id); url != "" {
+					hc.Tickets = append(hc.Tickets, htmlTicket{ID: id, URL: url})
+				}
+			}
+			hr.Commits = append(hr.Commits, hc)
+		}
+		view.Repos = append(view.Repos, hr)
+	}
+
+	return htmlTemplate.Execute(w, view)
+}
+
+// countTypes returns commit counts per type, most frequent first.
+func countTypes(r *entity.Report) []typeCount {
+	counts := make(map[string]int)
+	for _, repo := range r.Repos {
+		for _, c := range repo.Commits {
+			counts[c.Type]++
+		}
+	}
+
+	result := make([]typeCount, 0, len(counts))
+	for t, n := range counts {
+		result = append(result, typeCount{Type: t, Count: n})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Type < result[j].Type
+	})
+
+	return result
+}
+
+// visibleScope hides the parser's "-" placeholder for commits without scope.
+func visibleScope(scope string) string {
+	if scope == "-" {
+		return ""
+	}
+	return scope
+}
+
+// shortHash abbreviates a commit hash to 7 characters.
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}} · {{.Author}} · {{.Period}}</title>
+<style>
+  :root { --fg: #1f2328; --muted: #656d76; --border: #d0d7de; --bg-soft: #f6f8fa; --accent: #0969da; }
+  * { box-sizing: border-box; }
+  body { margin: 0 auto; max-width: 960px; padding: 32px 24px; color: var(--fg);
+         font: 14px/1.5 -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; }
+  header { border-bottom: 1px solid var(--border); margin-bottom: 24px; padding-bottom: 16px; }
+  h1 { font-size: 24px; margin: 0 0 4px; }
+  h2 { font-size: 18px; margin: 0 0 12px; }
+  .meta { color: var(--muted); }
+  .summary { display: flex; flex-wrap: wrap; gap: 12px; margin-bottom: 24px; }
+  .counter { border: 1px solid var(--border); border-radius: 6px; background: var(--bg-soft); padding: 8px 16px; min-width: 110px; }
+  .counter b { display: block; font-size: 22px; }
+  .counter span { color: var(--muted); font-size: 12px; text-transform: uppercase; letter-spacing: .04em; }
+  section { border: 1px solid var(--border); border-radius: 6px; padding: 16px; margin-bottom: 16px; page-break-inside: avoid; }
+  ul { list-style: none; margin: 0; padding: 0; }
+  li { padding: 6px 0; border-top: 1px solid var(--bg-soft); display: flex; align-items: baseline; gap: 8px; flex-wrap: wrap; }
+  li:first-child { border-top: 0; }
+  a { color: var(--accent); text-decoration: none; }
+  .badge { border-radius: 4px; padding: 1px 8px; font-size: 12px; font-weight: 600; color: #fff; background: #6e7781; }
+  .badge-feat { background: #1a7f37; } .badge-fix { background: #cf222e; } .badge-docs { background: #0969da; }
+  .badge-refactor { background: #8250df; } .badge-perf { background: #bf8700; } .badge-test { background: #1b7c83; }
+  .badge-chore, .badge-build, .badge-ci { background: #57606a; } .badge-style { background: #bf3989; }
+  .badge-breaking { background: #a40e26; text-transform: uppercase; }
+  .chip { border: 1px solid var(--border); border-radius: 12px; padding: 0 8px; font-size: 12px; color: var(--muted); }
+  .hash { font-family: ui-monospace, SFMono-Regular, Menlo, monospace; font-size: 12px; margin-left: auto; }
+  .types { margin: 0 0 24px; color: var(--muted); }
+  footer { color: var(--muted); font-size: 12px; margin-top: 24px; }
+  @media print {
+    body { padding: 0; max-width: none; }
+    a { color: inherit; }
+    .badge { border: 1px solid #000; color: #000; background: none !important; }
+  }
+</style>
+</head>
+<body>
+<header>
+  <h1>{{.Title}}</h1>
+  <div class="meta">{{.Author}} · since {{.Period}}</div>
+</header>
+
+<div class="summary">
+  <div class="counter"><b>{{.Summary.Repos}}</b><span>Repositories</span></div>
+  <div class="counter"><b>{{.Summary.Commits}}</b><span>Commits</span></div>
+  <div class="counter"><b>{{.Summary.Tasks}}</b><span>Tasks</span></div>
+</div>
+{{if .Summary.Types}}
+<p class="types">{{range $i, $t := .Summary.Types}}{{if $i}} · {{end}}<span class="badge badge-{{$t.Type}}">{{$t.Type}}</span> {{$t.Count}}{{end}}</p>
+{{end}}
+{{range .Repos}}
+<section>
+  <h2>📁 {{if .URL}}<a href="{{.URL}}">{{.Name}}</a>{{else}}{{.Name}}{{end}}</h2>
+  <ul>
+  {{- range .Commits}}
+    <li>{{if $.ShowIcon}}<span>{{.Icon}}</span>{{end}}<span class="badge badge-{{.Type}}">{{.Type}}</span>{{if .Breaking}}<span class="badge badge-breaking">breaking</span>{{end}}{{if and $.ShowScope .Scope}}<span class="chip">{{.Scope}}</span>{{end}}<span>{{.Message}}</span>{{range .Tickets}}<a class="chip" href="{{.URL}}">{{.ID}}</a>{{end}}{{if .ShortHash}}<span class="hash">{{if .URL}}<a href="{{.URL}}">{{.ShortHash}}</a>{{else}}{{.ShortHash}}{{end}}</span>{{end}}</li>
+  {{- end}}
+  </ul>
+</section>
+{{end}}
+{{if .Tasks}}
+<section>
+  <h2>📝 Additional Tasks</h2>
+  <ul>
+  {{- range .Tasks}}
+    <li>{{if and $.ShowIcon .Icon}}<span>{{.Icon}}</span>{{end}}{{if .Type}}<span class="badge badge-{{.Type}}">{{.Type}}</span>{{end}}<span>{{.Message}}</span></li>
+  {{- end}}
+  </ul>
+</section>
+{{end}}
+<footer>Generated by gohome on {{.GeneratedAt}}</footer>
+</body>
+</html>
+`))
@@ -4,6 +4,7 @@ package renderer
 import (
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/olekukonko/tablewriter"
 	"github.com/olekukonko/tablewriter/renderer"
@@ -14,20 +15,62 @@ import (
 
 // Config holds printer configuration options.
 type Config struct {
-	Format    string // "text" or "table"
-	Style     string // "normal" or "markdown"
+	Format    string // "text", "table", "html", "slack" or "slack-blocks"
+	Style     string // Name of a catalogue or custom style, see Styles
 	ShowIcon  bool
 	ShowScope bool
+
+	// CustomStyles are user-defined table styles keyed by name
+	CustomStyles map[string]StyleSpec
+
+	// Color enables ANSI colors in text and table output
+	Color bool
+	// Theme overrides the default commit type colors, see DefaultTheme
+	Theme map[string]string
+}
+
+// formats are the supported output formats.
+var formats = []string{"text", "table", "html", "slack", "slack-blocks"}
+
+// Formats returns the names of the supported output formats.
+func Formats() []string {
+	return append([]string(nil), formats...)
 }
 
 // Printer formats and outputs commit data according to configuration.
 type Printer struct {
-	cfg Config
+	cfg   Config
+	theme map[string]string
 }
 
 // NewPrinter creates a new Printer instance with the given configuration.
 func NewPrinter(cfg Config) *Printer {
-	return &Printer{cfg: cfg}
+	theme := DefaultTheme()
+	for k, v := range cfg.Theme {
+		theme[strings.ToLower(k)] = v
+	}
+	return &Printer{cfg: cfg, theme: theme}
+}
+
+// Render outputs the complete report to the provided writer.
+// Document formats (html, slack, slack-blocks) are rendered as a whole;
+// text and table formats print each repository followed by the tasks section.
+func (p *Printer) Render(w io.Writer, r *entity.Report) error {
+	switch p.cfg.Format {
+	case "html":
+		return p.renderHTML(w, r)
+	case "slack":
+		return p.renderSlack(w, r)
+	case "slack-blocks":
+		return p.renderSlackBlocks(w, r)
+	}
+
+	for _, repo := range r.Repos {
+		p.Print(w, repo.Name, repo.Commits)
+	}
+	p.PrintTasks(w, r.Tasks)
+
+	return nil
 }
 
 // Print outputs formatted commit data to the provided writer.
@@ -45,7 +88,7 @@ func (p *Printer) Print(w io.Writer, repoName string, commits []entity.Commit) {
 
 // printText outputs commits in plain text format.
 func (p *Printer) printText(w io.Writer, repoName string, commits []entity.Commit) {
-	fmt.Fprintf(w, "\n📁 Repository: %s\n", repoName)
+	fmt.Fprintf(w, "\n📁 Repository: %s\n", p.color(repoName, "bold"))
 
 	for _, c := range commits {
 		line := "- "
@@ -54,10 +97,10 @@ func (p *Printer) printText(w io.Writer, repoName string, commits []entity.Commi
 			line += c.Icon + " "
 		}
 
-		line += c.Type
+		line += p.color(c.Type, p.typeColor(c))
 
 		if p.cfg.ShowScope && c.Scope != "" {
-			line += "(" + c.Scope + ")"
+			line += "(" + p.color(c.Scope, "gray") + ")"
 		}
 
 		line += ": " + c.Message
@@ -70,7 +113,7 @@ func (p *Printer) printText(w io.Writer, repoName string, commits []entity.Commi
 
 // printTable outputs commits in table format.
 func (p *Printer) printTable(w io.Writer, repoName string, commits []entity.Commit) {
-	fmt.Fprintf(w, "\n📁 Repository: %s\n", repoName)
+	fmt.Fprintf(w, "\n📁 Repository: %s\n", p.color(repoName, "bold"))
 
 	// Initialize table with Options
 	table := p.createTable(w, p.cfg.Style)
@@ -85,7 +128,7 @@ func (p *Printer) printTable(w io.Writer, repoName string, commits []entity.Comm
 		headers = append(headers, "Scope")
 	}
 	headers = append(headers, "Message")
-	table.Header(headers)
+	table.Header(p.formatHeaders(headers))
 
 	// 2. Data Rows
 	for _, c := range commits {
@@ -93,7 +136,7 @@ func (p *Printer) printTable(w io.Writer, repoName string, commits []entity.Comm
 		if p.cfg.ShowIcon {
 			row = append(row, c.Icon)
 		}
-		row = append(row, c.Type)
+		row = append(row, p.color(c.Type, p.typeColor(c)))
 		if p.cfg.ShowScope {
 			row = append(row, c.Scope)
 		}
@@ -107,16 +150,22 @@ func (p *Printer) printTable(w io.Writer, repoName string, commits []entity.Comm
 }
 
 // createTable initializes tablewriter.Table with Style configuration Options.
-func (p *Printer) createTable(w io.Writer, style string) *tablewriter.Table {
+// Unknown style names fall back to "normal".
+func (p *Printer) createTable(w io.Writer, styleName string) *tablewriter.Table {
 	var options []tablewriter.Option
 
+	style := p.tableStyle(styleName)
+
 	// A. Configure Renderer (Interface)
-	switch style {
-	case "markdown":
+	if style.Markdown {
 		options = append(options, tablewriter.WithRenderer(renderer.NewMarkdown()))
-	default: // "normal"
-		// Use default Blueprint
-		options = append(options, tablewriter.WithRenderer(renderer.NewBlueprint(tw.Rendition{})))
+	} else {
+		rendition := tw.Rendition{Symbols: style.symbols()}
+		if style.Minimal {
+			rendition.Borders = tw.BorderNone
+			rendition.Settings.Separators.BetweenColumns = tw.Off
+		}
+		options = append(options, tablewriter.WithRenderer(renderer.NewBlueprint(rendition)))
 	}
 
 	// B. Configure Config (Alignment, Padding...)
@@ -129,12 +178,61 @@ func (p *Printer) createTable(w io.Writer, style string) *tablewriter.Table {
 			Alignment: tw.CellAlignment{Global: tw.AlignLeft},
 		},
 	}
+
+	// Colored headers are formatted by formatHeaders, auto-formatting would
+	// upper-case the escape sequences
+	if p.cfg.Color && style.HeaderColor != "" {
+		conf.Header.Formatting.AutoFormat = tw.Off
+	}
 	options = append(options, tablewriter.WithConfig(conf))
 
 	// Create new table with writer and built options
 	return tablewriter.NewTable(w, options...)
 }
 
+// tableStyle resolves a style name against the catalogue and custom styles.
+// Callers reject unknown names, the "normal" fallback only covers Config
+// values built without checking them.
+func (p *Printer) tableStyle(name string) TableStyle {
+	if style, ok := LookupStyle(name, p.cfg.CustomStyles); ok {
+		return style
+	}
+	style, _ := LookupStyle("normal", nil)
+	return style
+}
+
+// formatHeaders applies the style's header color.
+func (p *Printer) formatHeaders(headers []string) []string {
+	style := p.tableStyle(p.cfg.Style)
+	if !p.cfg.Color || style.HeaderColor == "" {
+		return headers
+	}
+
+	colored := make([]string, len(headers))
+	for i, h := range headers {
+		colored[i] = colorize(strings.ToUpper(h), style.HeaderColor)
+	}
+	return colored
+}
+
+// color applies a color specification when colors are enabled.
+func (p *Printer) color(text, spec string) string {
+	if !p.cfg.Color {
+		return text
+	}
+	return colorize(text, spec)
+}
+
+// typeColor returns the theme color of a commit.
+func (p *Printer) typeColor(c entity.Commit) string {
+	if c.Breaking {
+		if spec, ok := p.theme["breaking"]; ok {
+			return spec
+		}
+	}
+	return p.theme[strings.ToLower(c.Type)]
+}
+
 // PrintTasks outputs formatted task data to the provided writer.
 func (p *Printer) PrintTasks(w io.Writer, tasks []entity.Task) {
 	if len(tasks) == 0 {
@@ -151,7 +249,7 @@ func (p *Printer) PrintTasks(w io.Writer, tasks []entity.Task) {
 }
 
 func (p *Printer) printTaskText(w io.Writer, title string, tasks []entity.Task) {
-	fmt.Fprintf(w, "\n%s\n", title)
+	fmt.Fprintf(w, "\n%s\n", p.color(title, "bold"))
 	for _, t := range tasks {
 		// Format: - [Icon] Type: Message
 		line := "- "
@@ -159,7 +257,7 @@ func (p *Printer) printTaskText(w io.Writer, title string, tasks []entity.Task)
 			line += t.Icon + " "
 		}
 		if t.Type != "" {
-			line += t.Type + ": "
+			line += p.color(t.Type, p.theme[strings.ToLower(t.Type)]) + ": "
 		}
 		line += t.Message
 		fmt.Fprintln(w, line)
@@ -168,7 +266,7 @@ func (p *Printer) printTaskText(w io.Writer, title string, tasks []entity.Task)
 }
 
 func (p *Printer) printTaskTable(w io.Writer, title string, tasks []entity.Task) {
-	fmt.Fprintf(w, "\n%s\n", title)
+	fmt.Fprintf(w, "\n%s\n", p.color(title, "bold"))
 
 	// Tái sử dụng hàm createTable có sẵn
 	table := p.createTable(w, p.cfg.Style)
@@ -179,7 +277,7 @@ func (p *Printer) printTaskTable(w io.Writer, title string, tasks []entity.Task)
 		headers = append(headers, "Icon")
 	}
 	headers = append(headers, "Type", "Message")
-	table.Header(headers)
+	table.Header(p.formatHeaders(headers))
 
 	for _, t := range tasks {
 		row := []string{}
@@ -0,0 +1,189 @@
+package renderer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/anIcedAntFA/gohome/internal/entity"
+)
+
+// slackSectionLimit is the maximum length of a Block Kit section text.
+const slackSectionLimit = 3000
+
+// slackEscaper escapes the control characters of Slack mrkdwn.
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// slackBlock is a Block Kit layout block.
+type slackBlock struct {
+	Type     string       `json:"type"`
+	Text     *slackText   `json:"text,omitempty"`
+	Elements []*slackText `json:"elements,omitempty"`
+}
+
+// slackText is a Block Kit text object.
+type slackText struct {
+	Type  string `json:"type"`
+	Text  string `json:"text"`
+	Emoji bool   `json:"emoji,omitempty"`
+}
+
+// slackMessage is a Block Kit message payload.
+type slackMessage struct {
+	Text   string       `json:"text"` // Fallback for notifications
+	Blocks []slackBlock `json:"blocks"`
+}
+
+// renderSlack writes the report as Slack mrkdwn text.
+func (p *Printer) renderSlack(w io.Writer, r *entity.Report) error {
+	fmt.Fprintf(w, "*Work Report* · %s · since %s\n", slackEscaper.Replace(r.Author), slackEscaper.Replace(r.Period))
+
+	for i := range r.Repos {
+		repo := &r.Repos[i]
+		fmt.Fprintf(w, "\n*📁 %s*\n", p.slackRepoTitle(repo))
+		for _, c := range repo.Commits {
+			fmt.Fprintln(w, p.slackCommitLine(repo, c))
+		}
+	}
+
+	if len(r.Tasks) > 0 {
+		fmt.Fprintf(w, "\n*📝 Additional Tasks*\n")
+		for _, t := range r.Tasks {
+			fmt.Fprintln(w, p.slackTaskLine(t))
+		}
+	}
+
+	return nil
+}
+
+// renderSlackBlocks writes the report as Block Kit JSON with a section per repository.
+func (p *Printer) renderSlackBlocks(w io.Writer, r *entity.Report) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(p.slackBlocks(r))
+}
+
+// slackBlocks builds the Block Kit message of the report.
+func (p *Printer) slackBlocks(r *entity.Report) slackMessage {
+	msg := slackMessage{
+		Text: fmt.Sprintf("Work report of %s since %s", r.Author, r.Period),
+		Blocks: []slackBlock{
+			{Type: "header", Text: &slackText{Type: "plain_text", Text: "Work Report", Emoji: true}},
+			{Type: "context", Elements: []*slackText{
+				{Type: "mrkdwn", Text: "👤 " + slackEscaper.Replace(r.Author)},
+				{Type: "mrkdwn", Text: "🗓️ since " + slackEscaper.Replace(r.Period)},
+				{Type: "mrkdwn", Text: fmt.Sprintf("📊 %d commits in %d repositories", r.CommitCount(), len(r.Repos))},
+			}},
+			{Type: "divider"},
+		},
+	}
+
+	for i := range r.Repos {
+		repo := &r.Repos[i]
+		lines := make([]string, 0, len(repo.Commits))
+		for _, c := range repo.Commits {
+			lines = append(lines, p.slackCommitLine(repo, c))
+		}
+		msg.Blocks = append(msg.Blocks, slackSections("*📁 "+p.slackRepoTitle(repo)+"*", lines)...)
+	}
+
+	if len(r.Tasks) > 0 {
+		lines := make([]string, 0, len(r.Tasks))
+		for _, t := range r.Tasks {
+			lines = append(lines, p.slackTaskLine(t))
+		}
+		msg.Blocks = append(msg.Blocks, slackBlock{Type: "divider"})
+		msg.Blocks = append(msg.Blocks, slackSections("*📝 Additional Tasks*", lines)...)
+	}
+
+	return msg
+}
+
+// slackSections splits a titled list into sections within Slack's text limit.
+// Lines too long for a section on their own are cut between runes.
+func slackSections(title string, lines []string) []slackBlock {
+	var blocks []slackBlock
+	current := title
+	cont := title + " (cont.)"
+
+	for _, line := range lines {
+		for _, part := range cutLine(line, slackSectionLimit-len(cont)-1) {
+			if len(current)+len(part)+1 > slackSectionLimit {
+				blocks = append(blocks, slackBlock{Type: "section", Text: &slackText{Type: "mrkdwn", Text: current}})
+				current = cont
+			}
+			current += "\n" + part
+		}
+	}
+
+	return append(blocks, slackBlock{Type: "section", Text: &slackText{Type: "mrkdwn", Text: current}})
+}
+
+// cutLine splits a line into parts of at most limit bytes, between runes.
+func cutLine(line string, limit int) []string {
+	var parts []string
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		if cut == 0 {
+			cut = limit // The limit is shorter than a rune
+		}
+		parts = append(parts, line[:cut])
+		line = line[cut:]
+	}
+	return append(parts, line)
+}
+
+// slackRepoTitle returns the repository name, linked when its URL is known.
+func (p *Printer) slackRepoTitle(repo *entity.RepoReport) string {
+	name := slackEscaper.Replace(repo.Name)
+	if repo.URL == "" {
+		return name
+	}
+	return "<" + repo.URL + "|" + name + ">"
+}
+
+// slackCommitLine formats a commit as a mrkdwn bullet.
+func (p *Printer) slackCommitLine(repo *entity.RepoReport, c entity.Commit) string {
+	line := "• "
+	if p.cfg.ShowIcon && c.Icon != "-" {
+		line += c.Icon + " "
+	}
+
+	line += "`" + c.Type + "`"
+	if c.Breaking {
+		line += " *BREAKING*"
+	}
+	if scope := visibleScope(c.Scope); p.cfg.ShowScope && scope != "" {
+		line += " _" + slackEscaper.Replace(scope) + "_"
+	}
+	line += " " + slackEscaper.Replace(c.Message)
+
+	for _, id := range c.Tickets {
+		if url := repo.TicketLink(id); url != "" {
+			line += " <" + url + "|" + slackEscaper.Replace(id) + ">"
+		}
+	}
+	if url := repo.CommitURL(c.Hash); url != "" {
+		line += " (<" + url + "|" + shortHash(c.Hash) + ">)"
+	}
+
+	return line
+}
+
+// slackTaskLine formats a task as a mrkdwn bullet.
+func (p *Printer) slackTaskLine(t entity.Task) string {
+	line := "• "
+	if p.cfg.ShowIcon && t.Icon != "" {
+		line += t.Icon + " "
+	}
+	if t.Type != "" {
+		line += "`" + t.Type + "` "
+	}
+	return line + slackEscaper.Replace(t.Message)
+}
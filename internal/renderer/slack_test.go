@@ -0,0 +1,36 @@
+package renderer
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSlackSectionsCutLongLines(t *testing.T) {
+	long := "• " + strings.Repeat("日本語", 500) // 4502 bytes
+	lines := []string{"• short", long, "• last"}
+
+	blocks := slackSections("*api*", lines)
+	if len(blocks) < 2 {
+		t.Fatalf("expected the long line to span sections, got %d", len(blocks))
+	}
+
+	var joined strings.Builder
+	for i, b := range blocks {
+		text := b.Text.Text
+		if len(text) > slackSectionLimit {
+			t.Errorf("section %d has %d bytes, over the limit", i, len(text))
+		}
+		if !utf8.ValidString(text) {
+			t.Errorf("section %d is not valid UTF-8", i)
+		}
+		title, body, _ := strings.Cut(text, "\n")
+		if i > 0 && title != "*api* (cont.)" {
+			t.Errorf("section %d title = %q", i, title)
+		}
+		joined.WriteString(strings.ReplaceAll(body, "\n", ""))
+	}
+	if want := strings.Join(lines, ""); joined.String() != want {
+		t.Error("sections do not add up to the lines")
+	}
+}
@@ -0,0 +1,138 @@
+package renderer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/olekukonko/tablewriter/tw"
+)
+
+// StyleSpec describes a user-defined table style.
+type StyleSpec struct {
+	Border      string // Name of a border set, see BorderSets
+	HeaderColor string // Optional header color, see Colors
+}
+
+// TableStyle is an entry of the table style catalogue.
+type TableStyle struct {
+	Name        string
+	Description string
+	Border      string // Border set name, ignored for markdown
+	HeaderColor string // Empty for monochrome headers
+	Markdown    bool   // Render as a GitHub-flavored markdown table
+	Minimal     bool   // Hide outer borders and column separators
+	Custom      bool   // Defined in the config file
+}
+
+// borderSets maps border set names to tablewriter symbol styles.
+var borderSets = map[string]tw.BorderStyle{
+	"ascii":   tw.StyleASCII,
+	"light":   tw.StyleLight,
+	"rounded": tw.StyleRounded,
+	"heavy":   tw.StyleHeavy,
+	"double":  tw.StyleDouble,
+	"dotted":  tw.StyleDotted,
+	"nature":  tw.StyleNature,
+	"circuit": tw.StyleCircuit,
+	"arrow":   tw.StyleArrow,
+	"starry":  tw.StyleStarry,
+	"blocks":  tw.StyleBlocks,
+	"zen":     tw.StyleZen,
+	"vintage": tw.StyleVintage,
+	"none":    tw.StyleNone,
+}
+
+// builtinStyles is the catalogue of styles available through --style.
+var builtinStyles = []TableStyle{
+	{Name: "normal", Description: "Light box-drawing borders (default)", Border: "light"},
+	{Name: "markdown", Description: "GitHub-flavored markdown table", Markdown: true},
+	{Name: "ascii", Description: "Plain ASCII borders (+, -, |)", Border: "ascii"},
+	{Name: "rounded", Description: "Light borders with rounded corners", Border: "rounded"},
+	{Name: "heavy", Description: "Thick box-drawing borders", Border: "heavy"},
+	{Name: "double", Description: "Double-line borders", Border: "double"},
+	{Name: "minimal", Description: "No outer borders, header underline only", Border: "light", Minimal: true},
+	{Name: "colorized", Description: "Light borders with a cyan header", Border: "light", HeaderColor: "cyan"},
+	{Name: "dotted", Description: "Dotted borders", Border: "dotted"},
+	{Name: "nature", Description: "Decorative nature-themed borders", Border: "nature"},
+	{Name: "tech", Description: "Circuit-board themed borders", Border: "circuit"},
+	{Name: "starry", Description: "Star-studded borders", Border: "starry"},
+	{Name: "zen", Description: "Calm, sparse borders", Border: "zen"},
+}
+
+// BorderSets returns the names of the available border sets, sorted.
+func BorderSets() []string {
+	names := make([]string, 0, len(borderSets))
+	for name := range borderSets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Styles returns the built-in catalogue followed by custom styles sorted by name.
+func Styles(custom map[string]StyleSpec) []TableStyle {
+	styles := make([]TableStyle, 0, len(builtinStyles)+len(custom))
+	styles = append(styles, builtinStyles...)
+
+	names := make([]string, 0, len(custom))
+	for name := range custom {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		spec := custom[name]
+		desc := "Custom: " + spec.Border + " borders"
+		if spec.HeaderColor != "" {
+			desc += ", " + spec.HeaderColor + " header"
+		}
+		styles = append(styles, TableStyle{
+			Name:        name,
+			Description: desc,
+			Border:      spec.Border,
+			HeaderColor: spec.HeaderColor,
+			Custom:      true,
+		})
+	}
+
+	return styles
+}
+
+// LookupStyle finds a style by name. Custom styles take precedence over
+// built-in ones so users can redefine them.
+func LookupStyle(name string, custom map[string]StyleSpec) (TableStyle, bool) {
+	name = strings.ToLower(name)
+	if spec, ok := custom[name]; ok {
+		return TableStyle{Name: name, Border: spec.Border, HeaderColor: spec.HeaderColor, Custom: true}, true
+	}
+
+	for _, s := range builtinStyles {
+		if s.Name == name {
+			return s, true
+		}
+	}
+
+	return TableStyle{}, false
+}
+
+// ValidateStyleSpec checks that a custom style references known names.
+func ValidateStyleSpec(spec StyleSpec) error {
+	if _, ok := borderSets[strings.ToLower(spec.Border)]; !ok {
+		return fmt.Errorf("unknown border set %q (available: %s)", spec.Border, strings.Join(BorderSets(), ", "))
+	}
+	if spec.HeaderColor != "" {
+		if _, ok := ansiColors[strings.ToLower(spec.HeaderColor)]; !ok {
+			return fmt.Errorf("unknown color %q (available: %s)", spec.HeaderColor, strings.Join(Colors(), ", "))
+		}
+	}
+	return nil
+}
+
+// symbols returns the tablewriter symbols of the style's border set.
+func (s TableStyle) symbols() tw.Symbols {
+	if style, ok := borderSets[strings.ToLower(s.Border)]; ok {
+		return tw.NewSymbols(style)
+	}
+	return tw.NewSymbols(tw.StyleLight)
+}
@@ -0,0 +1,44 @@
+package sys
+
+import (
+	"os"
+	"strings"
+)
+
+// Color modes accepted by ColorEnabled.
+const (
+	ColorAuto   = "auto"
+	ColorAlways = "always"
+	ColorNever  = "never"
+)
+
+// IsTerminal reports whether the file is attached to a terminal.
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// ColorEnabled decides whether ANSI colors should be written to f.
+// "always" and "never" are honored as-is; "auto" enables colors only for
+// terminals, unless NO_COLOR is set or TERM is "dumb".
+func ColorEnabled(mode string, f *os.File) bool {
+	switch strings.ToLower(mode) {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+
+	// See https://no-color.org
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+
+	return f != nil && IsTerminal(f)
+}
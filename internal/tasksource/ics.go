@@ -0,0 +1,541 @@
+package tasksource
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxOccurrences bounds the expansion of a recurring event, so a rule
+// without an end stops even for long periods.
+const maxOccurrences = 10000
+
+// event is a VEVENT of an iCalendar file.
+type event struct {
+	uid          string
+	summary      string
+	start        time.Time
+	duration     time.Duration
+	rrule        string
+	exdates      []time.Time
+	recurrenceID time.Time // Set on the changed occurrences of a recurring event
+	cancelled    bool
+	allDay       bool
+}
+
+// ReadCalendar returns the events of an iCalendar file, or of the .ics
+// files of a directory such as a vdir, that start between from and to.
+// Recurring events are expanded.
+func ReadCalendar(path string, from, to time.Time) ([]Item, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		files = nil
+		err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err == nil && !d.IsDir() && strings.EqualFold(filepath.Ext(p), ".ics") {
+				files = append(files, p)
+			}
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var events []event
+	for _, file := range files {
+		// #nosec G304 -- calendars are files chosen by the user in the config
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		parsed, err := parseCalendar(f)
+		_ = f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		events = append(events, parsed...)
+	}
+
+	return occurrences(events, from, to)
+}
+
+// occurrences returns the events starting between from and to, sorted.
+// Changed occurrences replace the ones of their recurring event.
+func occurrences(events []event, from, to time.Time) ([]Item, error) {
+	changed := make(map[string]bool)
+	for _, e := range events {
+		if !e.recurrenceID.IsZero() {
+			changed[e.uid+"|"+e.recurrenceID.UTC().Format(time.RFC3339)] = true
+		}
+	}
+
+	var items []Item
+	add := func(e event, start time.Time) {
+		if !start.Before(from) && !start.After(to) {
+			items = append(items, Item{Done: start, Message: e.summary, Duration: e.duration})
+		}
+	}
+	for _, e := range events {
+		if e.cancelled || e.summary == "" {
+			continue
+		}
+		if e.rrule == "" || !e.recurrenceID.IsZero() {
+			add(e, e.start)
+			continue
+		}
+
+		starts, err := expand(e.rrule, e.start, to)
+		if err != nil {
+			return nil, fmt.Errorf("event %q: %w", e.summary, err)
+		}
+		for _, start := range starts {
+			if changed[e.uid+"|"+start.UTC().Format(time.RFC3339)] || containsTime(e.exdates, start) {
+				continue
+			}
+			add(e, start)
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Done.Before(items[j].Done) })
+	return items, nil
+}
+
+func containsTime(times []time.Time, t time.Time) bool {
+	for _, x := range times {
+		if x.Equal(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseCalendar reads the events of an iCalendar stream.
+func parseCalendar(r io.Reader) ([]event, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []event
+	var current *event
+	var end time.Time
+	depth := 0 // Nesting below the VEVENT, e.g. VALARM
+	for _, line := range lines {
+		name, params, value := splitProperty(line)
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			current, end = &event{}, time.Time{}
+			continue
+		case current == nil:
+			continue
+		case name == "BEGIN":
+			depth++
+			continue
+		case name == "END" && value == "VEVENT":
+			if current.duration == 0 && !end.IsZero() {
+				current.duration = end.Sub(current.start)
+			}
+			if current.allDay {
+				current.duration = 0 // Days off and reminders, not meetings
+			}
+			if !current.start.IsZero() {
+				events = append(events, *current)
+			}
+			current = nil
+			continue
+		case name == "END":
+			depth--
+			continue
+		case depth > 0:
+			continue
+		}
+
+		switch name {
+		case "UID":
+			current.uid = value
+		case "SUMMARY":
+			current.summary = unescapeText(value)
+		case "STATUS":
+			current.cancelled = strings.EqualFold(value, "CANCELLED")
+		case "RRULE":
+			current.rrule = value
+		case "DTSTART":
+			if current.start, err = parseICSTime(value, params); err != nil {
+				return nil, err
+			}
+			current.allDay = len(strings.TrimSpace(value)) == len("20060102")
+		case "DTEND":
+			if end, err = parseICSTime(value, params); err != nil {
+				return nil, err
+			}
+		case "DURATION":
+			if current.duration, err = parseICSDuration(value); err != nil {
+				return nil, err
+			}
+		case "RECURRENCE-ID":
+			if current.recurrenceID, err = parseICSTime(value, params); err != nil {
+				return nil, err
+			}
+		case "EXDATE":
+			for _, v := range strings.Split(value, ",") {
+				t, err := parseICSTime(v, params)
+				if err != nil {
+					return nil, err
+				}
+				current.exdates = append(current.exdates, t)
+			}
+		}
+	}
+	return events, nil
+}
+
+// unfold joins the continuation lines of an iCalendar stream, which start
+// with a space or a tab.
+func unfold(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// splitProperty splits "DTSTART;TZID=Europe/Paris:20260109T100000" into
+// its name, parameters and value.
+func splitProperty(line string) (string, map[string]string, string) {
+	// The value starts at the first colon outside a quoted parameter
+	quoted, colon := false, -1
+	for i, c := range line {
+		if c == '"' {
+			quoted = !quoted
+		} else if c == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return strings.ToUpper(line), nil, ""
+	}
+
+	parts := strings.Split(line[:colon], ";")
+	params := make(map[string]string)
+	for _, p := range parts[1:] {
+		if k, v, ok := strings.Cut(p, "="); ok {
+			params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+		}
+	}
+	return strings.ToUpper(parts[0]), params, line[colon+1:]
+}
+
+// unescapeText undoes the escaping of iCalendar text values.
+func unescapeText(s string) string {
+	return strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(s)
+}
+
+// parseICSTime reads a date or a date-time: UTC ("...Z"), in the TZID
+// time zone, or floating in the local time zone.
+func parseICSTime(value string, params map[string]string) (time.Time, error) {
+	loc := time.Local
+	if tzid := params["TZID"]; tzid != "" {
+		// Unknown zones, e.g. Windows names, fall back to local time
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+
+	value = strings.TrimSpace(value)
+	layouts := []string{"20060102T150405", "20060102"}
+	if strings.HasSuffix(value, "Z") {
+		value, loc = strings.TrimSuffix(value, "Z"), time.UTC
+	}
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
+
+// icsDuration matches durations such as "PT1H30M", "P1D" or "P2W".
+var icsDuration = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// parseICSDuration reads an iCalendar duration.
+func parseICSDuration(value string) (time.Duration, error) {
+	m := icsDuration.FindStringSubmatch(strings.TrimSpace(value))
+	if m == nil {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var d time.Duration
+	for i, unit := range units {
+		if m[i+2] != "" {
+			n, _ := strconv.Atoi(m[i+2])
+			d += time.Duration(n) * unit
+		}
+	}
+	if m[1] == "-" {
+		d = -d
+	}
+	return d, nil
+}
+
+// rule is a parsed RRULE.
+type rule struct {
+	freq       string
+	interval   int
+	count      int
+	until      time.Time
+	byDay      []weekdayNum
+	byMonthDay []int
+	byMonth    []int
+}
+
+// weekdayNum is a BYDAY value, e.g. "MO" or "-1FR" (the last Friday).
+type weekdayNum struct {
+	n   int
+	day time.Weekday
+}
+
+var icsWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// parseRule reads the parts of an RRULE that gohome supports: FREQ,
+// INTERVAL, COUNT, UNTIL, BYDAY, BYMONTHDAY and BYMONTH.
+func parseRule(value string, loc *time.Location) (rule, error) {
+	r := rule{interval: 1}
+	for _, part := range strings.Split(value, ";") {
+		key, val, _ := strings.Cut(part, "=")
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			r.freq = strings.ToUpper(val)
+		case "INTERVAL":
+			r.interval, err = strconv.Atoi(val)
+		case "COUNT":
+			r.count, err = strconv.Atoi(val)
+		case "UNTIL":
+			params := map[string]string{}
+			if loc != time.Local {
+				params["TZID"] = loc.String()
+			}
+			r.until, err = parseICSTime(val, params)
+			if err == nil && len(val) == 8 {
+				r.until = r.until.AddDate(0, 0, 1).Add(-time.Second) // The whole day
+			}
+		case "BYDAY":
+			for _, d := range strings.Split(val, ",") {
+				d = strings.ToUpper(strings.TrimSpace(d))
+				if len(d) < 2 {
+					return r, fmt.Errorf("invalid BYDAY %q", val)
+				}
+				day, ok := icsWeekdays[d[len(d)-2:]]
+				if !ok {
+					return r, fmt.Errorf("invalid BYDAY %q", val)
+				}
+				n := 0
+				if num := d[:len(d)-2]; num != "" {
+					if n, err = strconv.Atoi(num); err != nil {
+						return r, fmt.Errorf("invalid BYDAY %q", val)
+					}
+				}
+				r.byDay = append(r.byDay, weekdayNum{n: n, day: day})
+			}
+		case "BYMONTHDAY":
+			r.byMonthDay, err = atoiList(val)
+		case "BYMONTH":
+			r.byMonth, err = atoiList(val)
+		}
+		if err != nil {
+			return r, fmt.Errorf("invalid %s in RRULE %q", key, value)
+		}
+	}
+
+	switch r.freq {
+	case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+	default:
+		return r, fmt.Errorf("unsupported RRULE frequency %q", r.freq)
+	}
+	if r.interval < 1 {
+		return r, errors.New("RRULE INTERVAL must be positive")
+	}
+	return r, nil
+}
+
+func atoiList(s string) ([]int, error) {
+	var list []int
+	for _, v := range strings.Split(s, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, n)
+	}
+	return list, nil
+}
+
+// expand returns the start times of a recurring event up to limit, the
+// first one being the event's own start.
+func expand(value string, start, limit time.Time) ([]time.Time, error) {
+	r, err := parseRule(value, start.Location())
+	if err != nil {
+		return nil, err
+	}
+
+	var starts []time.Time
+	done := func(t time.Time) bool {
+		return t.After(limit) || (!r.until.IsZero() && t.After(r.until)) ||
+			(r.count > 0 && len(starts) >= r.count) || len(starts) >= maxOccurrences
+	}
+
+	// Each period (day, week, month or year) gives its candidate days in order
+	for period := 0; ; period++ {
+		candidates, first := r.period(start, period)
+		if first.After(limit) || (!r.until.IsZero() && first.After(r.until)) || period > maxOccurrences {
+			break
+		}
+		for _, t := range candidates {
+			if t.Before(start) {
+				continue
+			}
+			if done(t) {
+				return starts, nil
+			}
+			starts = append(starts, t)
+		}
+		if r.count > 0 && len(starts) >= r.count {
+			break
+		}
+	}
+	return starts, nil
+}
+
+// period returns the candidate start times of the nth period of the rule,
+// sorted, and the beginning of the period.
+func (r rule) period(start time.Time, n int) ([]time.Time, time.Time) {
+	at := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, start.Hour(), start.Minute(), start.Second(), 0, start.Location())
+	}
+	y, m, d := start.Date()
+
+	switch r.freq {
+	case "DAILY":
+		t := at(y, m, d+n*r.interval)
+		if len(r.byDay) > 0 && !r.hasWeekday(t.Weekday()) {
+			return nil, t
+		}
+		return []time.Time{t}, t
+
+	case "WEEKLY":
+		// Weeks start on Monday
+		monday := at(y, m, d-(int(start.Weekday())+6)%7+7*n*r.interval)
+		days := []time.Weekday{start.Weekday()}
+		if len(r.byDay) > 0 {
+			days = nil
+			for _, wd := range r.byDay {
+				days = append(days, wd.day)
+			}
+		}
+		var times []time.Time
+		for _, day := range days {
+			my, mm, md := monday.Date()
+			times = append(times, at(my, mm, md+(int(day)+6)%7))
+		}
+		sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+		return times, monday
+
+	case "MONTHLY":
+		first := at(y, m+time.Month(n*r.interval), 1)
+		return r.monthDays(first, d, at), first
+
+	default: // YEARLY
+		first := at(y+n*r.interval, time.January, 1)
+		months := r.byMonth
+		if len(months) == 0 {
+			months = []int{int(m)}
+		}
+		var times []time.Time
+		for _, month := range months {
+			times = append(times, r.monthDays(at(first.Year(), time.Month(month), 1), d, at)...)
+		}
+		sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+		return times, first
+	}
+}
+
+// monthDays returns the candidate days of the month starting at first:
+// BYMONTHDAY, BYDAY (e.g. the first Monday) or the day of the start.
+func (r rule) monthDays(first time.Time, startDay int, at func(int, time.Month, int) time.Time) []time.Time {
+	y, m, _ := first.Date()
+	last := first.AddDate(0, 1, -1).Day()
+
+	var days []int
+	switch {
+	case len(r.byMonthDay) > 0:
+		for _, d := range r.byMonthDay {
+			if d < 0 {
+				d = last + d + 1
+			}
+			days = append(days, d)
+		}
+	case len(r.byDay) > 0:
+		for _, wd := range r.byDay {
+			var matches []int
+			for d := 1; d <= last; d++ {
+				if at(y, m, d).Weekday() == wd.day {
+					matches = append(matches, d)
+				}
+			}
+			switch {
+			case wd.n == 0:
+				days = append(days, matches...)
+			case wd.n > 0 && wd.n <= len(matches):
+				days = append(days, matches[wd.n-1])
+			case wd.n < 0 && -wd.n <= len(matches):
+				days = append(days, matches[len(matches)+wd.n])
+			}
+		}
+	default:
+		days = []int{startDay}
+	}
+
+	sort.Ints(days)
+	var times []time.Time
+	for _, d := range days {
+		// Months without the day, e.g. the 31st, are skipped
+		if d >= 1 && d <= last {
+			times = append(times, at(y, m, d))
+		}
+	}
+	return times
+}
+
+func (r rule) hasWeekday(day time.Weekday) bool {
+	for _, wd := range r.byDay {
+		if wd.day == day {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,212 @@
+package tasksource
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// calendar wraps events in a VCALENDAR with CRLF line endings.
+func calendar(events ...string) string {
+	lines := []string{"BEGIN:VCALENDAR", "VERSION:2.0"}
+	for _, e := range events {
+		lines = append(lines, "BEGIN:VEVENT")
+		lines = append(lines, strings.Split(strings.TrimSpace(e), "\n")...)
+		lines = append(lines, "END:VEVENT")
+	}
+	lines = append(lines, "END:VCALENDAR")
+	return strings.Join(lines, "\r\n") + "\r\n"
+}
+
+func readCalendar(t *testing.T, input string, from, to time.Time) []Item {
+	t.Helper()
+	events, err := parseCalendar(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	items, err := occurrences(events, from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return items
+}
+
+func utc(s string) time.Time {
+	t, err := time.Parse("2006-01-02 15:04", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func starts(items []Item) []string {
+	var s []string
+	for _, item := range items {
+		s = append(s, item.Done.UTC().Format("2006-01-02 15:04"))
+	}
+	return s
+}
+
+func TestCalendarEvent(t *testing.T) {
+	input := calendar(`
+UID:review
+SUMMARY:Design review\, API
+  v2
+DTSTART;TZID=America/New_York:20261015T090000
+DTEND;TZID=America/New_York:20261015T103000
+BEGIN:VALARM
+SUMMARY:Reminder
+TRIGGER:-PT15M
+END:VALARM`, `
+UID:cancelled
+SUMMARY:Cancelled sync
+STATUS:CANCELLED
+DTSTART:20261015T150000Z
+DURATION:PT30M`, `
+UID:holiday
+SUMMARY:Day off
+DTSTART;VALUE=DATE:20261016
+DTEND;VALUE=DATE:20261017`)
+
+	items := readCalendar(t, input, utc("2026-10-12 00:00"), utc("2026-10-18 00:00"))
+	if len(items) != 2 {
+		t.Fatalf("got %d items %+v, want 2", len(items), items)
+	}
+	review := items[0]
+	if review.Message != "Design review, API v2" {
+		t.Errorf("message = %q", review.Message)
+	}
+	if !review.Done.Equal(utc("2026-10-15 13:00")) {
+		t.Errorf("start = %v, want 13:00 UTC", review.Done.UTC())
+	}
+	if review.Duration != 90*time.Minute {
+		t.Errorf("duration = %v, want 1h30m", review.Duration)
+	}
+	if items[1].Message != "Day off" || items[1].Duration != 0 {
+		t.Errorf("all-day event = %+v, want no duration", items[1])
+	}
+}
+
+func TestCalendarRecurrence(t *testing.T) {
+	tests := []struct {
+		name     string
+		event    string
+		from, to string
+		want     []string
+	}{
+		{
+			name: "weekly on several days with a count",
+			event: `
+UID:a
+SUMMARY:Pairing
+DTSTART:20261005T140000Z
+RRULE:FREQ=WEEKLY;BYDAY=MO,WE,FR;COUNT=5`,
+			from: "2026-10-01 00:00", to: "2026-10-31 00:00",
+			want: []string{"2026-10-05 14:00", "2026-10-07 14:00", "2026-10-09 14:00", "2026-10-12 14:00", "2026-10-14 14:00"},
+		},
+		{
+			name: "weekly until a date",
+			event: `
+UID:b
+SUMMARY:1:1
+DTSTART:20261006T100000Z
+RRULE:FREQ=WEEKLY;UNTIL=20261020T000000Z`,
+			from: "2026-10-01 00:00", to: "2026-10-31 00:00",
+			want: []string{"2026-10-06 10:00", "2026-10-13 10:00"},
+		},
+		{
+			name: "every other week, within the period",
+			event: `
+UID:c
+SUMMARY:Sprint planning
+DTSTART:20260907T090000Z
+RRULE:FREQ=WEEKLY;INTERVAL=2`,
+			from: "2026-10-01 00:00", to: "2026-10-31 00:00",
+			want: []string{"2026-10-05 09:00", "2026-10-19 09:00"},
+		},
+		{
+			name: "last Friday of the month",
+			event: `
+UID:d
+SUMMARY:Demo
+DTSTART:20260130T160000Z
+RRULE:FREQ=MONTHLY;BYDAY=-1FR`,
+			from: "2026-01-01 00:00", to: "2026-04-30 00:00",
+			want: []string{"2026-01-30 16:00", "2026-02-27 16:00", "2026-03-27 16:00", "2026-04-24 16:00"},
+		},
+		{
+			name: "monthly on the 31st skips short months",
+			event: `
+UID:e
+SUMMARY:Invoices
+DTSTART:20260131T080000Z
+RRULE:FREQ=MONTHLY`,
+			from: "2026-01-01 00:00", to: "2026-05-31 23:00",
+			want: []string{"2026-01-31 08:00", "2026-03-31 08:00", "2026-05-31 08:00"},
+		},
+		{
+			name: "yearly",
+			event: `
+UID:f
+SUMMARY:Planning offsite
+DTSTART:20241014T090000Z
+RRULE:FREQ=YEARLY`,
+			from: "2026-10-01 00:00", to: "2026-10-31 00:00",
+			want: []string{"2026-10-14 09:00"},
+		},
+	}
+
+	for _, tt := range tests {
+		got := starts(readCalendar(t, calendar(tt.event), utc(tt.from), utc(tt.to)))
+		if strings.Join(got, ", ") != strings.Join(tt.want, ", ") {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCalendarExceptions(t *testing.T) {
+	input := calendar(`
+UID:standup
+SUMMARY:Standup
+DTSTART:20261012T090000Z
+DURATION:PT15M
+RRULE:FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR
+EXDATE:20261013T090000Z`, `
+UID:standup
+SUMMARY:Standup (moved)
+RECURRENCE-ID:20261014T090000Z
+DTSTART:20261014T113000Z
+DURATION:PT15M`)
+
+	items := readCalendar(t, input, utc("2026-10-12 00:00"), utc("2026-10-18 00:00"))
+	want := []Item{
+		{Done: utc("2026-10-12 09:00"), Message: "Standup"},
+		{Done: utc("2026-10-14 11:30"), Message: "Standup (moved)"},
+		{Done: utc("2026-10-15 09:00"), Message: "Standup"},
+		{Done: utc("2026-10-16 09:00"), Message: "Standup"},
+	}
+	if len(items) != len(want) {
+		t.Fatalf("got %v, want %d items", starts(items), len(want))
+	}
+	for i, w := range want {
+		if !items[i].Done.Equal(w.Done) || items[i].Message != w.Message || items[i].Duration != 15*time.Minute {
+			t.Errorf("item %d = %+v, want %+v", i, items[i], w)
+		}
+	}
+}
+
+func TestCalendarInvalidRule(t *testing.T) {
+	input := calendar(`
+UID:x
+SUMMARY:Broken
+DTSTART:20261012T090000Z
+RRULE:FREQ=HOURLY`)
+
+	events, err := parseCalendar(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := occurrences(events, utc("2026-10-12 00:00"), utc("2026-10-18 00:00")); err == nil {
+		t.Error("expected an error for an unsupported frequency")
+	}
+}
@@ -0,0 +1,164 @@
+// Package tasksource imports done tasks from files kept by other tools:
+// todo.txt files, Markdown checklists and iCalendar files.
+package tasksource
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Source types.
+const (
+	TypeTodoTxt  = "todotxt"
+	TypeMarkdown = "markdown"
+	TypeICS      = "ics"
+)
+
+// dateLayout is the date format of todo.txt and of Markdown annotations.
+const dateLayout = "2006-01-02"
+
+// Types returns the supported source types.
+func Types() []string {
+	return []string{TypeTodoTxt, TypeMarkdown, TypeICS}
+}
+
+// Defaults returns the task type and icon of the items of a source type.
+func Defaults(kind string) (string, string) {
+	if kind == TypeICS {
+		return "meeting", "📅"
+	}
+	return "todo", "✅"
+}
+
+// Item is a task that was done at a given time.
+type Item struct {
+	Done     time.Time
+	Message  string
+	Duration time.Duration // Length of calendar events
+}
+
+var (
+	// checkedItem matches a checked Markdown list item: "- [x] Ship it".
+	checkedItem = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+\[[xX]\]\s+(.+)$`)
+	// doneDate matches the completion date annotations of checklist items:
+	// "✅ 2026-01-09" (Obsidian Tasks), "@2026-01-09" and "done:2026-01-09".
+	doneDate = regexp.MustCompile(`(?:✅\s*|@|\bdone:)(\d{4}-\d{2}-\d{2})\b`)
+	// todoPriority matches the priority kept by todo.txt clients on done tasks.
+	todoPriority = regexp.MustCompile(`(^|\s)pri:[A-Z]\b`)
+)
+
+// Read returns the items of a source done from the day of from to to.
+// Items of todo.txt files and checklists without a date are skipped: the
+// time the file was last changed does not tell when they were done.
+func Read(kind, path string, from, to time.Time) ([]Item, error) {
+	if kind == TypeICS {
+		return ReadCalendar(path, startOfDay(from), to)
+	}
+
+	// #nosec G304 -- sources are files chosen by the user in the config
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var items []Item
+	switch kind {
+	case TypeTodoTxt:
+		items, err = ReadTodoTxt(f)
+	case TypeMarkdown:
+		items, err = ReadMarkdown(f)
+	default:
+		return nil, fmt.Errorf("unknown task source type %q (use %s)", kind, strings.Join(Types(), ", "))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return Within(items, from, to), nil
+}
+
+// ReadTodoTxt returns the completed tasks of a todo.txt file: the lines
+// starting with "x ", done on their completion date. Lines without one are
+// skipped.
+func ReadTodoTxt(r io.Reader) ([]Item, error) {
+	var items []Item
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		rest, ok := strings.CutPrefix(scanner.Text(), "x ")
+		if !ok {
+			continue
+		}
+
+		// Completion date, then the optional creation date
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			continue
+		}
+		done, err := time.ParseInLocation(dateLayout, fields[0], time.Local)
+		if err != nil {
+			continue
+		}
+		fields = fields[1:]
+		if len(fields) > 0 {
+			if _, err := time.Parse(dateLayout, fields[0]); err == nil {
+				fields = fields[1:]
+			}
+		}
+
+		message := strings.TrimSpace(todoPriority.ReplaceAllString(strings.Join(fields, " "), " "))
+		if message != "" {
+			items = append(items, Item{Done: done, Message: strings.Join(strings.Fields(message), " ")})
+		}
+	}
+	return items, scanner.Err()
+}
+
+// ReadMarkdown returns the checked items of the checklists of a Markdown
+// file, done on their annotated date. Items without one are skipped.
+func ReadMarkdown(r io.Reader) ([]Item, error) {
+	var items []Item
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		m := checkedItem.FindStringSubmatch(scanner.Text())
+		if m == nil {
+			continue
+		}
+
+		d := doneDate.FindStringSubmatch(m[1])
+		if d == nil {
+			continue
+		}
+		done, err := time.ParseInLocation(dateLayout, d[1], time.Local)
+		if err != nil {
+			continue
+		}
+		message := doneDate.ReplaceAllString(m[1], "")
+
+		if message = strings.Join(strings.Fields(message), " "); message != "" {
+			items = append(items, Item{Done: done, Message: message})
+		}
+	}
+	return items, scanner.Err()
+}
+
+// Within returns the items done from the day of from to to. Dates have no
+// time of day, so the whole first day of the period counts.
+func Within(items []Item, from, to time.Time) []Item {
+	start := startOfDay(from)
+	var within []Item
+	for _, item := range items {
+		if !item.Done.Before(start) && !item.Done.After(to) {
+			within = append(within, item)
+		}
+	}
+	return within
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,89 @@
+package tasksource
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+var today = time.Date(2026, 10, 18, 9, 30, 0, 0, time.Local)
+
+func day(s string) time.Time {
+	t, err := time.ParseInLocation(dateLayout, s, time.Local)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestReadTodoTxt(t *testing.T) {
+	input := strings.Join([]string{
+		"(A) Call the dentist +Life",
+		"x 2026-10-16 2026-10-10 Upgrade Postgres +infra @work pri:A",
+		"x 2026-09-01 Renew the certificate",
+		"x Undated task",
+		"x 2026-02-30 Invalid date",
+		"xylophone lesson",
+	}, "\n")
+
+	items, err := ReadTodoTxt(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Item{
+		{Done: day("2026-10-16"), Message: "Upgrade Postgres +infra @work"},
+		{Done: day("2026-09-01"), Message: "Renew the certificate"},
+	}
+	if len(items) != len(want) {
+		t.Fatalf("got %d items %+v, want %d", len(items), items, len(want))
+	}
+	for i := range want {
+		if !items[i].Done.Equal(want[i].Done) || items[i].Message != want[i].Message {
+			t.Errorf("item %d = %+v, want %+v", i, items[i], want[i])
+		}
+	}
+}
+
+func TestReadMarkdown(t *testing.T) {
+	input := strings.Join([]string{
+		"# Week",
+		"- [x] Ship v2 ✅ 2026-10-17",
+		"- [ ] Not done",
+		"  * [X] Nested item @2026-10-18",
+		"- [x] Close the sprint done:2026-10-16",
+		"1. [x] Undated item",
+		"- [x] Invalid date @2026-02-30",
+		"[x] Not a list item",
+	}, "\n")
+
+	items, err := ReadMarkdown(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Item{
+		{Done: day("2026-10-17"), Message: "Ship v2"},
+		{Done: day("2026-10-18"), Message: "Nested item"},
+		{Done: day("2026-10-16"), Message: "Close the sprint"},
+	}
+	if len(items) != len(want) {
+		t.Fatalf("got %d items %+v, want %d", len(items), items, len(want))
+	}
+	for i := range want {
+		if !items[i].Done.Equal(want[i].Done) || items[i].Message != want[i].Message {
+			t.Errorf("item %d = %+v, want %+v", i, items[i], want[i])
+		}
+	}
+}
+
+func TestWithinCountsTheWholeFirstDay(t *testing.T) {
+	items := []Item{
+		{Done: day("2026-10-16"), Message: "before"},
+		{Done: day("2026-10-17"), Message: "first day"},
+		{Done: today, Message: "today"},
+	}
+	now := today.Add(time.Hour)
+	got := Within(items, now.AddDate(0, 0, -1), now)
+	if len(got) != 2 || got[0].Message != "first day" || got[1].Message != "today" {
+		t.Errorf("got %+v", got)
+	}
+}
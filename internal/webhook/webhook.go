@@ -0,0 +1,296 @@
+// Package webhook delivers rendered reports to chat webhooks (Slack, Discord, Teams)
+// and generic JSON endpoints.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/anIcedAntFA/gohome/internal/entity"
+)
+
+// Destination types.
+const (
+	TypeSlack   = "slack"
+	TypeDiscord = "discord"
+	TypeTeams   = "teams"
+	TypeGeneric = "generic"
+)
+
+// discordLimit is the maximum length of a Discord message content.
+const discordLimit = 2000
+
+// slackBlockLimit is the maximum number of blocks of a Slack message.
+const slackBlockLimit = 50
+
+// Default delivery settings.
+const (
+	DefaultTimeout = 10 * time.Second
+	DefaultRetries = 2
+)
+
+// Destination is a named webhook target.
+type Destination struct {
+	Name    string
+	Type    string
+	URL     string
+	Headers map[string]string // Extra request headers
+}
+
+// DefaultFormat returns the report format used when a destination does not pick one.
+func DefaultFormat(destType string) string {
+	if destType == TypeSlack {
+		return "slack-blocks"
+	}
+	return "text"
+}
+
+// Validate checks that the destination can be posted to.
+func (d Destination) Validate() error {
+	switch d.Type {
+	case TypeSlack, TypeDiscord, TypeTeams, TypeGeneric:
+	default:
+		return fmt.Errorf("unknown webhook type %q (use slack, discord, teams or generic)", d.Type)
+	}
+	if !strings.HasPrefix(d.URL, "https://") && !strings.HasPrefix(d.URL, "http://") {
+		return errors.New("webhook url must start with http:// or https://")
+	}
+	return nil
+}
+
+// Payloads builds the JSON bodies to send for a rendered report.
+// format is the report format that produced content. Discord messages longer
+// than the API limit and Slack messages with too many blocks are split into
+// several payloads.
+func Payloads(d Destination, format, content string, report *entity.Report) ([][]byte, error) {
+	switch d.Type {
+	case TypeSlack:
+		if format == "slack-blocks" {
+			// Already a complete Block Kit message
+			return splitBlocks(content)
+		}
+		return marshalAll(map[string]string{"text": content})
+	case TypeDiscord:
+		chunks := splitLines(content, discordLimit)
+		payloads := make([]any, 0, len(chunks))
+		for _, chunk := range chunks {
+			payloads = append(payloads, map[string]string{"content": chunk})
+		}
+		return marshalAll(payloads...)
+	case TypeTeams:
+		return marshalAll(map[string]string{
+			"@type":    "MessageCard",
+			"@context": "http://schema.org/extensions",
+			"summary":  "Work report of " + report.Author,
+			"title":    "Work Report · " + report.Author + " · since " + report.Period,
+			// Teams collapses single newlines in card text
+			"text": strings.ReplaceAll(content, "\n", "\n\n"),
+		})
+	default:
+		return marshalAll(map[string]any{
+			"author":       report.Author,
+			"period":       report.Period,
+			"generated_at": report.GeneratedAt,
+			"format":       format,
+			"text":         content,
+			"report":       report,
+		})
+	}
+}
+
+// marshalAll encodes every value as JSON.
+func marshalAll(values ...any) ([][]byte, error) {
+	payloads := make([][]byte, 0, len(values))
+	for _, v := range values {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		payloads = append(payloads, data)
+	}
+	return payloads, nil
+}
+
+// splitBlocks splits a Block Kit message into messages of at most
+// slackBlockLimit blocks, each with the fallback text of the original.
+// Messages within the limit are sent as they are.
+func splitBlocks(content string) ([][]byte, error) {
+	var msg struct {
+		Text   string            `json:"text"`
+		Blocks []json.RawMessage `json:"blocks"`
+	}
+	if err := json.Unmarshal([]byte(content), &msg); err != nil {
+		return nil, fmt.Errorf("invalid Block Kit message: %w", err)
+	}
+	if len(msg.Blocks) <= slackBlockLimit {
+		return [][]byte{[]byte(content)}, nil
+	}
+
+	var payloads []any
+	for blocks := msg.Blocks; len(blocks) > 0; {
+		n := min(len(blocks), slackBlockLimit)
+		payloads = append(payloads, map[string]any{"text": msg.Text, "blocks": blocks[:n]})
+		blocks = blocks[n:]
+	}
+	return marshalAll(payloads...)
+}
+
+// splitLines splits text into chunks of at most limit bytes, breaking on newlines
+// where possible. Longer lines are cut between runes.
+func splitLines(text string, limit int) []string {
+	var chunks []string
+	var current strings.Builder
+
+	for _, line := range strings.SplitAfter(text, "\n") {
+		for len(line) > limit {
+			if current.Len() > 0 {
+				chunks = append(chunks, current.String())
+				current.Reset()
+			}
+			cut := limit
+			for cut > 0 && !utf8.RuneStart(line[cut]) {
+				cut--
+			}
+			if cut == 0 {
+				cut = limit // The limit is shorter than a rune
+			}
+			chunks = append(chunks, line[:cut])
+			line = line[cut:]
+		}
+		if current.Len()+len(line) > limit {
+			chunks = append(chunks, current.String())
+			current.Reset()
+		}
+		current.WriteString(line)
+	}
+
+	if current.Len() > 0 {
+		chunks = append(chunks, current.String())
+	}
+	return chunks
+}
+
+// Client posts payloads with retries.
+type Client struct {
+	http    *http.Client
+	retries int
+	backoff time.Duration
+}
+
+// NewClient creates a webhook client with the given per-request timeout and
+// number of retries after a failed attempt.
+func NewClient(timeout time.Duration, retries int) *Client {
+	return &Client{
+		http:    &http.Client{Timeout: timeout},
+		retries: retries,
+		backoff: 500 * time.Millisecond,
+	}
+}
+
+// Post sends every payload to the destination in order.
+func (c *Client) Post(ctx context.Context, d Destination, payloads [][]byte) error {
+	for _, payload := range payloads {
+		if err := c.send(ctx, d, payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// send delivers one payload, retrying on network errors, 429 and 5xx responses.
+func (c *Client) send(ctx context.Context, d Destination, payload []byte) error {
+	var lastErr error
+	var delay time.Duration
+
+	for attempt := 0; attempt <= c.retries; attempt++ {
+		if delay > 0 {
+			if err := sleep(ctx, delay); err != nil {
+				return err
+			}
+		}
+
+		retry, wait, err := c.do(ctx, d, payload)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retry {
+			return err
+		}
+
+		// Exponential backoff, unless the server asked for a longer wait
+		delay = c.backoff << attempt
+		if wait > delay {
+			delay = wait
+		}
+	}
+
+	return fmt.Errorf("giving up after %d attempts: %w", c.retries+1, lastErr)
+}
+
+// do performs a single request. It reports whether the error is worth retrying
+// and how long the server asked to wait.
+func (c *Client) do(ctx context.Context, d Destination, payload []byte) (bool, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(payload))
+	if err != nil {
+		return false, 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "gohome")
+	for k, v := range d.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, 0, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return false, 0, nil
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	err = fmt.Errorf("%s responded %s: %s", d.Name, resp.Status, strings.TrimSpace(string(body)))
+
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, retryAfter(resp.Header.Get("Retry-After")), err
+}
+
+// retryAfter parses a Retry-After header in seconds, capped to 30 seconds.
+func retryAfter(value string) time.Duration {
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	if seconds > 30 {
+		seconds = 30
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// sleep waits for d or until the context is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,215 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/anIcedAntFA/gohome/internal/entity"
+	"github.com/anIcedAntFA/gohome/internal/renderer"
+)
+
+func testReport() *entity.Report {
+	return &entity.Report{Author: "tester", Period: "1 day ago"}
+}
+
+func TestPostRetriesOnServerError(t *testing.T) {
+	var calls atomic.Int32
+	var body string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(time.Second, 2)
+	client.backoff = time.Millisecond
+
+	dest := Destination{Name: "team", Type: TypeSlack, URL: server.URL}
+	payloads, err := Payloads(dest, "slack", "hello", testReport())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.Post(context.Background(), dest, payloads); err != nil {
+		t.Fatalf("expected success after retries, got %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls.Load())
+	}
+	if body != `{"text":"hello"}` {
+		t.Errorf("unexpected body %q", body)
+	}
+}
+
+func TestPostDoesNotRetryClientError(t *testing.T) {
+	var calls atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		http.Error(w, "invalid_payload", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	client := NewClient(time.Second, 3)
+	client.backoff = time.Millisecond
+
+	dest := Destination{Name: "team", Type: TypeGeneric, URL: server.URL}
+	err := client.Post(context.Background(), dest, [][]byte{[]byte(`{}`)})
+	if err == nil || !strings.Contains(err.Error(), "invalid_payload") {
+		t.Fatalf("expected error with response body, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("expected a single attempt, got %d", calls.Load())
+	}
+}
+
+func TestPostTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(20*time.Millisecond, 0)
+	dest := Destination{Name: "slow", Type: TypeGeneric, URL: server.URL}
+	if err := client.Post(context.Background(), dest, [][]byte{[]byte(`{}`)}); err == nil {
+		t.Fatal("expected timeout error")
+	}
+}
+
+func TestDiscordPayloadsAreSplit(t *testing.T) {
+	line := strings.Repeat("x", 99) + "\n"
+	content := strings.Repeat(line, 50) // 5000 bytes
+
+	payloads, err := Payloads(Destination{Type: TypeDiscord}, "text", content, testReport())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(payloads) != 3 {
+		t.Fatalf("expected 3 messages, got %d", len(payloads))
+	}
+
+	var joined strings.Builder
+	for _, p := range payloads {
+		var msg map[string]string
+		if err := json.Unmarshal(p, &msg); err != nil {
+			t.Fatal(err)
+		}
+		if len(msg["content"]) > discordLimit {
+			t.Errorf("message exceeds limit: %d", len(msg["content"]))
+		}
+		joined.WriteString(msg["content"])
+	}
+	if joined.String() != content {
+		t.Error("split messages do not add up to the original content")
+	}
+}
+
+func TestSlackBlocksPayloadIsPassedThrough(t *testing.T) {
+	blocks := `{"text":"x","blocks":[]}`
+	payloads, err := Payloads(Destination{Type: TypeSlack}, "slack-blocks", blocks, testReport())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(payloads) != 1 || string(payloads[0]) != blocks {
+		t.Errorf("unexpected payloads %q", payloads)
+	}
+}
+
+func TestSlackBlocksPayloadsAreSplit(t *testing.T) {
+	report := testReport()
+	for i := 0; i < 60; i++ {
+		report.Repos = append(report.Repos, entity.RepoReport{
+			Name:    fmt.Sprintf("repo-%02d", i),
+			Commits: []entity.Commit{{Type: "feat", Message: fmt.Sprintf("change <%d>", i)}},
+		})
+	}
+	var content strings.Builder
+	if err := renderer.NewPrinter(renderer.Config{Format: "slack-blocks"}).Render(&content, report); err != nil {
+		t.Fatal(err)
+	}
+
+	payloads, err := Payloads(Destination{Type: TypeSlack}, "slack-blocks", content.String(), report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(payloads) != 2 {
+		t.Fatalf("expected 2 messages, got %d", len(payloads))
+	}
+
+	type message struct {
+		Text   string            `json:"text"`
+		Blocks []json.RawMessage `json:"blocks"`
+	}
+	var original message
+	if err := json.Unmarshal([]byte(content.String()), &original); err != nil {
+		t.Fatal(err)
+	}
+	var blocks []string
+	for _, p := range payloads {
+		var msg message
+		if err := json.Unmarshal(p, &msg); err != nil {
+			t.Fatal(err)
+		}
+		if len(msg.Blocks) > slackBlockLimit {
+			t.Errorf("message has %d blocks, over the limit", len(msg.Blocks))
+		}
+		if msg.Text != original.Text {
+			t.Errorf("fallback text = %q, want %q", msg.Text, original.Text)
+		}
+		for _, b := range msg.Blocks {
+			var v any
+			if err := json.Unmarshal(b, &v); err != nil {
+				t.Fatal(err)
+			}
+			data, _ := json.Marshal(v)
+			blocks = append(blocks, string(data))
+		}
+	}
+	if len(blocks) != len(original.Blocks) {
+		t.Fatalf("messages have %d blocks, want %d", len(blocks), len(original.Blocks))
+	}
+	for i, b := range original.Blocks {
+		var v any
+		if err := json.Unmarshal(b, &v); err != nil {
+			t.Fatal(err)
+		}
+		if data, _ := json.Marshal(v); string(data) != blocks[i] {
+			t.Errorf("block %d = %s, want %s", i, blocks[i], data)
+		}
+	}
+}
+
+func TestSplitLinesKeepsRunes(t *testing.T) {
+	text := "short\n" + strings.Repeat("é", 10) + "\n" // 20 bytes line
+	chunks := splitLines(text, 7)
+
+	var joined strings.Builder
+	for _, chunk := range chunks {
+		if len(chunk) > 7 {
+			t.Errorf("chunk %q exceeds the limit", chunk)
+		}
+		if !utf8.ValidString(chunk) {
+			t.Errorf("chunk %q is not valid UTF-8", chunk)
+		}
+		joined.WriteString(chunk)
+	}
+	if joined.String() != text {
+		t.Errorf("chunks %q do not add up to the text", chunks)
+	}
+}